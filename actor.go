@@ -0,0 +1,201 @@
+package EventBus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Actor - consumer which processes the events of a set of topics one at a time on its own goroutine.
+// State only touched by the receive function needs no locking.
+type Actor struct {
+	bus      *EventBus
+	receive  func(event Event)
+	mailbox  chan queuedEvent
+	lock     sync.Mutex
+	handlers map[string]*eventHandler
+	stopped  bool           // Stop was called
+	closing  bool           // mailbox is about to be closed, late deliveries are dropped
+	pending  sync.WaitGroup // deliveries currently being enqueued
+	running  sync.WaitGroup // worker goroutines
+	workers  int
+	name     string
+	policy   *SupervisorPolicy // restart policy, nil when a panic in receive is not recovered
+	queued   []time.Time       // enqueue times of the events waiting to be processed, oldest first
+	highest  int
+	scaler   *autoscaler // nil unless Autoscale was called
+}
+
+// queuedEvent - event waiting in an actor's mailbox
+type queuedEvent struct {
+	Event
+	enqueued time.Time
+}
+
+// QueueStats - backlog of an actor: events waiting (including publishers blocked on a full
+// mailbox), the highest backlog seen and the age of the oldest waiting event
+type QueueStats struct {
+	Depth     int
+	HighWater int
+	Lag       time.Duration
+}
+
+// NewActor - create an actor with a mailbox holding up to size events.
+// Publishers block while the mailbox is full.
+func NewActor(bus *EventBus, size int, receive func(event Event)) *Actor {
+	return &Actor{
+		bus:     bus,
+		receive: receive,
+		mailbox: make(chan queuedEvent, size),
+	}
+}
+
+// Supervise restarts the actor's mailbox loop according to policy when receive panics,
+// publishing HandlerRestartedTopic with the given name on every restart. When the actor is
+// given up the remaining events are discarded. Must be called before Start.
+func (actor *Actor) Supervise(name string, policy SupervisorPolicy) *Actor {
+	actor.name = name
+	actor.policy = &policy
+	return actor
+}
+
+// Start subscribes the actor to the topics and starts processing its mailbox.
+// Events are counted by WaitAsync until the actor has processed them.
+func (actor *Actor) Start(topics ...string) error {
+	actor.lock.Lock()
+	defer actor.lock.Unlock()
+	if actor.handlers != nil || actor.stopped {
+		return fmt.Errorf("Actor %w", ErrAlreadyStarted)
+	}
+	actor.handlers = make(map[string]*eventHandler)
+	for _, topic := range topics {
+		topic := topic
+		// transactional so events of a topic enter the mailbox in publish order
+		handler, err := actor.bus.subscribeHandler(topic, func(args ...interface{}) { actor.enqueue(topic, args) }, false, true, true)
+		if err != nil {
+			return err
+		}
+		actor.handlers[topic] = handler
+	}
+	if actor.scaler == nil {
+		actor.startWorker()
+		return nil
+	}
+	for actor.workers < actor.scaler.policy.MinWorkers {
+		actor.startWorker()
+	}
+	go actor.scaler.run(actor)
+	return nil
+}
+
+// Stop unsubscribes the actor and returns once every event published to it before the call is processed.
+// It must not be called from the receive function.
+func (actor *Actor) Stop() {
+	actor.lock.Lock()
+	if actor.stopped || actor.handlers == nil {
+		actor.lock.Unlock()
+		return
+	}
+	actor.stopped = true
+	actor.lock.Unlock()
+	if actor.scaler != nil {
+		actor.scaler.stop()
+	}
+	for topic, handler := range actor.handlers {
+		actor.bus.removeHandlerPtr(topic, handler)
+		// the deliveries queued for the transactional handler enter the mailbox first
+		handler.queue.wait()
+	}
+	actor.lock.Lock()
+	actor.closing = true
+	actor.lock.Unlock()
+	actor.pending.Wait()
+	close(actor.mailbox)
+	actor.running.Wait()
+}
+
+func (actor *Actor) enqueue(topic string, args []interface{}) {
+	actor.lock.Lock()
+	if actor.closing {
+		actor.lock.Unlock()
+		return
+	}
+	actor.pending.Add(1)
+	enqueued := time.Now()
+	actor.queued = append(actor.queued, enqueued)
+	if len(actor.queued) > actor.highest {
+		actor.highest = len(actor.queued)
+	}
+	actor.lock.Unlock()
+	defer actor.pending.Done()
+	actor.bus.wg.Add(1)
+	actor.mailbox <- queuedEvent{Event{Topic: topic, Args: args}, enqueued}
+}
+
+// dequeued forgets the enqueue time of an event taken out of the mailbox
+func (actor *Actor) dequeued(event queuedEvent) {
+	actor.lock.Lock()
+	defer actor.lock.Unlock()
+	for i, enqueued := range actor.queued {
+		if enqueued == event.enqueued {
+			actor.queued = append(actor.queued[:i], actor.queued[i+1:]...)
+			return
+		}
+	}
+}
+
+// QueueStats returns the current backlog of the actor.
+func (actor *Actor) QueueStats() QueueStats {
+	actor.lock.Lock()
+	defer actor.lock.Unlock()
+	stats := QueueStats{Depth: len(actor.queued), HighWater: actor.highest}
+	if len(actor.queued) > 0 {
+		stats.Lag = time.Since(actor.queued[0])
+	}
+	return stats
+}
+
+// startWorker starts one more goroutine processing the mailbox, the actor lock must be held
+func (actor *Actor) startWorker() {
+	actor.workers++
+	actor.running.Add(1)
+	go actor.loop()
+}
+
+func (actor *Actor) loop() {
+	defer actor.running.Done()
+	var shrink chan struct{} // nil, so never ready, unless autoscaled
+	if actor.scaler != nil {
+		shrink = actor.scaler.shrink
+	}
+	work := func() {
+		for {
+			select {
+			case event, ok := <-actor.mailbox:
+				if !ok {
+					return
+				}
+				actor.dequeued(event)
+				actor.process(event.Event)
+			case <-shrink:
+				return
+			}
+		}
+	}
+	if actor.policy == nil {
+		work()
+		return
+	}
+	if !actor.bus.supervise(actor.name, *actor.policy, work) {
+		// given up: keep draining so publishers never block on a dead mailbox
+		for event := range actor.mailbox {
+			actor.dequeued(event)
+			actor.bus.wg.Done()
+		}
+	}
+}
+
+func (actor *Actor) process(event Event) {
+	defer actor.bus.wg.Done()
+	actor.receive(event)
+}
@@ -0,0 +1,63 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActor(t *testing.T) {
+	bus := New().(*EventBus)
+	total := 0
+	var order []string
+	actor := NewActor(bus, 1, func(event Event) {
+		order = append(order, event.Topic)
+		if event.Topic == "add" {
+			total += event.Args[0].(int)
+		}
+	})
+	if err := actor.Start("add", "reset"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 10; i++ {
+		bus.Publish("add", i)
+	}
+	bus.WaitAsync()
+	if total != 55 || len(order) != 10 {
+		t.Fail()
+	}
+
+	bus.Publish("reset")
+	actor.Stop()
+	if len(order) != 11 || order[10] != "reset" {
+		t.Fail()
+	}
+	if bus.HasCallback("add") || bus.HasCallback("reset") {
+		t.Fail()
+	}
+	bus.Publish("add", 1)
+	if total != 55 {
+		t.Fail()
+	}
+}
+
+func TestActorQueueStats(t *testing.T) {
+	bus := New().(*EventBus)
+	release := make(chan bool)
+	actor := NewActor(bus, 4, func(event Event) { <-release })
+	actor.Start("topic")
+	for i := 0; i < 3; i++ {
+		bus.Publish("topic")
+	}
+	time.Sleep(10 * time.Millisecond)
+	stats := actor.QueueStats()
+	// the first event is being processed, two are waiting
+	if stats.Depth != 2 || stats.HighWater < 2 || stats.Lag < 10*time.Millisecond {
+		t.Fatal(stats)
+	}
+	close(release)
+	actor.Stop()
+	stats = actor.QueueStats()
+	if stats.Depth != 0 || stats.Lag != 0 {
+		t.Fatal(stats)
+	}
+}
@@ -0,0 +1,193 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AlertTopic - topic alerts are published to, unless their rule names another
+const AlertTopic = "bus:alert"
+
+// AlertCondition - what an AlertRule watches for
+type AlertCondition int
+
+const (
+	// AlertThreshold - at least Count events within Window
+	AlertThreshold AlertCondition = iota
+	// AlertAbsence - no event for Window, e.g. a missing heartbeat
+	AlertAbsence
+)
+
+// AlertRule - condition over the events of a topic raising an Alert, see Alerting
+type AlertRule struct {
+	Name      string
+	Topic     string
+	Condition AlertCondition
+	Count     int // events raising a threshold alert
+	Window    time.Duration
+	Match     func(args ...interface{}) bool // counts the matching events only, every event when nil
+	Publish   string                         // topic of the alerts, AlertTopic when empty
+}
+
+// Alert - a rule whose condition started or stopped holding
+type Alert struct {
+	Rule      string
+	Topic     string
+	Condition AlertCondition
+	Count     int // events within the window when a threshold alert changed
+	Resolved  bool
+	At        time.Time
+}
+
+// alertState - events seen by a rule and whether it is firing
+type alertState struct {
+	rule    AlertRule
+	bus     *EventBus
+	lock    sync.Mutex
+	times   []time.Time // publish times of the events within the window, threshold rules only
+	firing  bool
+	timer   *time.Timer // checks the window once it moved, nil when idle
+	stopped bool
+}
+
+// Alerting subscribes the rules to their topics. A rule publishes an Alert once its condition
+// holds, and again with Resolved set once it stops holding: a threshold rule when fewer than Count
+// events remain in the window, an absence rule when an event arrives. Absence windows start
+// with Alerting. Events are counted by publish time on a goroutine of their own, so rules may
+// watch the topics published by sync handlers. stop unsubscribes every rule.
+func (bus *EventBus) Alerting(rules ...AlertRule) (stop func(), err error) {
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Topic == "" || rule.Window <= 0 {
+			return nil, errors.New("alert rule without name, topic or window")
+		}
+		if rule.Condition == AlertThreshold && rule.Count <= 0 {
+			return nil, fmt.Errorf("threshold of alert rule %s is not positive", rule.Name)
+		}
+	}
+	states := make(map[*eventHandler]*alertState, len(rules))
+	stop = func() {
+		for handler, state := range states {
+			bus.removeHandlerPtr(state.rule.Topic, handler)
+			state.stop()
+		}
+	}
+	for _, rule := range rules {
+		state := &alertState{rule: rule, bus: bus}
+		handler, err := bus.subscribeHandler(rule.Topic, state.seen, false, true, true)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		states[handler] = state
+		if rule.Condition == AlertAbsence {
+			state.lock.Lock()
+			state.timer = time.AfterFunc(rule.Window, state.check)
+			state.lock.Unlock()
+		}
+	}
+	return stop, nil
+}
+
+// seen counts an event of the rule's topic
+func (state *alertState) seen(meta EventMeta, args ...interface{}) {
+	if state.rule.Match != nil && !state.rule.Match(args...) {
+		return
+	}
+	state.lock.Lock()
+	if state.stopped {
+		state.lock.Unlock()
+		return
+	}
+	var alert *Alert
+	if state.rule.Condition == AlertAbsence {
+		if state.timer != nil {
+			state.timer.Stop()
+		}
+		state.timer = time.AfterFunc(time.Until(meta.Published.Add(state.rule.Window)), state.check)
+		if state.firing {
+			state.firing = false
+			alert = state.alert(0)
+		}
+	} else {
+		state.times = append(state.times, meta.Published)
+		alert = state.evaluate(time.Now())
+	}
+	state.lock.Unlock()
+	state.publish(alert)
+}
+
+// check runs once the window moved: an absence rule fires, a threshold rule may resolve
+func (state *alertState) check() {
+	state.lock.Lock()
+	if state.stopped {
+		state.lock.Unlock()
+		return
+	}
+	state.timer = nil
+	var alert *Alert
+	if state.rule.Condition == AlertAbsence {
+		if !state.firing {
+			state.firing = true
+			alert = state.alert(0)
+		}
+	} else {
+		alert = state.evaluate(time.Now())
+	}
+	state.lock.Unlock()
+	state.publish(alert)
+}
+
+// evaluate drops the events which left the window of a threshold rule and returns the alert
+// raised or resolved, if any. While firing, a check is armed for when the oldest event leaves.
+func (state *alertState) evaluate(now time.Time) *Alert {
+	horizon := now.Add(-state.rule.Window)
+	n := 0
+	for n < len(state.times) && !state.times[n].After(horizon) {
+		n++
+	}
+	state.times = append(state.times[:0], state.times[n:]...)
+
+	var alert *Alert
+	if holds := len(state.times) >= state.rule.Count; holds != state.firing {
+		state.firing = holds
+		alert = state.alert(len(state.times))
+	}
+	if state.firing && state.timer == nil {
+		state.timer = time.AfterFunc(state.times[0].Sub(horizon), state.check)
+	}
+	return alert
+}
+
+func (state *alertState) alert(count int) *Alert {
+	return &Alert{
+		Rule:      state.rule.Name,
+		Topic:     state.rule.Topic,
+		Condition: state.rule.Condition,
+		Count:     count,
+		Resolved:  !state.firing,
+		At:        time.Now(),
+	}
+}
+
+func (state *alertState) publish(alert *Alert) {
+	if alert == nil {
+		return
+	}
+	topic := state.rule.Publish
+	if topic == "" {
+		topic = AlertTopic
+	}
+	state.bus.Publish(topic, *alert)
+}
+
+func (state *alertState) stop() {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	state.stopped = true
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+}
@@ -0,0 +1,102 @@
+package EventBus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// alertLog - alerts received, safe for the timer goroutines publishing them
+type alertLog struct {
+	lock   sync.Mutex
+	alerts []Alert
+}
+
+func (log *alertLog) add(alert Alert) {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	log.alerts = append(log.alerts, alert)
+}
+
+func (log *alertLog) get() []Alert {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	return append([]Alert(nil), log.alerts...)
+}
+
+func TestAlertThreshold(t *testing.T) {
+	bus := New().(*EventBus)
+	log := &alertLog{}
+	bus.Subscribe(AlertTopic, log.add)
+	stop, err := bus.Alerting(AlertRule{
+		Name:   "login-failures",
+		Topic:  "login",
+		Count:  3,
+		Window: 100 * time.Millisecond,
+		Match:  func(args ...interface{}) bool { return args[0] == false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	for _, ok := range []bool{false, true, false, false, false} {
+		bus.Publish("login", ok)
+	}
+	bus.WaitAsync()
+	alerts := log.get()
+	if len(alerts) != 1 || alerts[0].Rule != "login-failures" || alerts[0].Count != 3 || alerts[0].Resolved {
+		t.Fatal(alerts)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	alerts = log.get()
+	if len(alerts) != 2 || !alerts[1].Resolved || alerts[1].Count >= 3 {
+		t.Fatal(alerts)
+	}
+}
+
+func TestAlertAbsence(t *testing.T) {
+	bus := New().(*EventBus)
+	log := &alertLog{}
+	bus.Subscribe("alerts:heartbeat", log.add)
+	stop, err := bus.Alerting(AlertRule{
+		Name:      "worker-down",
+		Topic:     "heartbeat",
+		Condition: AlertAbsence,
+		Window:    50 * time.Millisecond,
+		Publish:   "alerts:heartbeat",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		bus.Publish("heartbeat")
+	}
+	if alerts := log.get(); len(alerts) != 0 {
+		t.Fatal(alerts)
+	}
+	time.Sleep(150 * time.Millisecond)
+	bus.Publish("heartbeat")
+	bus.WaitAsync()
+	alerts := log.get()
+	if len(alerts) != 2 || alerts[0].Resolved || !alerts[1].Resolved || alerts[0].Condition != AlertAbsence {
+		t.Fatal(alerts)
+	}
+
+	stop()
+	time.Sleep(100 * time.Millisecond)
+	if alerts := log.get(); len(alerts) != 2 || bus.HasCallback("heartbeat") {
+		t.Fatal("alerting after stop", alerts)
+	}
+}
+
+func TestAlertingInvalidRule(t *testing.T) {
+	bus := New().(*EventBus)
+	if _, err := bus.Alerting(AlertRule{Name: "r", Topic: "t", Window: time.Second}); err == nil {
+		t.Fatal("threshold rule without count accepted")
+	}
+	if _, err := bus.Alerting(AlertRule{Name: "r", Topic: "t", Condition: AlertAbsence}); err == nil {
+		t.Fatal("rule without window accepted")
+	}
+}
@@ -0,0 +1,515 @@
+package EventBus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AsyncAPIVersion - version of the AsyncAPI specification WriteAsyncAPI writes
+const AsyncAPIVersion = "2.6.0"
+
+// AsyncAPIInfo - info object of an AsyncAPI document, title and version are required
+type AsyncAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// JSONSchema - the subset of JSON Schema describing event payloads in AsyncAPI documents. The
+// payload of a topic with a single argument is the schema of that argument; with several, an
+// array listing them in Tuple.
+type JSONSchema struct {
+	Type        string                 `json:"-"`
+	Nullable    bool                   `json:"-"` // null accepted besides Type
+	Format      string                 `json:"format,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Enum        []interface{}          `json:"enum,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"` // resolved by ImportAsyncAPI
+	// AdditionalProperties - schema of the properties not listed in Properties, e.g. map values
+	AdditionalProperties *JSONSchema   `json:"-"`
+	Closed               bool          `json:"-"` // no properties beyond Properties allowed
+	Items                *JSONSchema   `json:"-"` // schema of every element of an array
+	Tuple                []*JSONSchema `json:"-"` // schemas of the elements of an array by position
+}
+
+// MarshalJSON writes Type and Nullable as type, Items or Tuple as items, and
+// AdditionalProperties or Closed as additionalProperties
+func (schema *JSONSchema) MarshalJSON() ([]byte, error) {
+	type plain JSONSchema
+	wire := struct {
+		Type interface{} `json:"type,omitempty"`
+		*plain
+		Items                interface{} `json:"items,omitempty"`
+		AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	}{plain: (*plain)(schema)}
+	if schema.Type != "" && schema.Nullable {
+		wire.Type = []string{schema.Type, "null"}
+	} else if schema.Type != "" {
+		wire.Type = schema.Type
+	}
+	if schema.Tuple != nil {
+		wire.Items = schema.Tuple
+	} else if schema.Items != nil {
+		wire.Items = schema.Items
+	}
+	if schema.Closed {
+		wire.AdditionalProperties = false
+	} else if schema.AdditionalProperties != nil {
+		wire.AdditionalProperties = schema.AdditionalProperties
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON reads type as Type and Nullable, items as Items or Tuple, and
+// additionalProperties as AdditionalProperties or Closed. A type listing several types besides
+// null accepts any.
+func (schema *JSONSchema) UnmarshalJSON(data []byte) error {
+	type plain JSONSchema
+	wire := struct {
+		Type json.RawMessage `json:"type"`
+		*plain
+		Items                json.RawMessage `json:"items"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}{plain: (*plain)(schema)}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	if types := bytes.TrimSpace(wire.Type); len(types) > 0 && types[0] == '[' {
+		var list []string
+		if err := json.Unmarshal(types, &list); err != nil {
+			return err
+		}
+		for _, t := range list {
+			if t == "null" {
+				schema.Nullable = true
+			} else if schema.Type == "" && len(list) <= 2 {
+				schema.Type = t
+			}
+		}
+	} else if len(types) > 0 {
+		if err := json.Unmarshal(types, &schema.Type); err != nil {
+			return err
+		}
+	}
+	items := bytes.TrimSpace(wire.Items)
+	switch {
+	case len(items) == 0:
+	case items[0] == '[':
+		if err := json.Unmarshal(items, &schema.Tuple); err != nil {
+			return err
+		}
+	default:
+		if err := json.Unmarshal(items, &schema.Items); err != nil {
+			return err
+		}
+	}
+	switch additional := string(bytes.TrimSpace(wire.AdditionalProperties)); additional {
+	case "", "true":
+	case "false":
+		schema.Closed = true
+	default:
+		return json.Unmarshal(wire.AdditionalProperties, &schema.AdditionalProperties)
+	}
+	return nil
+}
+
+// asyncAPIDocument - the parts of an AsyncAPI document the bus writes and reads
+type asyncAPIDocument struct {
+	AsyncAPI   string                      `json:"asyncapi"`
+	Info       AsyncAPIInfo                `json:"info"`
+	Channels   map[string]*asyncAPIChannel `json:"channels"`
+	Components *asyncAPIComponents         `json:"components,omitempty"`
+}
+
+type asyncAPIChannel struct {
+	Description string             `json:"description,omitempty"`
+	Subscribe   *asyncAPIOperation `json:"subscribe,omitempty"`
+	Publish     *asyncAPIOperation `json:"publish,omitempty"`
+}
+
+type asyncAPIOperation struct {
+	Message *asyncAPIMessage `json:"message,omitempty"`
+}
+
+type asyncAPIMessage struct {
+	Ref     string      `json:"$ref,omitempty"`
+	Payload *JSONSchema `json:"payload,omitempty"`
+}
+
+type asyncAPIComponents struct {
+	Schemas  map[string]*JSONSchema      `json:"schemas,omitempty"`
+	Messages map[string]*asyncAPIMessage `json:"messages,omitempty"`
+}
+
+// WriteAsyncAPI writes the declared topics as an AsyncAPI document in JSON, a channel per topic
+// with its description and the schema of its payload, so services in other languages can
+// generate code against the events of the bus. Channels are subscribe operations: the bus
+// publishes the events.
+func (bus *EventBus) WriteAsyncAPI(w io.Writer, info AsyncAPIInfo) error {
+	document := asyncAPIDocument{AsyncAPI: AsyncAPIVersion, Info: info, Channels: make(map[string]*asyncAPIChannel)}
+	for _, topic := range bus.Topics() {
+		if !topic.Declared {
+			continue
+		}
+		payload := topic.Schema
+		if payload == nil {
+			payload = payloadSchema(topic.Args)
+		}
+		document.Channels[topic.Topic] = &asyncAPIChannel{
+			Description: topic.Description,
+			Subscribe:   &asyncAPIOperation{Message: &asyncAPIMessage{Payload: payload}},
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// ImportAsyncAPI declares the channels of an AsyncAPI document in JSON as topics, contract
+// first: each with its description and the payload schema of its messages, references to
+// components resolved. Events published to an imported topic are checked against its schema
+// by ValidateEvent, and by Publish on a bus created WithValidation. Topics declared already are
+// replaced.
+func (bus *EventBus) ImportAsyncAPI(r io.Reader) error {
+	var document asyncAPIDocument
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return fmt.Errorf("asyncapi: %v", err)
+	}
+	if !strings.HasPrefix(document.AsyncAPI, "2.") {
+		return fmt.Errorf("asyncapi: unsupported version %q", document.AsyncAPI)
+	}
+	if document.Components == nil {
+		document.Components = &asyncAPIComponents{}
+	}
+	declarations := make([]TopicDeclaration, 0, len(document.Channels))
+	for topic, channel := range document.Channels {
+		if channel == nil {
+			continue
+		}
+		declaration := TopicDeclaration{Topic: topic, Description: channel.Description, Declared: true}
+		for _, operation := range []*asyncAPIOperation{channel.Subscribe, channel.Publish} {
+			if operation == nil || operation.Message == nil {
+				continue
+			}
+			message, err := document.Components.message(operation.Message)
+			if err != nil {
+				return fmt.Errorf("asyncapi: channel %s: %v", topic, err)
+			}
+			if message.Payload != nil {
+				payload, err := document.Components.resolve(message.Payload, nil)
+				if err != nil {
+					return fmt.Errorf("asyncapi: channel %s: %v", topic, err)
+				}
+				declaration.Schema = payload
+				break
+			}
+		}
+		declarations = append(declarations, declaration)
+	}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.declared == nil {
+		bus.declared = make(map[string]TopicDeclaration)
+	}
+	for _, declaration := range declarations {
+		bus.declared[declaration.Topic] = declaration
+		bus.schemas.Store(declaration.Topic, declaration.Schema)
+	}
+	return nil
+}
+
+// message returns the message a reference points to
+func (components *asyncAPIComponents) message(message *asyncAPIMessage) (*asyncAPIMessage, error) {
+	if message.Ref == "" {
+		return message, nil
+	}
+	name := strings.TrimPrefix(message.Ref, "#/components/messages/")
+	if resolved := components.Messages[name]; name != message.Ref && resolved != nil && resolved.Ref == "" {
+		return resolved, nil
+	}
+	return nil, fmt.Errorf("unresolved message %s", message.Ref)
+}
+
+// resolve returns a copy of schema with its references to component schemas replaced by the
+// schemas. A schema referring to itself, directly or not, accepts anything at the point of
+// recursion.
+func (components *asyncAPIComponents) resolve(schema *JSONSchema, resolving []string) (*JSONSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		target := components.Schemas[name]
+		if name == schema.Ref || target == nil {
+			return nil, fmt.Errorf("unresolved schema %s", schema.Ref)
+		}
+		for _, outer := range resolving {
+			if outer == name {
+				return &JSONSchema{}, nil
+			}
+		}
+		return components.resolve(target, append(resolving, name))
+	}
+	resolved := *schema
+	var err error
+	if schema.Properties != nil {
+		resolved.Properties = make(map[string]*JSONSchema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			if resolved.Properties[name], err = components.resolve(property, resolving); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if resolved.AdditionalProperties, err = components.resolve(schema.AdditionalProperties, resolving); err != nil {
+		return nil, err
+	}
+	if resolved.Items, err = components.resolve(schema.Items, resolving); err != nil {
+		return nil, err
+	}
+	if schema.Tuple != nil {
+		resolved.Tuple = make([]*JSONSchema, len(schema.Tuple))
+		for i, item := range schema.Tuple {
+			if resolved.Tuple[i], err = components.resolve(item, resolving); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &resolved, nil
+}
+
+// ValidateEvent checks args against the payload schema of the topic imported by
+// ImportAsyncAPI, through their JSON encoding. Topics without a schema accept any arguments.
+func (bus *EventBus) ValidateEvent(topic string, args ...interface{}) error {
+	schema, _ := bus.schemas.Load(topic)
+	payload, _ := schema.(*JSONSchema)
+	if payload == nil {
+		return nil
+	}
+	var value interface{} = args
+	if payload.Tuple != nil {
+		if len(args) != len(payload.Tuple) {
+			return fmt.Errorf("topic %s takes %d arguments, %d given", topic, len(payload.Tuple), len(args))
+		}
+	} else if len(args) != 1 {
+		return fmt.Errorf("topic %s takes 1 argument, %d given", topic, len(args))
+	} else {
+		value = args[0]
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("topic %s: %v", topic, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return fmt.Errorf("topic %s: %v", topic, err)
+	}
+	if err := payload.check(decoded, "payload"); err != nil {
+		return fmt.Errorf("topic %s: %v", topic, err)
+	}
+	return nil
+}
+
+// check returns an error locating the first part of the decoded JSON value the schema rejects
+func (schema *JSONSchema) check(value interface{}, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		return fmt.Errorf("%s: %s not in enum", path, jsonText(value))
+	}
+	if value == nil && schema.Nullable {
+		return nil
+	}
+	if schema.Type != "" && jsonType(value) != schema.Type && !(schema.Type == "number" && jsonType(value) == "integer") {
+		return fmt.Errorf("%s: want %s, got %s", path, schema.Type, jsonType(value))
+	}
+	switch value := value.(type) {
+	case []interface{}:
+		for i, element := range value {
+			item := schema.Items
+			if schema.Tuple != nil {
+				if i >= len(schema.Tuple) {
+					break
+				}
+				item = schema.Tuple[i]
+			}
+			if err := item.check(element, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := value[name]; !ok {
+				return fmt.Errorf("%s: missing %s", path, name)
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, listed := schema.Properties[name]
+			switch {
+			case listed:
+			case schema.Closed:
+				return fmt.Errorf("%s: unexpected %s", path, name)
+			default:
+				property = schema.AdditionalProperties
+			}
+			if err := property.check(value[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonType returns the JSON Schema type of a value decoded with UseNumber
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if number, ok := new(big.Float).SetString(value.String()); ok && number.IsInt() {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// inEnum reports whether value is one of the values of an enum, compared as JSON
+func inEnum(enum []interface{}, value interface{}) bool {
+	text := jsonText(value)
+	for _, allowed := range enum {
+		if jsonText(allowed) == text {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonText(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// payloadSchema returns the schema of the payload of events with arguments of the types
+func payloadSchema(args []reflect.Type) *JSONSchema {
+	switch len(args) {
+	case 0:
+		return nil
+	case 1:
+		return schemaOf(args[0], nil)
+	}
+	payload := &JSONSchema{Type: "array", Tuple: make([]*JSONSchema, len(args))}
+	for i, arg := range args {
+		payload.Tuple[i] = schemaOf(arg, nil)
+	}
+	return payload
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaOf returns the schema of the JSON encoding of values of type t, nil pointers, slices and
+// maps encoded as null. Types encoding themselves, interfaces and recursive types accept anything.
+func schemaOf(t reflect.Type, seen []reflect.Type) *JSONSchema {
+	if t == nil {
+		return &JSONSchema{}
+	}
+	if t.Kind() == reflect.Ptr {
+		schema := schemaOf(t.Elem(), seen)
+		schema.Nullable = schema.Type != ""
+		return schema
+	}
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		return &JSONSchema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Format: "byte"}
+		}
+		return &JSONSchema{Type: "array", Nullable: t.Kind() == reflect.Slice, Items: schemaOf(t.Elem(), seen)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", Nullable: true, AdditionalProperties: schemaOf(t.Elem(), seen)}
+	case reflect.Struct:
+		for _, outer := range seen {
+			if outer == t {
+				return &JSONSchema{}
+			}
+		}
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+		addFieldSchemas(schema, t, append(seen, t))
+		return schema
+	}
+	return &JSONSchema{}
+}
+
+// addFieldSchemas adds the exported fields of a struct type to the properties of schema, named
+// and flattened as encoding/json does. Fields without omitempty are required.
+func addFieldSchemas(schema *JSONSchema, t reflect.Type, seen []reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, options = tag[:comma], tag[comma:]
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addFieldSchemas(schema, fieldType, seen)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = schemaOf(field.Type, seen)
+		if !strings.Contains(options, ",omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
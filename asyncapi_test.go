@@ -0,0 +1,155 @@
+package EventBus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type apiOrder struct {
+	ID       string            `json:"id"`
+	Amount   float64           `json:"amount"`
+	Lines    []apiLine         `json:"lines,omitempty"`
+	Placed   time.Time         `json:"placed"`
+	Notes    *string           `json:"notes"`
+	Labels   map[string]string `json:"-"`
+	internal int
+}
+
+type apiLine struct {
+	SKU      string
+	Quantity int
+	Parent   *apiLine
+}
+
+func TestWriteAsyncAPI(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.DeclareTopic("order:placed", "An order was placed.", apiOrder{})
+	bus.DeclareTopic("order:moved", "", "", 0)
+	bus.Subscribe("undeclared", func() {})
+	var b bytes.Buffer
+	if err := bus.WriteAsyncAPI(&b, AsyncAPIInfo{Title: "Orders", Version: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	var document asyncAPIDocument
+	if err := json.Unmarshal(b.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+	if document.AsyncAPI != AsyncAPIVersion || len(document.Channels) != 2 || document.Channels["order:placed"].Description != "An order was placed." {
+		t.Fatal(b.String())
+	}
+	order := document.Channels["order:placed"].Subscribe.Message.Payload
+	if order.Type != "object" || strings.Join(order.Required, ",") != "id,amount,placed,notes" || len(order.Properties) != 5 ||
+		order.Properties["placed"].Format != "date-time" || !order.Properties["notes"].Nullable || !order.Properties["lines"].Nullable {
+		t.Fatal(b.String())
+	}
+	// the recursive field accepts anything
+	if line := order.Properties["lines"].Items; line.Properties["SKU"].Type != "string" || line.Properties["Parent"].Type != "" {
+		t.Fatal(b.String())
+	}
+	moved := document.Channels["order:moved"].Subscribe.Message.Payload
+	if moved.Type != "array" || len(moved.Tuple) != 2 || moved.Tuple[1].Type != "integer" {
+		t.Fatal(b.String())
+	}
+	imported := New().(*EventBus)
+	if err := imported.ImportAsyncAPI(&b); err != nil {
+		t.Fatal(err)
+	}
+	if err := imported.ValidateEvent("order:placed", apiOrder{ID: "o-1", Lines: []apiLine{{SKU: "a", Quantity: 2}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := imported.ValidateEvent("order:moved", "warehouse", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := imported.ValidateEvent("order:moved", 3, "warehouse"); err == nil || !strings.Contains(err.Error(), "payload[0]: want string, got integer") {
+		t.Fatal(err)
+	}
+}
+
+const ordersSpec = `{
+  "asyncapi": "2.6.0",
+  "info": {"title": "Orders", "version": "2.1.0"},
+  "channels": {
+    "order:placed": {
+      "description": "An order was placed.",
+      "publish": {"message": {"$ref": "#/components/messages/OrderPlaced"}}
+    },
+    "order:note": {"subscribe": {"message": {"payload": {"type": ["string", "null"]}}}}
+  },
+  "components": {
+    "messages": {"OrderPlaced": {"payload": {"$ref": "#/components/schemas/Order"}}},
+    "schemas": {
+      "Order": {
+        "type": "object",
+        "required": ["id", "amount"],
+        "additionalProperties": false,
+        "properties": {
+          "id": {"type": "string"},
+          "amount": {"type": "number"},
+          "status": {"enum": ["new", "paid"]},
+          "parent": {"$ref": "#/components/schemas/Order"}
+        }
+      }
+    }
+  }
+}`
+
+func TestImportAsyncAPI(t *testing.T) {
+	bus := NewWithOptions(WithValidation()).(*EventBus)
+	if err := bus.ImportAsyncAPI(strings.NewReader(ordersSpec)); err != nil {
+		t.Fatal(err)
+	}
+	topics := bus.Topics()
+	if len(topics) != 2 || topics[1].Topic != "order:placed" || topics[1].Description != "An order was placed." || topics[1].Schema == nil {
+		t.Fatal(topics)
+	}
+	for _, test := range []struct {
+		event interface{}
+		err   string
+	}{
+		{map[string]interface{}{"id": "o-1", "amount": 3, "status": "paid"}, ""},
+		{map[string]interface{}{"id": "o-1", "amount": 3, "parent": map[string]interface{}{"anything": true}}, ""},
+		{map[string]interface{}{"id": "o-1"}, "payload: missing amount"},
+		{map[string]interface{}{"id": "o-1", "amount": "3"}, "payload.amount: want number, got string"},
+		{map[string]interface{}{"id": "o-1", "amount": 3, "status": "lost"}, `payload.status: "lost" not in enum`},
+		{map[string]interface{}{"id": "o-1", "amount": 3, "by": "me"}, "payload: unexpected by"},
+	} {
+		err := bus.ValidateEvent("order:placed", test.event)
+		if test.err == "" && err != nil || test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Fatal(test.event, err)
+		}
+	}
+	if err := bus.ValidateEvent("order:note", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.ValidateEvent("unknown", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.Subscribe("order:placed", func(order map[string]interface{}) {})
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(error).Error(), "missing amount") {
+			t.Fatal(r)
+		}
+	}()
+	bus.Publish("order:placed", map[string]interface{}{"id": "o-1"})
+}
+
+func TestImportAsyncAPIErrors(t *testing.T) {
+	bus := New().(*EventBus)
+	for _, spec := range []string{
+		`{"asyncapi": "3.0.0", "channels": {}}`,
+		`{"asyncapi": "2.6.0", "channels": {"a": {"publish": {"message": {"$ref": "#/components/messages/Missing"}}}}}`,
+		`{"asyncapi": "2.6.0", "channels": {"a": {"publish": {"message": {"payload": {"$ref": "other.json#/Order"}}}}}}`,
+		`{"asyncapi": 2}`,
+	} {
+		if err := bus.ImportAsyncAPI(strings.NewReader(spec)); err == nil {
+			t.Fatal(spec)
+		}
+	}
+	if len(bus.Topics()) != 0 {
+		t.Fatal(bus.Topics())
+	}
+}
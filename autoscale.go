@@ -0,0 +1,88 @@
+package EventBus
+
+import (
+	"time"
+)
+
+// AutoscalePolicy - bounds and thresholds used to size the worker goroutines of an actor.
+// A worker is added when the backlog reaches ScaleUpDepth or its oldest event is older than
+// ScaleUpLag, and removed when the backlog falls to ScaleDownDepth; keeping ScaleDownDepth
+// well below ScaleUpDepth avoids flapping. At most one worker is added or removed per Interval.
+type AutoscalePolicy struct {
+	MinWorkers     int           // 1 when lower
+	MaxWorkers     int           // MinWorkers when lower
+	ScaleUpDepth   int           // 1 when lower
+	ScaleUpLag     time.Duration // zero to scale on depth only
+	ScaleDownDepth int
+	Interval       time.Duration // a second when zero
+}
+
+// autoscaler - goroutine periodically resizing the workers of an actor
+type autoscaler struct {
+	policy AutoscalePolicy
+	shrink chan struct{} // every value received makes one worker exit
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+// Autoscale lets the actor run between policy.MinWorkers and policy.MaxWorkers goroutines
+// depending on its backlog. Events are then processed concurrently, so receive must be safe
+// for concurrent use and events are no longer handled in publish order. Must be called before Start.
+func (actor *Actor) Autoscale(policy AutoscalePolicy) *Actor {
+	if policy.MinWorkers < 1 {
+		policy.MinWorkers = 1
+	}
+	if policy.MaxWorkers < policy.MinWorkers {
+		policy.MaxWorkers = policy.MinWorkers
+	}
+	if policy.ScaleUpDepth < 1 {
+		policy.ScaleUpDepth = 1
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = time.Second
+	}
+	actor.scaler = &autoscaler{
+		policy: policy,
+		shrink: make(chan struct{}, policy.MaxWorkers),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	return actor
+}
+
+// Workers returns the number of goroutines processing the actor's mailbox.
+func (actor *Actor) Workers() int {
+	actor.lock.Lock()
+	defer actor.lock.Unlock()
+	return actor.workers
+}
+
+func (scaler *autoscaler) run(actor *Actor) {
+	defer close(scaler.done)
+	ticker := time.NewTicker(scaler.policy.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-scaler.quit:
+			return
+		case <-ticker.C:
+		}
+		stats := actor.QueueStats()
+		behind := stats.Depth >= scaler.policy.ScaleUpDepth ||
+			scaler.policy.ScaleUpLag > 0 && stats.Lag >= scaler.policy.ScaleUpLag
+		actor.lock.Lock()
+		switch {
+		case behind && actor.workers < scaler.policy.MaxWorkers:
+			actor.startWorker()
+		case stats.Depth <= scaler.policy.ScaleDownDepth && actor.workers > scaler.policy.MinWorkers:
+			actor.workers--
+			scaler.shrink <- struct{}{}
+		}
+		actor.lock.Unlock()
+	}
+}
+
+func (scaler *autoscaler) stop() {
+	close(scaler.quit)
+	<-scaler.done
+}
@@ -0,0 +1,59 @@
+package EventBus
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAutoscale(t *testing.T) {
+	bus := New().(*EventBus)
+	var busy int32
+	slow := int32(1)
+	actor := NewActor(bus, 100, func(event Event) {
+		atomic.AddInt32(&busy, 1)
+		if atomic.LoadInt32(&slow) == 1 {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}).Autoscale(AutoscalePolicy{
+		MinWorkers:     1,
+		MaxWorkers:     4,
+		ScaleUpDepth:   10,
+		ScaleDownDepth: 0,
+		Interval:       time.Millisecond,
+	})
+	actor.Start("job")
+	for i := 0; i < 60; i++ {
+		bus.Publish("job")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if actor.Workers() < 2 {
+		t.Fatal(actor.Workers())
+	}
+	atomic.StoreInt32(&slow, 0)
+	bus.WaitAsync()
+	time.Sleep(20 * time.Millisecond)
+	if actor.Workers() != 1 {
+		t.Fatal(actor.Workers())
+	}
+	actor.Stop()
+	if atomic.LoadInt32(&busy) != 60 {
+		t.Fail()
+	}
+}
+
+func TestAutoscaleZeroPolicy(t *testing.T) {
+	bus := New().(*EventBus)
+	actor := NewActor(bus, 10, func(event Event) {}).Autoscale(AutoscalePolicy{})
+	if policy := actor.scaler.policy; policy.MinWorkers != 1 || policy.MaxWorkers != 1 ||
+		policy.ScaleUpDepth != 1 || policy.Interval != time.Second {
+		t.Fatal(policy)
+	}
+	actor.Start("job")
+	bus.Publish("job")
+	bus.WaitAsync()
+	if actor.Workers() != 1 {
+		t.Fatal(actor.Workers())
+	}
+	actor.Stop()
+}
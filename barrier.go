@@ -0,0 +1,118 @@
+package EventBus
+
+import (
+	"context"
+	"sync"
+)
+
+// topicProgress - events of a topic not fully processed yet, by publish sequence
+type topicProgress struct {
+	lock      sync.Mutex
+	seq       uint64
+	pending   map[uint64]int // deliveries left per event, the publish itself counting as one
+	waiters   []barrierWaiter
+	reclaimed bool // dropped by GC, its numbering continues in a new entry
+}
+
+// barrierWaiter - Barrier waiting until no event up to seq is pending
+type barrierWaiter struct {
+	seq  uint64
+	done chan struct{}
+}
+
+func (bus *EventBus) progressOf(topic string) *topicProgress {
+	if progress, ok := bus.progress.Load(topic); ok {
+		return progress.(*topicProgress)
+	}
+	bus.seqLock.Lock()
+	defer bus.seqLock.Unlock()
+	progress, _ := bus.progress.LoadOrStore(topic, &topicProgress{seq: bus.seqFloor, pending: make(map[uint64]int)})
+	return progress.(*topicProgress)
+}
+
+// Sequence returns the sequence number of the last event published to the topic, 0 before
+// the first one and once GC reclaimed the topic. Handlers read the number of the event they receive from EventMeta.Seq.
+func (bus *EventBus) Sequence(topic string) uint64 {
+	progress, ok := bus.progress.Load(topic)
+	if !ok {
+		return 0
+	}
+	p := progress.(*topicProgress)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.seq
+}
+
+// begin numbers a new event, pending until its Publish returned. It fails once GC reclaimed
+// the entry, the event is numbered by the new one.
+func (progress *topicProgress) begin() (uint64, bool) {
+	progress.lock.Lock()
+	defer progress.lock.Unlock()
+	if progress.reclaimed {
+		return 0, false
+	}
+	progress.seq++
+	progress.pending[progress.seq] = 1
+	return progress.seq, true
+}
+
+// add counts an async delivery of the event
+func (progress *topicProgress) add(seq uint64) {
+	progress.lock.Lock()
+	defer progress.lock.Unlock()
+	progress.pending[seq]++
+}
+
+// done counts a delivery of the event as finished, releasing the barriers it held
+func (progress *topicProgress) done(seq uint64) {
+	progress.lock.Lock()
+	defer progress.lock.Unlock()
+	if progress.pending[seq]--; progress.pending[seq] > 0 {
+		return
+	}
+	delete(progress.pending, seq)
+	waiters := progress.waiters[:0]
+	for _, waiter := range progress.waiters {
+		if progress.pendingUpTo(waiter.seq) {
+			waiters = append(waiters, waiter)
+		} else {
+			close(waiter.done)
+		}
+	}
+	progress.waiters = waiters
+}
+
+func (progress *topicProgress) pendingUpTo(seq uint64) bool {
+	for pending := range progress.pending {
+		if pending <= seq {
+			return true
+		}
+	}
+	return false
+}
+
+// Barrier returns once every event published to the topic before the call has been processed
+// by all its handlers, async ones included. Events published meanwhile are not waited for.
+// Calling it from a handler of the topic dead locks.
+func (bus *EventBus) Barrier(topic string) error {
+	return bus.BarrierContext(context.Background(), topic)
+}
+
+// BarrierContext is Barrier giving up with the context error when ctx is done first.
+func (bus *EventBus) BarrierContext(ctx context.Context, topic string) error {
+	progress := bus.progressOf(topic)
+	progress.lock.Lock()
+	if !progress.pendingUpTo(progress.seq) {
+		progress.lock.Unlock()
+		return nil
+	}
+	waiter := barrierWaiter{progress.seq, make(chan struct{})}
+	progress.waiters = append(progress.waiters, waiter)
+	progress.lock.Unlock()
+	select {
+	case <-waiter.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package EventBus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBarrier(t *testing.T) {
+	bus := New().(*EventBus)
+	var done int32
+	release := make(chan struct{})
+	bus.SubscribeAsync("topic", func(n int) {
+		<-release
+		atomic.AddInt32(&done, 1)
+	}, false)
+	bus.Publish("topic", 1)
+	bus.Publish("topic", 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if bus.BarrierContext(ctx, "topic") != context.DeadlineExceeded {
+		t.Fail()
+	}
+	if bus.Barrier("other") != nil {
+		t.Fail()
+	}
+	close(release)
+	if bus.Barrier("topic") != nil || atomic.LoadInt32(&done) != 2 {
+		t.Fail()
+	}
+	bus.WaitAsync()
+}
+
+func TestBarrierIgnoresLaterEvents(t *testing.T) {
+	bus := New().(*EventBus)
+	first, later := make(chan struct{}), make(chan struct{})
+	bus.SubscribeAsync("topic", func(wait chan struct{}) { <-wait }, false)
+	bus.Publish("topic", first)
+	result := make(chan error)
+	go func() { result <- bus.Barrier("topic") }()
+	time.Sleep(5 * time.Millisecond)
+	bus.Publish("topic", later)
+	close(first)
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("barrier waited for an event published after it")
+	}
+	close(later)
+	bus.WaitAsync()
+}
@@ -0,0 +1,178 @@
+package EventBus
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The benchmarks below run the same scenarios against the bus and against hand-rolled
+// baselines (raw channels, sync.Map fan-out) so dispatch overhead can be compared:
+//
+//	go test -run XXX -bench . -benchmem
+
+const benchFanOut = 10
+
+func BenchmarkBusPublishSingle(b *testing.B) {
+	bus := New()
+	sum := 0
+	bus.Subscribe("topic", func(a int) { sum += a })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Publish("topic", i)
+	}
+}
+
+func BenchmarkFuncMapPublishSingle(b *testing.B) {
+	var handlers sync.Map
+	sum := 0
+	handlers.Store("topic", []func(int){func(a int) { sum += a }})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fns, _ := handlers.Load("topic")
+		for _, fn := range fns.([]func(int)) {
+			fn(i)
+		}
+	}
+}
+
+func BenchmarkBusPublishFanOut(b *testing.B) {
+	bus := New()
+	sum := 0
+	for j := 0; j < benchFanOut; j++ {
+		bus.Subscribe("topic", func(a int) { sum += a })
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Publish("topic", i)
+	}
+}
+
+func BenchmarkFuncMapPublishFanOut(b *testing.B) {
+	var handlers sync.Map
+	sum := 0
+	fns := make([]func(int), benchFanOut)
+	for j := range fns {
+		fns[j] = func(a int) { sum += a }
+	}
+	handlers.Store("topic", fns)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fns, _ := handlers.Load("topic")
+		for _, fn := range fns.([]func(int)) {
+			fn(i)
+		}
+	}
+}
+
+func BenchmarkBusPublishAsync(b *testing.B) {
+	bus := New()
+	var wg sync.WaitGroup
+	bus.SubscribeAsync("topic", func(a int) { wg.Done() }, false)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		bus.Publish("topic", i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkChannelPublishAsync(b *testing.B) {
+	ch := make(chan int, 128)
+	var wg sync.WaitGroup
+	go func() {
+		for range ch {
+			wg.Done()
+		}
+	}()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		ch <- i
+	}
+	wg.Wait()
+	close(ch)
+}
+
+func BenchmarkBusPublishAsyncFanOut(b *testing.B) {
+	bus := New()
+	var wg sync.WaitGroup
+	for j := 0; j < benchFanOut; j++ {
+		bus.SubscribeAsync("topic", func(a int) { wg.Done() }, false)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(benchFanOut)
+		bus.Publish("topic", i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkChannelPublishAsyncFanOut(b *testing.B) {
+	chs := make([]chan int, benchFanOut)
+	var wg sync.WaitGroup
+	for j := range chs {
+		chs[j] = make(chan int, 128)
+		go func(ch chan int) {
+			for range ch {
+				wg.Done()
+			}
+		}(chs[j])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(benchFanOut)
+		for _, ch := range chs {
+			ch <- i
+		}
+	}
+	wg.Wait()
+	for _, ch := range chs {
+		close(ch)
+	}
+}
+
+func BenchmarkBusPublishParallel(b *testing.B) {
+	bus := New()
+	bus.Subscribe("topic", func(a int) {})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bus.Publish("topic", 1)
+		}
+	})
+}
+
+func BenchmarkChannelPublishParallel(b *testing.B) {
+	ch := make(chan int, 128)
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+		}
+		close(done)
+	}()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ch <- 1
+		}
+	})
+	close(ch)
+	<-done
+}
+
+// publishers of unrelated topics, each handler blocking briefly as on I/O
+func BenchmarkBusPublishParallelTopics(b *testing.B) {
+	bus := New()
+	const topics = 64
+	for j := 0; j < topics; j++ {
+		bus.Subscribe(fmt.Sprint("topic-", j), func(a int) { time.Sleep(time.Microsecond) })
+	}
+	var next int32
+	b.RunParallel(func(pb *testing.PB) {
+		topic := fmt.Sprint("topic-", atomic.AddInt32(&next, 1)%topics)
+		for pb.Next() {
+			bus.Publish(topic, 1)
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package EventBus
+
+import (
+	"context"
+)
+
+// busKey - context key of the bus put by NewContext
+type busKey struct{}
+
+// NewContext returns a copy of ctx carrying bus, so libraries called with it publish to the
+// bus of their caller, see FromContext.
+func NewContext(ctx context.Context, bus *EventBus) context.Context {
+	return context.WithValue(ctx, busKey{}, bus)
+}
+
+// FromContext returns the bus put into ctx by NewContext, false when there is none. Libraries
+// instrumenting their work publish to it, or skip publishing, without global state.
+func FromContext(ctx context.Context) (*EventBus, bool) {
+	bus, ok := ctx.Value(busKey{}).(*EventBus)
+	return bus, ok && bus != nil
+}
@@ -0,0 +1,29 @@
+package EventBus
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("bus found in an empty context")
+	}
+	bus := New().(*EventBus)
+	var got []string
+	bus.Subscribe("lib:query", func(q string) { got = append(got, q) })
+	library := func(ctx context.Context, q string) {
+		if bus, ok := FromContext(ctx); ok {
+			bus.Publish("lib:query", q)
+		}
+	}
+	ctx, cancel := context.WithCancel(NewContext(context.Background(), bus))
+	defer cancel()
+	library(ctx, "select 1")
+	if len(got) != 1 || got[0] != "select 1" {
+		t.Fatal(got)
+	}
+	if _, ok := FromContext(NewContext(ctx, nil)); ok {
+		t.Fatal("nil bus found")
+	}
+}
@@ -0,0 +1,61 @@
+package EventBus
+
+import (
+	"context"
+	"sync"
+)
+
+// CorrelationIDHeader - header identifying the work an event belongs to, see PublishWithHeaders
+const CorrelationIDHeader = "Correlation-ID"
+
+// CancelTopicPrefix - publishing to this prefix followed by a correlation ID cancels the context
+// of every handler processing an event with that correlation ID. The event is then delivered to
+// the subscribers of the topic as any other.
+const CancelTopicPrefix = "cancel:"
+
+// runningSet - cancel functions of the deliveries running, per correlation ID
+type runningSet struct {
+	lock    sync.Mutex
+	cancels map[string]map[*context.CancelFunc]bool
+}
+
+// track gives the delivery of a correlated event its own context, cancelled by a publish to the
+// cancel topic of the correlation ID until done is called
+func (set *runningSet) track(id string, env *envelope) (tracked *envelope, done func()) {
+	ctx, cancel := context.WithCancel(env.ctx)
+	set.lock.Lock()
+	if set.cancels == nil {
+		set.cancels = make(map[string]map[*context.CancelFunc]bool)
+	}
+	if set.cancels[id] == nil {
+		set.cancels[id] = make(map[*context.CancelFunc]bool)
+	}
+	set.cancels[id][&cancel] = true
+	set.lock.Unlock()
+
+	copied := *env
+	copied.ctx = ctx
+	return &copied, func() {
+		set.lock.Lock()
+		if delete(set.cancels[id], &cancel); len(set.cancels[id]) == 0 {
+			delete(set.cancels, id)
+		}
+		set.lock.Unlock()
+		cancel()
+	}
+}
+
+func (set *runningSet) cancel(id string) {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	for cancel := range set.cancels[id] {
+		(*cancel)()
+	}
+}
+
+// CancelCorrelated cancels the context of every handler processing an event with the correlation
+// ID by publishing to its cancel topic. Handlers get the context by declaring a context.Context
+// parameter and stop cooperatively.
+func (bus *EventBus) CancelCorrelated(correlationID string) {
+	bus.Publish(CancelTopicPrefix + correlationID)
+}
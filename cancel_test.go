@@ -0,0 +1,115 @@
+package EventBus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCancelCorrelated(t *testing.T) {
+	bus := New().(*EventBus)
+	started := make(chan struct{}, 2)
+	results := make(chan error, 2)
+	bus.SubscribeAsync("job", func(ctx context.Context, n int) {
+		started <- struct{}{}
+		select {
+		case <-ctx.Done():
+			results <- ctx.Err()
+		case <-time.After(time.Second):
+			results <- nil
+		}
+	}, false)
+	var cancelled []string
+	bus.Subscribe(CancelTopicPrefix+"job-1", func() { cancelled = append(cancelled, "job-1") })
+
+	bus.PublishWithHeaders("job", Headers{CorrelationIDHeader: "job-1"}, 1)
+	bus.PublishWithHeaders("job", Headers{CorrelationIDHeader: "job-2"}, 2)
+	<-started
+	<-started
+	bus.CancelCorrelated("job-1")
+	if err := <-results; err != context.Canceled {
+		t.Fatal(err)
+	}
+	bus.CancelCorrelated("job-2")
+	if err := <-results; err != context.Canceled {
+		t.Fatal(err)
+	}
+	bus.WaitAsync()
+	if len(cancelled) != 1 {
+		t.Fatal(cancelled)
+	}
+	if len(bus.running.cancels) != 0 {
+		t.Fatal(bus.running.cancels)
+	}
+}
+
+func TestPublishWithHeaders(t *testing.T) {
+	bus := New()
+	var got Headers
+	bus.Subscribe("topic", func(meta EventMeta, headers Headers) {
+		if meta.Headers["tenant"] != headers["tenant"] {
+			t.Fail()
+		}
+		got = headers
+	})
+	bus.(*EventBus).PublishWithHeaders("topic", Headers{"tenant": "acme"})
+	if got["tenant"] != "acme" {
+		t.Fatal(got)
+	}
+}
+
+func TestPublishWithContext(t *testing.T) {
+	bus := New().(*EventBus)
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls []string
+	bus.Subscribe("request", func(got context.Context, path string) {
+		if got != ctx {
+			t.Fatal("handler did not get the publish context")
+		}
+		calls = append(calls, "first")
+		cancel()
+	})
+	bus.Subscribe("request", func(path string) { calls = append(calls, "second") })
+	bus.PublishWithContext(ctx, "request", "/")
+	if len(calls) != 1 {
+		t.Fatal(calls)
+	}
+
+	bus.PublishWithContext(ctx, "request", "/")
+	if len(calls) != 1 {
+		t.Fatal("handlers called with a done context", calls)
+	}
+}
+
+func TestPublishWithContextAsync(t *testing.T) {
+	bus := New().(*EventBus)
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	aborted := make(chan error, 1)
+	bus.SubscribeAsync("request", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		aborted <- ctx.Err()
+	}, false)
+	bus.PublishWithContext(ctx, "request")
+	<-started
+	cancel()
+	if err := <-aborted; err != context.Canceled {
+		t.Fatal(err)
+	}
+	bus.WaitAsync()
+}
+
+func TestPublishWithContextSkipsPendingAsync(t *testing.T) {
+	bus := NewWithOptions(WithInlineAsync()).(*EventBus)
+	bus.EnableTrace(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	bus.SubscribeAsync("request", func() { t.Error("async handler started with a done context") }, false)
+	bus.Subscribe("request", cancel)
+	bus.PublishWithContext(ctx, "request")
+	bus.WaitAsync()
+	deliveries := bus.Trace()[0].Deliveries
+	if len(deliveries) != 2 || deliveries[0].Outcome != TraceCancelled {
+		t.Fatal(deliveries)
+	}
+}
@@ -0,0 +1,145 @@
+package EventBus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CausationFormat - graph language written by ExportCausationGraph
+type CausationFormat int
+
+const (
+	// CausationDOT - Graphviz DOT graph
+	CausationDOT CausationFormat = iota
+	// CausationJSON - JSON object listing the events and the edges from causes to effects
+	CausationJSON
+)
+
+// CausationNode - event recorded by RecordCausation
+type CausationNode struct {
+	ID            string    `json:"id"`
+	Topic         string    `json:"topic"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	CausationID   string    `json:"causationId,omitempty"` // ID of the event which triggered this one
+	Published     time.Time `json:"published"`
+}
+
+// causationEdge - cause and effect, by event ID
+type causationEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// causationLog - ring of the last events published with an ID
+type causationLog struct {
+	lock   sync.Mutex
+	events []CausationNode
+	next   int
+	full   bool
+}
+
+func (log *causationLog) add(node CausationNode) {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	log.events[log.next] = node
+	log.next = (log.next + 1) % len(log.events)
+	log.full = log.full || log.next == 0
+}
+
+// snapshot returns the recorded events, oldest first
+func (log *causationLog) snapshot() []CausationNode {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+	if !log.full {
+		return append([]CausationNode(nil), log.events[:log.next]...)
+	}
+	return append(append([]CausationNode(nil), log.events[log.next:]...), log.events[:log.next]...)
+}
+
+// RecordCausation keeps the last `size` events published with an ID, see WithIDGenerator, for
+// ExportCausationGraph. An event was triggered by the one named by its CausationIDHeader, set by
+// Reply or by publishing with the headers returned by CausedBy. A size of zero or less stops
+// recording.
+func (bus *EventBus) RecordCausation(size int) {
+	if size <= 0 {
+		bus.causation.Store((*causationLog)(nil))
+		return
+	}
+	bus.causation.Store(&causationLog{events: make([]CausationNode, size)})
+}
+
+// recordCausation records a published event when RecordCausation was called
+func (bus *EventBus) recordCausation(meta EventMeta) {
+	log, _ := bus.causation.Load().(*causationLog)
+	if log == nil || meta.ID == "" {
+		return
+	}
+	log.add(CausationNode{
+		ID:            meta.ID,
+		Topic:         meta.Topic,
+		CorrelationID: meta.Headers[CorrelationIDHeader],
+		CausationID:   meta.Headers[CausationIDHeader],
+		Published:     meta.Published,
+	})
+}
+
+// CausedBy returns the headers of an event triggered by the one described by ev, which handlers
+// get by declaring an EventMeta parameter: its correlation ID, and its ID as causation ID.
+//
+//	bus.PublishWithHeaders("invoice:created", EventBus.CausedBy(ev), invoice)
+func CausedBy(ev EventMeta) Headers {
+	headers := Headers{}
+	if id := ev.Headers[CorrelationIDHeader]; id != "" {
+		headers[CorrelationIDHeader] = id
+	}
+	if ev.ID != "" {
+		headers[CausationIDHeader] = ev.ID
+	}
+	return headers
+}
+
+// ExportCausationGraph writes the graph of which recorded event triggered which, oldest first.
+// Causes published before the recorded window appear as edges to events unknown to the graph.
+func (bus *EventBus) ExportCausationGraph(w io.Writer, format CausationFormat) error {
+	var nodes []CausationNode
+	if log, _ := bus.causation.Load().(*causationLog); log != nil {
+		nodes = log.snapshot()
+	}
+	edges := make([]causationEdge, 0, len(nodes))
+	for _, node := range nodes {
+		if node.CausationID != "" {
+			edges = append(edges, causationEdge{node.CausationID, node.ID})
+		}
+	}
+	switch format {
+	case CausationDOT:
+		return writeCausationDOT(w, nodes, edges)
+	case CausationJSON:
+		if nodes == nil {
+			nodes = []CausationNode{}
+		}
+		return json.NewEncoder(w).Encode(struct {
+			Nodes []CausationNode `json:"nodes"`
+			Edges []causationEdge `json:"edges"`
+		}{nodes, edges})
+	}
+	return fmt.Errorf("unknown causation format %d", format)
+}
+
+func writeCausationDOT(w io.Writer, nodes []CausationNode, edges []causationEdge) error {
+	var b strings.Builder
+	b.WriteString("digraph causation {\n\trankdir=LR;\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "\t%q [shape=box,label=%q];\n", node.ID, node.Topic+"\n"+node.ID)
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,63 @@
+package EventBus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExportCausationGraph(t *testing.T) {
+	n := 0
+	bus := NewWithOptions(WithIDGenerator(func() string {
+		n++
+		return fmt.Sprintf("ev%d", n)
+	})).(*EventBus)
+	bus.RecordCausation(3)
+	bus.Subscribe("order:placed", func(ev EventMeta) {
+		bus.PublishWithHeaders("invoice:created", CausedBy(ev))
+	})
+	bus.Subscribe("invoice:created", func(ev EventMeta) {
+		bus.PublishWithHeaders("mail:sent", CausedBy(ev))
+	})
+	bus.Publish("ignored")
+	bus.PublishWithHeaders("order:placed", Headers{CorrelationIDHeader: "c1"})
+
+	buf := new(bytes.Buffer)
+	if err := bus.ExportCausationGraph(buf, CausationJSON); err != nil {
+		t.Fatal(err)
+	}
+	var graph struct {
+		Nodes []CausationNode
+		Edges []causationEdge
+	}
+	if err := json.Unmarshal(buf.Bytes(), &graph); err != nil {
+		t.Fatal(err)
+	}
+	// the first event fell out of the window
+	if len(graph.Nodes) != 3 || graph.Nodes[0].ID != "ev2" || graph.Nodes[2].Topic != "mail:sent" ||
+		graph.Nodes[2].CorrelationID != "c1" || graph.Nodes[2].CausationID != "ev3" {
+		t.Fatal(graph.Nodes)
+	}
+	if len(graph.Edges) != 2 || graph.Edges[0] != (causationEdge{"ev2", "ev3"}) || graph.Edges[1] != (causationEdge{"ev3", "ev4"}) {
+		t.Fatal(graph.Edges)
+	}
+
+	buf.Reset()
+	if err := bus.ExportCausationGraph(buf, CausationDOT); err != nil {
+		t.Fatal(err)
+	}
+	if dot := buf.String(); !strings.Contains(dot, `"ev2" -> "ev3";`) || !strings.Contains(dot, `label="order:placed\nev2"`) {
+		t.Fatal(dot)
+	}
+
+	bus.RecordCausation(0)
+	buf.Reset()
+	if err := bus.ExportCausationGraph(buf, CausationJSON); err != nil || buf.String() != "{\"nodes\":[],\"edges\":[]}\n" {
+		t.Fatal(buf.String(), err)
+	}
+	if bus.ExportCausationGraph(buf, CausationFormat(42)) == nil {
+		t.Fail()
+	}
+}
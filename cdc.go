@@ -0,0 +1,225 @@
+package EventBus
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ChangeFeedErrorTopic - topic a ChangeFeed publishes the errors of its source to, it retries after its poll interval
+const ChangeFeedErrorTopic = "bus:change_feed_error"
+
+// ChangeOp - kind of a row change
+type ChangeOp string
+
+const (
+	// ChangeInsert - row inserted
+	ChangeInsert ChangeOp = "insert"
+	// ChangeUpdate - row updated
+	ChangeUpdate ChangeOp = "update"
+	// ChangeDelete - row deleted
+	ChangeDelete ChangeOp = "delete"
+	// ChangeUpsert - row inserted or updated, from sources which cannot tell them apart
+	ChangeUpsert ChangeOp = "upsert"
+)
+
+// RowChange - committed change of a database row, published by a ChangeFeed
+type RowChange struct {
+	Table    string
+	Op       ChangeOp
+	Before   map[string]interface{} // row before the change, when the source reports it
+	After    map[string]interface{} // row after the change, nil for deletes
+	Position string                 // place of the change in the source, resuming after it skips the change
+}
+
+// ChangeSource - committed row changes in commit order, e.g. Postgres logical decoding or the
+// MySQL binlog read through a client library, or a polled table, see NewQuerySource
+type ChangeSource interface {
+	// Changes returns the changes committed after position, the start of the source when empty.
+	// It may wait for changes until ctx is done.
+	Changes(ctx context.Context, position string) ([]RowChange, error)
+}
+
+// ChangeFeed - publishes the changes read from a ChangeSource to the bus, in order
+type ChangeFeed struct {
+	bus      *EventBus
+	source   ChangeSource
+	interval time.Duration
+	topic    func(change RowChange) string
+	lock     sync.Mutex
+	position string
+	cancel   context.CancelFunc // stops reading, nil until started
+	done     chan struct{}
+}
+
+// ChangeFeedOption - setting of a feed created by NewChangeFeed
+type ChangeFeedOption func(feed *ChangeFeed)
+
+// WithChangeTopic publishes every change to the topic returned by topic, "cdc:<table>" by default
+func WithChangeTopic(topic func(change RowChange) string) ChangeFeedOption {
+	return func(feed *ChangeFeed) {
+		feed.topic = topic
+	}
+}
+
+// WithChangePosition resumes reading after position, e.g. the Position saved by a previous run
+func WithChangePosition(position string) ChangeFeedOption {
+	return func(feed *ChangeFeed) {
+		feed.position = position
+	}
+}
+
+// WithPollInterval sets how long the feed waits after reading no change or failing, a second by default
+func WithPollInterval(interval time.Duration) ChangeFeedOption {
+	return func(feed *ChangeFeed) {
+		feed.interval = interval
+	}
+}
+
+// NewChangeFeed returns a feed publishing the changes of source as RowChange arguments, once started.
+// Every change is published synchronously, so the handlers of a change have returned before the
+// next one is published and before Position moves past it.
+func (bus *EventBus) NewChangeFeed(source ChangeSource, opts ...ChangeFeedOption) *ChangeFeed {
+	feed := &ChangeFeed{
+		bus:      bus,
+		source:   source,
+		interval: time.Second,
+		topic:    func(change RowChange) string { return "cdc:" + change.Table },
+	}
+	for _, opt := range opts {
+		opt(feed)
+	}
+	return feed
+}
+
+// Start reads the source on a goroutine of its own until Stop or Close is called
+func (feed *ChangeFeed) Start() error {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	if feed.cancel != nil {
+		return fmt.Errorf("Change feed %w", ErrAlreadyStarted)
+	}
+	bus := feed.bus
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if atomic.LoadInt32(&bus.closed) != 0 {
+		return ErrBusClosed
+	}
+	if bus.feeds == nil {
+		bus.feeds = make(map[*ChangeFeed]bool)
+	}
+	bus.feeds[feed] = true
+	var ctx context.Context
+	ctx, feed.cancel = context.WithCancel(context.Background())
+	feed.done = make(chan struct{})
+	go feed.run(ctx)
+	return nil
+}
+
+// Stop stops reading and waits until the change being published, if any, was handled
+func (feed *ChangeFeed) Stop() {
+	feed.lock.Lock()
+	cancel, done := feed.cancel, feed.done
+	feed.lock.Unlock()
+	if cancel == nil {
+		return
+	}
+	feed.bus.lock.Lock()
+	delete(feed.bus.feeds, feed)
+	feed.bus.lock.Unlock()
+	cancel()
+	<-done
+}
+
+// Position returns the position of the last change published, to resume from with WithChangePosition
+func (feed *ChangeFeed) Position() string {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	return feed.position
+}
+
+func (feed *ChangeFeed) run(ctx context.Context) {
+	defer close(feed.done)
+	for {
+		changes, err := feed.source.Changes(ctx, feed.Position())
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			feed.bus.Publish(ChangeFeedErrorTopic, err)
+		}
+		for _, change := range changes {
+			feed.bus.Publish(feed.topic(change), change)
+			feed.lock.Lock()
+			feed.position = change.Position
+			feed.lock.Unlock()
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		if len(changes) > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(feed.interval):
+		}
+	}
+}
+
+// querySource - ChangeSource polling a table for rows with a newer position
+type querySource struct {
+	db             *sql.DB
+	table          string
+	query          string
+	positionColumn string
+}
+
+// NewQuerySource returns a ChangeSource polling a table with query, which takes the last position
+// as its single parameter and selects the rows changed after it, ordered by positionColumn. The
+// table needs a column increasing with every change, e.g. a version bumped by a trigger. The
+// position is passed as a string, empty before the first change, so cast it in the query where
+// needed. Changes are reported as ChangeUpsert, deletes are not seen.
+func NewQuerySource(db *sql.DB, table, query, positionColumn string) ChangeSource {
+	return &querySource{db, table, query, positionColumn}
+}
+
+func (source *querySource) Changes(ctx context.Context, position string) ([]RowChange, error) {
+	rows, err := source.db.QueryContext(ctx, source.query, position)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var changes []RowChange
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return changes, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		pos, ok := row[source.positionColumn]
+		if !ok {
+			return changes, fmt.Errorf("query of table %s selects no %s column", source.table, source.positionColumn)
+		}
+		changes = append(changes, RowChange{Table: source.table, Op: ChangeUpsert, After: row, Position: fmt.Sprint(pos)})
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,131 @@
+package EventBus
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// sliceSource - ChangeSource reading a fixed list of changes, failing once first
+type sliceSource struct {
+	changes []RowChange
+	failed  bool
+}
+
+func (source *sliceSource) Changes(ctx context.Context, position string) ([]RowChange, error) {
+	if !source.failed {
+		source.failed = true
+		return nil, errors.New("connection reset")
+	}
+	for i, change := range source.changes {
+		if change.Position > position {
+			return source.changes[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+func TestChangeFeed(t *testing.T) {
+	bus := New().(*EventBus)
+	source := &sliceSource{changes: []RowChange{
+		{Table: "orders", Op: ChangeInsert, Position: "1"},
+		{Table: "users", Op: ChangeDelete, Position: "2"},
+		{Table: "orders", Op: ChangeUpdate, Position: "3"},
+	}}
+	received := make(chan RowChange, 3)
+	bus.Subscribe("cdc:orders", func(change RowChange) { received <- change })
+	bus.Subscribe("users:delete", func(change RowChange) { received <- change })
+	failures := make(chan error, 1)
+	bus.Subscribe(ChangeFeedErrorTopic, func(err error) { failures <- err })
+
+	feed := bus.NewChangeFeed(source, WithPollInterval(time.Millisecond), WithChangePosition("1"), WithChangeTopic(func(change RowChange) string {
+		if change.Table == "users" {
+			return change.Table + ":" + string(change.Op)
+		}
+		return "cdc:" + change.Table
+	}))
+	if err := feed.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-failures; err.Error() != "connection reset" {
+		t.Fatal(err)
+	}
+	for _, position := range []string{"2", "3"} {
+		select {
+		case change := <-received:
+			if change.Position != position {
+				t.Fatal(change)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("change not published")
+		}
+	}
+	bus.Close()
+	if feed.Position() != "3" || len(bus.feeds) != 0 {
+		t.Fatal(feed.Position())
+	}
+}
+
+func init() {
+	// once per test binary, registering a driver twice panics when tests run again with -count
+	sql.Register("eventbus_rows", rowsDriver{})
+}
+
+func TestQuerySource(t *testing.T) {
+	db, err := sql.Open("eventbus_rows", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	source := NewQuerySource(db, "orders", "SELECT id, version FROM orders WHERE version > ?", "version")
+	changes, err := source.Changes(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Position != "2" || changes[1].After["id"] != "order-3" || changes[1].Op != ChangeUpsert {
+		t.Fatal(changes)
+	}
+}
+
+// rowsDriver - database/sql driver whose queries return the rows of versions 1 to 3 newer than their argument
+type rowsDriver struct{}
+
+func (rowsDriver) Open(name string) (driver.Conn, error) { return rowsConn{}, nil }
+
+type rowsConn struct{}
+
+func (rowsConn) Prepare(query string) (driver.Stmt, error) { return rowsStmt{}, nil }
+func (rowsConn) Close() error                              { return nil }
+func (rowsConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type rowsStmt struct{}
+
+func (rowsStmt) Close() error  { return nil }
+func (rowsStmt) NumInput() int { return 1 }
+func (rowsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (rowsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	after, _ := strconv.Atoi(args[0].(string))
+	return &versionRows{next: after + 1}, nil
+}
+
+type versionRows struct {
+	next int
+}
+
+func (rows *versionRows) Columns() []string { return []string{"id", "version"} }
+func (rows *versionRows) Close() error      { return nil }
+func (rows *versionRows) Next(dest []driver.Value) error {
+	if rows.next > 3 {
+		return io.EOF
+	}
+	dest[0], dest[1] = []byte("order-"+strconv.Itoa(rows.next)), int64(rows.next)
+	rows.next++
+	return nil
+}
@@ -0,0 +1,80 @@
+package EventBus
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ChaosConfig - misbehavior injected by a ChaosBus, rates are probabilities in [0, 1]
+type ChaosConfig struct {
+	Latency       time.Duration // maximum random delay added before each delivery
+	DropRate      float64       // probability an event is silently dropped
+	DuplicateRate float64       // probability an event is delivered twice
+	ReorderRate   float64       // probability an event is held back and delivered after the next one
+	Seed          int64         // seed of the random source, so failing runs can be reproduced
+}
+
+type heldEvent struct {
+	topic string
+	args  []interface{}
+}
+
+// ChaosBus - bus wrapper for tests which delays, reorders, duplicates and drops published events
+// so handlers can be checked against a misbehaving transport
+type ChaosBus struct {
+	Bus
+	config ChaosConfig
+	lock   sync.Mutex
+	rand   *rand.Rand
+	held   *heldEvent
+}
+
+// NewChaosBus - wrap bus with the fault injection described by config
+func NewChaosBus(bus Bus, config ChaosConfig) *ChaosBus {
+	return &ChaosBus{Bus: bus, config: config, rand: rand.New(rand.NewSource(config.Seed))}
+}
+
+// Publish passes the event to the wrapped bus, subject to the configured faults.
+func (chaos *ChaosBus) Publish(topic string, args ...interface{}) {
+	chaos.lock.Lock()
+	if chaos.rand.Float64() < chaos.config.DropRate {
+		chaos.lock.Unlock()
+		return
+	}
+	if chaos.held == nil && chaos.rand.Float64() < chaos.config.ReorderRate {
+		chaos.held = &heldEvent{topic, args}
+		chaos.lock.Unlock()
+		return
+	}
+	times := 1
+	if chaos.rand.Float64() < chaos.config.DuplicateRate {
+		times = 2
+	}
+	var delay time.Duration
+	if chaos.config.Latency > 0 {
+		delay = time.Duration(chaos.rand.Int63n(int64(chaos.config.Latency)))
+	}
+	held := chaos.held
+	chaos.held = nil
+	chaos.lock.Unlock()
+
+	time.Sleep(delay)
+	for i := 0; i < times; i++ {
+		chaos.Bus.Publish(topic, args...)
+	}
+	if held != nil {
+		chaos.Bus.Publish(held.topic, held.args...)
+	}
+}
+
+// Flush delivers an event still held back for reordering.
+func (chaos *ChaosBus) Flush() {
+	chaos.lock.Lock()
+	held := chaos.held
+	chaos.held = nil
+	chaos.lock.Unlock()
+	if held != nil {
+		chaos.Bus.Publish(held.topic, held.args...)
+	}
+}
@@ -0,0 +1,52 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestChaosPassThrough(t *testing.T) {
+	var got []int
+	chaos := NewChaosBus(New(), ChaosConfig{})
+	chaos.Subscribe("topic", func(a int) { got = append(got, a) })
+	for i := 0; i < 10; i++ {
+		chaos.Publish("topic", i)
+	}
+	for i, a := range got {
+		if a != i {
+			t.Fail()
+		}
+	}
+	if len(got) != 10 {
+		t.Fail()
+	}
+}
+
+func TestChaosDropDuplicate(t *testing.T) {
+	count := 0
+	chaos := NewChaosBus(New(), ChaosConfig{DropRate: 1})
+	chaos.Subscribe("topic", func() { count++ })
+	chaos.Publish("topic")
+	if count != 0 {
+		t.Fail()
+	}
+
+	chaos = NewChaosBus(New(), ChaosConfig{DuplicateRate: 1})
+	chaos.Subscribe("topic", func() { count++ })
+	chaos.Publish("topic")
+	if count != 2 {
+		t.Fail()
+	}
+}
+
+func TestChaosReorder(t *testing.T) {
+	var got []int
+	chaos := NewChaosBus(New(), ChaosConfig{ReorderRate: 1})
+	chaos.Subscribe("topic", func(a int) { got = append(got, a) })
+	chaos.Publish("topic", 1)
+	chaos.Publish("topic", 2)
+	chaos.Publish("topic", 3)
+	chaos.Flush()
+	if len(got) != 3 || got[0] != 2 || got[1] != 1 || got[2] != 3 {
+		t.Fatal(got)
+	}
+}
@@ -0,0 +1,170 @@
+package EventBus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrBlobNotFound - the blob store holds nothing under the reference
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore - storage of the payloads sent by reference instead of inline, see Server.SetClaimCheck.
+// Blobs are never deleted by the bus, expiring them is up to the store.
+type BlobStore interface {
+	Put(data []byte) (ref string, err error)
+	Get(ref string) ([]byte, error)
+}
+
+// claimedArgs - gob encoded content of a blob, the arguments of an event
+type claimedArgs struct {
+	Args []interface{}
+}
+
+// memoryBlobStore - BlobStore keeping blobs in memory by content hash
+type memoryBlobStore struct {
+	lock  sync.Mutex
+	blobs map[string][]byte
+}
+
+// NewMemoryBlobStore returns a BlobStore keeping blobs in memory, for a server and clients
+// sharing a process, e.g. in tests.
+func NewMemoryBlobStore() BlobStore {
+	return &memoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (store *memoryBlobStore) Put(data []byte) (string, error) {
+	ref := blobRef(data)
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.blobs[ref] = data
+	return ref, nil
+}
+
+func (store *memoryBlobStore) Get(ref string) ([]byte, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	data, ok := store.blobs[ref]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, ref)
+	}
+	return data, nil
+}
+
+// fileBlobStore - BlobStore keeping a file per blob, named after its content hash
+type fileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore returns a BlobStore keeping blobs in files of dir, e.g. on a volume shared
+// by the server and its clients. The same payload is stored once.
+func NewFileBlobStore(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &fileBlobStore{dir}, nil
+}
+
+func (store *fileBlobStore) Put(data []byte) (string, error) {
+	ref := blobRef(data)
+	path := filepath.Join(store.dir, ref)
+	if _, err := os.Stat(path); err == nil {
+		return ref, nil
+	}
+	// written aside and renamed, so a reader never sees a partial blob
+	tmp, err := ioutil.TempFile(store.dir, ref+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+	return ref, os.Rename(tmp.Name(), path)
+}
+
+func (store *fileBlobStore) Get(ref string) ([]byte, error) {
+	if filepath.Base(ref) != ref {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, ref)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(store.dir, ref))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, ref)
+	}
+	return data, err
+}
+
+func blobRef(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetClaimCheck - send the events whose gob encoded arguments exceed threshold bytes by
+// reference: the arguments are put in store and clients fetch them on delivery, so outboxes,
+// spools and wire frames stay small. Clients need the same store, see Client.SetBlobStore.
+// A nil store sends every event inline again.
+func (server *Server) SetClaimCheck(store BlobStore, threshold int) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.blobs, server.blobThreshold = store, threshold
+}
+
+// claimCheck replaces the arguments of a large event by a reference to a blob holding them.
+// Events whose arguments can not be encoded or stored are sent inline.
+func (server *Server) claimCheck(arg *ClientArg) {
+	server.lock.Lock()
+	store, threshold := server.blobs, server.blobThreshold
+	server.lock.Unlock()
+	if store == nil || len(arg.Args) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&claimedArgs{arg.Args}); err != nil || buf.Len() <= threshold {
+		return
+	}
+	if ref, err := store.Put(buf.Bytes()); err == nil {
+		arg.Args, arg.Ref = nil, ref
+	}
+}
+
+// SetBlobStore - store the arguments of events sent by reference are fetched from, see Server.SetClaimCheck
+func (client *Client) SetBlobStore(store BlobStore) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	client.blobs = store
+}
+
+// claim fetches the arguments of an event sent by reference
+func (client *Client) claim(arg *ClientArg) error {
+	if arg.Ref == "" {
+		return nil
+	}
+	client.lock.Lock()
+	store := client.blobs
+	client.lock.Unlock()
+	if store == nil {
+		return fmt.Errorf("event of topic %s sent by reference, the client has no blob store", arg.Topic)
+	}
+	data, err := store.Get(arg.Ref)
+	if err != nil {
+		return err
+	}
+	claimed := new(claimedArgs)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(claimed); err != nil {
+		return err
+	}
+	arg.Args, arg.Ref = claimed.Args, ""
+	return nil
+}
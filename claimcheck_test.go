@@ -0,0 +1,72 @@
+package EventBus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaimCheck(t *testing.T) {
+	store := NewMemoryBlobStore()
+	server := NewServer(":2070", "/_server_bus_claim", New())
+	server.SetClaimCheck(store, 100)
+	server.SetRetention(10)
+	client := NewClient("localhost:2075", "/_client_bus_claim", New())
+	client.SetBlobStore(store)
+	client.Start()
+	defer client.Stop()
+
+	received := make(chan string, 2)
+	client.EventBus().Subscribe("topic", func(s string) { received <- s })
+	server.service.Register(&SubscribeArg{client.address, client.path, PublishService, Subscribe, "topic"}, new(bool))
+	large := strings.Repeat("x", 1000)
+	server.EventBus().Publish("topic", "small")
+	server.EventBus().Publish("topic", large)
+	for _, expected := range []string{"small", large} {
+		select {
+		case s := <-received:
+			if s != expected {
+				t.Fatal(len(s))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not received")
+		}
+	}
+
+	retained := server.retained["topic"]
+	if len(retained) != 2 || retained[0].Ref != "" || retained[1].Ref == "" || retained[1].Args != nil {
+		t.Fatal(retained)
+	}
+}
+
+func TestClaimWithoutBlobStore(t *testing.T) {
+	client := NewClient(":2080", "/_client_bus_no_blobs", New())
+	if err := client.service.PushEvent(&ClientArg{Topic: "topic", Ref: "ref"}, new(bool)); err == nil {
+		t.Fatal("event sent by reference published without a blob store")
+	}
+	client.SetBlobStore(NewMemoryBlobStore())
+	if err := client.service.PushEvent(&ClientArg{Topic: "topic", Ref: "ref"}, new(bool)); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatal(err)
+	}
+}
+
+func TestFileBlobStore(t *testing.T) {
+	store, err := NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := store.Put([]byte("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := store.Put([]byte("payload")); again != ref {
+		t.Fatal(again, ref)
+	}
+	if data, err := store.Get(ref); err != nil || string(data) != "payload" {
+		t.Fatal(string(data), err)
+	}
+	if _, err := store.Get("../" + ref); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatal(err)
+	}
+}
@@ -1,7 +1,6 @@
 package EventBus
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,8 +15,11 @@ const (
 
 // ClientArg - object containing event for client to publish locally
 type ClientArg struct {
-	Args  []interface{}
-	Topic string
+	Args    []interface{}
+	Topic   string
+	Seq     uint64  // sequence number of the event in its topic on the server, see EventMeta.Seq
+	Ref     string  // blob holding Args when sent by reference, see Server.SetClaimCheck
+	Headers Headers // headers of the event, e.g. its correlation ID and reply topic
 }
 
 // Client - object capable of subscribing to a remote event bus
@@ -26,6 +28,14 @@ type Client struct {
 	address  string
 	path     string
 	service  *ClientService
+	lock     sync.Mutex
+	servers  map[string]remoteServer       // server each topic was subscribed at, see Subscribe
+	seqs     map[string]uint64             // sequence number of the last event received per topic
+	blobs    BlobStore                     // arguments of events sent by reference, see SetBlobStore
+	conns    map[remoteServer]*serverConn  // servers subscribed at, see SetReconnectPolicy
+	policy   ReconnectPolicy               // how the servers are watched and connected again
+	onChange func(change ConnectionChange) // see OnConnectionStateChange
+	done     chan struct{}                 // closed by Stop to stop watching the servers
 }
 
 // NewClient - create a client object with the address and server path
@@ -35,6 +45,11 @@ func NewClient(address, path string, eventBus Bus) *Client {
 	client.address = address
 	client.path = path
 	client.service = &ClientService{client, &sync.WaitGroup{}, false}
+	client.servers = make(map[string]remoteServer)
+	client.seqs = make(map[string]uint64)
+	client.conns = make(map[remoteServer]*serverConn)
+	client.policy = DefaultReconnectPolicy
+	client.done = make(chan struct{})
 	return client
 }
 
@@ -44,34 +59,32 @@ func (client *Client) EventBus() Bus {
 }
 
 func (client *Client) doSubscribe(topic string, fn interface{}, serverAddr, serverPath string, subscribeType SubscribeType) {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Println("Server not found -", r)
-		}
-	}()
-
-	rpcClient, err := rpc.DialHTTPPath("tcp", serverAddr, serverPath)
-	defer rpcClient.Close()
-	if err != nil {
-		fmt.Errorf("dialing: %v", err)
-	}
+	server := remoteServer{serverAddr, serverPath}
 	args := &SubscribeArg{client.address, client.path, PublishService, subscribeType, topic}
-	reply := new(bool)
-	err = rpcClient.Call(RegisterService, args, reply)
-	if err != nil {
-		fmt.Errorf("Register error: %v", err)
-	}
-	if *reply {
+	if subscribeType == Subscribe {
+		client.lock.Lock()
+		client.servers[topic] = server
+		client.lock.Unlock()
+		client.connect(server, args)
 		client.eventBus.Subscribe(topic, fn)
+		return
+	}
+	if err := server.register(args); err != nil {
+		fmt.Println("Server not found -", err)
+		return
 	}
+	client.eventBus.Subscribe(topic, fn)
 }
 
-//Subscribe subscribes to a topic in a remote event bus
+// Subscribe subscribes to a topic in a remote event bus, events missed meanwhile are requested
+// again from the server and reported on GapTopic when it no longer retains them. The subscription
+// is registered again whenever the server comes back from a failure or a restart, see
+// SetReconnectPolicy, so it holds even when the server is unreachable at first.
 func (client *Client) Subscribe(topic string, fn interface{}, serverAddr, serverPath string) {
 	client.doSubscribe(topic, fn, serverAddr, serverPath, Subscribe)
 }
 
-//SubscribeOnce subscribes once to a topic in a remote event bus
+// SubscribeOnce subscribes once to a topic in a remote event bus
 func (client *Client) SubscribeOnce(topic string, fn interface{}, serverAddr, serverPath string) {
 	client.doSubscribe(topic, fn, serverAddr, serverPath, SubscribeOnce)
 }
@@ -88,16 +101,19 @@ func (client *Client) Start() error {
 		if err == nil {
 			service.wg.Add(1)
 			service.started = true
-			go http.Serve(l, nil)	
-		}	
+			go http.Serve(l, nil)
+		}
 	} else {
-		err = errors.New("Client service already started")
+		err = fmt.Errorf("Client service %w", ErrAlreadyStarted)
 	}
 	return err
 }
 
-// Stop - signal for the service to stop serving
+// Stop - signal for the service to stop serving, and stop watching the servers subscribed at
 func (client *Client) Stop() {
+	client.lock.Lock()
+	client.stopWatching()
+	client.lock.Unlock()
 	service := client.service
 	if service.started {
 		service.wg.Done()
@@ -114,7 +130,22 @@ type ClientService struct {
 
 // PushEvent - exported service to listening to remote events
 func (service *ClientService) PushEvent(arg *ClientArg, reply *bool) error {
-	service.client.eventBus.Publish(arg.Topic, arg.Args...)
+	if err := service.client.claim(arg); err != nil {
+		return err
+	}
+	service.client.resync(arg)
+	service.client.publish(arg)
 	*reply = true
 	return nil
 }
+
+// publish publishes a remote event to the local bus, with its headers when the bus takes them
+func (client *Client) publish(arg *ClientArg) {
+	if bus, ok := client.eventBus.(interface {
+		PublishWithHeaders(topic string, headers Headers, args ...interface{})
+	}); ok && len(arg.Headers) > 0 {
+		bus.PublishWithHeaders(arg.Topic, arg.Headers, arg.Args...)
+		return
+	}
+	client.eventBus.Publish(arg.Topic, arg.Args...)
+}
@@ -0,0 +1,107 @@
+package EventBus
+
+import (
+	"reflect"
+)
+
+// WithArgCloner gives every async handler its own copy of the published arguments, made by
+// cloner, so handlers mutating a shared payload do not race with each other or the publisher.
+func WithArgCloner(cloner func(arg interface{}) interface{}) Option {
+	return func(bus *EventBus) {
+		bus.cloner = cloner
+	}
+}
+
+// WithDeepCopy gives every async handler a DeepCopy of the published arguments.
+func WithDeepCopy() Option {
+	return WithArgCloner(DeepCopy)
+}
+
+// DeepCopy returns a copy of arg where slices, maps, pointers and arrays, also inside
+// structs, are copied recursively. References shared within arg stay shared in the copy, so
+// cyclic values are copied too. Unexported struct fields, channels and functions are shared
+// with the original.
+func DeepCopy(arg interface{}) interface{} {
+	if arg == nil {
+		return nil
+	}
+	return make(copier).copy(reflect.ValueOf(arg)).Interface()
+}
+
+// copyKey - identity of a reference already copied, slices also differ by type and length
+type copyKey struct {
+	typ     reflect.Type
+	pointer uintptr
+	length  int
+}
+
+// copier - copies made so far, by original reference
+type copier map[copyKey]reflect.Value
+
+func (c copier) copy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		key := copyKey{value.Type(), value.Pointer(), 0}
+		if copied, ok := c[key]; ok {
+			return copied
+		}
+		copied := reflect.New(value.Elem().Type())
+		c[key] = copied
+		copied.Elem().Set(c.copy(value.Elem()))
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		key := copyKey{value.Type(), value.Pointer(), value.Len()}
+		if copied, ok := c[key]; ok {
+			return copied
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		c[key] = copied
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(c.copy(value.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(c.copy(value.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		key := copyKey{value.Type(), value.Pointer(), 0}
+		if copied, ok := c[key]; ok {
+			return copied
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		c[key] = copied
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, c.copy(value.MapIndex(key)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(c.copy(value.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(c.copy(value.Elem()))
+		return copied
+	}
+	return value
+}
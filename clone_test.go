@@ -0,0 +1,78 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+type clonedOrder struct {
+	ID    int
+	Items []string
+	Tags  map[string]string
+	Next  *clonedOrder
+	Extra interface{}
+}
+
+func TestDeepCopy(t *testing.T) {
+	original := &clonedOrder{1, []string{"a"}, map[string]string{"k": "v"}, &clonedOrder{ID: 2}, []int{1}}
+	copied := DeepCopy(original).(*clonedOrder)
+	copied.Items[0] = "b"
+	copied.Tags["k"] = "w"
+	copied.Next.ID = 3
+	copied.Extra.([]int)[0] = 2
+	if original.Items[0] != "a" || original.Tags["k"] != "v" || original.Next.ID != 2 || original.Extra.([]int)[0] != 1 {
+		t.Fatal(original)
+	}
+	if copied.ID != 1 || DeepCopy(nil) != nil || DeepCopy(5) != 5 {
+		t.Fail()
+	}
+}
+
+func TestWithDeepCopy(t *testing.T) {
+	bus := NewWithOptions(WithDeepCopy()).(*EventBus)
+	for i := 0; i < 4; i++ {
+		bus.SubscribeAsync("topic", func(items []int) {
+			for j := range items {
+				items[j]++
+			}
+		}, false)
+	}
+	payload := []int{0, 0, 0}
+	bus.Subscribe("topic", func(items []int) {
+		if &items[0] != &payload[0] {
+			t.Error("sync handlers receive the published arguments")
+		}
+	})
+	bus.Publish("topic", payload)
+	bus.WaitAsync()
+	if payload[0] != 0 {
+		t.Fatal(payload)
+	}
+}
+
+type clonedNode struct {
+	Value  int
+	Next   *clonedNode
+	Parent *clonedNode
+}
+
+func TestDeepCopyCycles(t *testing.T) {
+	root := &clonedNode{Value: 1}
+	child := &clonedNode{Value: 2, Parent: root}
+	root.Next = child
+	child.Next = root
+	copied := DeepCopy(root).(*clonedNode)
+	if copied == root || copied.Next == child || copied.Next.Next != copied || copied.Next.Parent != copied {
+		t.Fatal("cycle not preserved")
+	}
+	copied.Next.Value = 3
+	if child.Value != 2 {
+		t.Fail()
+	}
+
+	shared := []int{1}
+	pair := DeepCopy([2][]int{shared, shared}).([2][]int)
+	pair[0][0] = 2
+	if pair[1][0] != 2 || shared[0] != 1 {
+		t.Fail()
+	}
+}
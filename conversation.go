@@ -0,0 +1,55 @@
+package EventBus
+
+import (
+	"errors"
+)
+
+const (
+	// ReplyToHeader - header naming the topic the replies to an event go to, see Request
+	ReplyToHeader = "Reply-To"
+	// CausationIDHeader - header holding the ID of the event a reply answers, when the bus identifies events
+	CausationIDHeader = "Causation-ID"
+)
+
+// ErrNoReplyTo - the event replied to was not published by Request, it names no reply topic
+var ErrNoReplyTo = errors.New("event has no reply topic")
+
+// Request publishes args to topic asking for replies on replyTo, under a new correlation ID
+// which it returns. Handlers answer with Reply, the replies reach the requester through any
+// transport forwarding headers, such as Server and Client.
+func (bus *EventBus) Request(topic, replyTo string, args ...interface{}) (correlationID string) {
+	correlationID = bus.NewID()
+	bus.PublishWithHeaders(topic, Headers{CorrelationIDHeader: correlationID, ReplyToHeader: replyTo}, args...)
+	return correlationID
+}
+
+// Reply publishes args to the reply topic of the event described by ev, which handlers get by
+// declaring an EventMeta parameter. Sync and async handlers may reply. The reply keeps the
+// headers of the event, so its correlation ID, and records the event ID as its causation.
+// Returns ErrNoReplyTo when the event names no reply topic.
+func (bus *EventBus) Reply(ev EventMeta, args ...interface{}) error {
+	return bus.ReplyRequest(ev, "", args...)
+}
+
+// ReplyRequest is Reply asking in turn for an answer on replyTo, carrying the conversation on
+// for another hop under the same correlation ID.
+func (bus *EventBus) ReplyRequest(ev EventMeta, replyTo string, args ...interface{}) error {
+	topic := ev.Headers[ReplyToHeader]
+	if topic == "" {
+		return ErrNoReplyTo
+	}
+	headers := make(Headers, len(ev.Headers)+1)
+	for key, value := range ev.Headers {
+		headers[key] = value
+	}
+	delete(headers, ReplyToHeader)
+	delete(headers, CausationIDHeader)
+	if replyTo != "" {
+		headers[ReplyToHeader] = replyTo
+	}
+	if ev.ID != "" {
+		headers[CausationIDHeader] = ev.ID
+	}
+	bus.PublishWithHeaders(topic, headers, args...)
+	return nil
+}
@@ -0,0 +1,60 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestReply(t *testing.T) {
+	bus := NewWithOptions(WithIDGenerator(TimeOrderedID)).(*EventBus)
+	bus.SubscribeAsync("quote", func(ev EventMeta, item string) {
+		if err := bus.ReplyRequest(ev, "quote:confirm", item, 42); err != nil {
+			t.Error(err)
+		}
+	}, false)
+	bus.SubscribeAsync("quote:reply", func(ev EventMeta, item string, price int) {
+		if err := bus.Reply(ev, "accepted"); err != nil {
+			t.Error(err)
+		}
+	}, false)
+	confirmed := make(chan EventMeta, 1)
+	bus.Subscribe("quote:confirm", func(ev EventMeta, answer string) { confirmed <- ev })
+
+	id := bus.Request("quote", "quote:reply", "book")
+	select {
+	case ev := <-confirmed:
+		if ev.Headers[CorrelationIDHeader] != id || ev.Headers[ReplyToHeader] != "" || ev.Headers[CausationIDHeader] == "" {
+			t.Fatal(ev.Headers)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("conversation did not complete")
+	}
+	bus.WaitAsync()
+
+	if err := bus.Reply(EventMeta{Topic: "quote"}, "lost"); err != ErrNoReplyTo {
+		t.Fatal(err)
+	}
+}
+
+func TestRequestOverNetwork(t *testing.T) {
+	server := NewServer(":2085", "/_server_bus_request", New())
+	client := NewClient("localhost:2090", "/_client_bus_request", New())
+	client.Start()
+	defer client.Stop()
+
+	replies := make(chan EventMeta, 1)
+	remote := client.EventBus().(*EventBus)
+	remote.SubscribeAsync("quote", func(ev EventMeta, item string) { remote.Reply(ev, item, 42) }, false)
+	remote.Subscribe("quote:reply", func(ev EventMeta, item string, price int) { replies <- ev })
+	server.service.Register(&SubscribeArg{client.address, client.path, PublishService, Subscribe, "quote"}, new(bool))
+
+	id := server.EventBus().(*EventBus).Request("quote", "quote:reply", "book")
+	select {
+	case ev := <-replies:
+		if ev.Headers[CorrelationIDHeader] != id {
+			t.Fatal(ev.Headers)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reply not received")
+	}
+}
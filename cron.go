@@ -0,0 +1,175 @@
+package EventBus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar - days on which recurring events do not fire, e.g. public holidays
+type Calendar interface {
+	// Skip reports whether the day of t, in the time zone of the schedule, is skipped
+	Skip(t time.Time) bool
+}
+
+// dateCalendar - Calendar skipping a fixed set of dates, by year, month and day
+type dateCalendar map[string]bool
+
+// SkipDates returns a Calendar skipping the dates of the given days, whatever their time zone
+func SkipDates(days ...time.Time) Calendar {
+	calendar := make(dateCalendar, len(days))
+	for _, day := range days {
+		calendar[day.Format("2006-01-02")] = true
+	}
+	return calendar
+}
+
+func (calendar dateCalendar) Skip(t time.Time) bool {
+	return calendar[t.Format("2006-01-02")]
+}
+
+// CronSchedule - times matching a cron spec in a time zone, skipping the days of an optional Calendar
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64 // bit i set when value i matches
+	anyDom, anyDow                bool   // day of month or day of week starting with *
+	location                      *time.Location
+	calendar                      Calendar
+}
+
+// cronField - allowed values and names of a cron spec field
+type cronField struct {
+	min, max int
+	names    []string // names of the values from min on, when the field has any
+}
+
+var (
+	minuteField = cronField{0, 59, nil}
+	hourField   = cronField{0, 23, nil}
+	domField    = cronField{1, 31, nil}
+	monthField  = cronField{1, 12, []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}}
+	dowField    = cronField{0, 7, []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}}
+)
+
+// ParseCron parses a standard five field cron spec (minute, hour, day of month, month, day of
+// week) evaluated in loc, time.Local when nil. Fields take *, values, names of months and
+// week days, ranges, lists and steps, e.g. "0 9 * * MON-FRI". As usual with cron, when both day
+// fields are restricted a day matching either of them matches.
+func ParseCron(spec string, loc *time.Location) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron spec %q: 5 fields expected, %d given", spec, len(fields))
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	schedule := &CronSchedule{location: loc, anyDom: strings.HasPrefix(fields[2], "*"), anyDow: strings.HasPrefix(fields[4], "*")}
+	bits := []*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	var err error
+	for i, field := range []cronField{minuteField, hourField, domField, monthField, dowField} {
+		if *bits[i], err = field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("cron spec %q: %v", spec, err)
+		}
+	}
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1 // 7 is Sunday as well
+	}
+	return schedule, nil
+}
+
+// Skipping returns the schedule skipping the days of calendar
+func (schedule *CronSchedule) Skipping(calendar Calendar) *CronSchedule {
+	copied := *schedule
+	copied.calendar = calendar
+	return &copied
+}
+
+// Location returns the time zone the schedule is evaluated in
+func (schedule *CronSchedule) Location() *time.Location {
+	return schedule.location
+}
+
+// Next returns the first time of the schedule after t, the zero time when there is none within five years
+func (schedule *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(schedule.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case schedule.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, schedule.location)
+		case !schedule.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, schedule.location)
+		case schedule.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, schedule.location)
+		case schedule.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (schedule *CronSchedule) matchDay(t time.Time) bool {
+	dom := schedule.dom&(1<<uint(t.Day())) != 0
+	dow := schedule.dow&(1<<uint(t.Weekday())) != 0
+	var match bool
+	if schedule.anyDom || schedule.anyDow {
+		match = dom && dow
+	} else {
+		match = dom || dow
+	}
+	return match && (schedule.calendar == nil || !schedule.calendar.Skip(t))
+}
+
+// parse returns the values matched by a field of a cron spec as a bit set
+func (field cronField) parse(spec string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(spec, ",") {
+		rangeSpec, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangeSpec = part[:i]
+		}
+		low, high := field.min, field.max
+		if rangeSpec != "*" {
+			bounds := strings.SplitN(rangeSpec, "-", 2)
+			var err error
+			if low, err = field.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = field.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				high = field.max
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid range %q", rangeSpec)
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of the field, a number or a name
+func (field cronField) value(s string) (int, error) {
+	for i, name := range field.names {
+		if strings.EqualFold(s, name) {
+			return field.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < field.min || v > field.max {
+		return 0, fmt.Errorf("invalid value %q, expected %d-%d", s, field.min, field.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package EventBus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+	schedule, err := ParseCron("0 9 * * MON-FRI", paris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Friday 2026-10-16 10:00 in Paris
+	from := time.Date(2026, 10, 16, 10, 0, 0, 0, paris)
+	if next := schedule.Next(from); !next.Equal(time.Date(2026, 10, 19, 9, 0, 0, 0, paris)) {
+		t.Fatal(next)
+	}
+	holiday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	if next := schedule.Skipping(SkipDates(holiday)).Next(from); !next.Equal(time.Date(2026, 10, 20, 9, 0, 0, 0, paris)) {
+		t.Fatal(next)
+	}
+	// clocks go back on 2026-10-25, 9am stays 9am local time
+	if next := schedule.Next(time.Date(2026, 10, 23, 10, 0, 0, 0, paris)); next.UTC().Hour() != 8 {
+		t.Fatal(next.UTC())
+	}
+}
+
+func TestCronFields(t *testing.T) {
+	schedule, err := ParseCron("*/15 8-10 1,15 * 7", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// day of month and day of week both restricted: the 1st, the 15th or a Sunday
+	from := time.Date(2026, 11, 2, 11, 0, 0, 0, time.UTC)
+	expected := []time.Time{
+		time.Date(2026, 11, 8, 8, 0, 0, 0, time.UTC),
+		time.Date(2026, 11, 8, 8, 15, 0, 0, time.UTC),
+	}
+	for _, want := range expected {
+		if from = schedule.Next(from); !from.Equal(want) {
+			t.Fatal(from)
+		}
+	}
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * * * MON-FOO", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := ParseCron(spec, nil); err == nil {
+			t.Error(spec)
+		}
+	}
+	if next := mustParseCron(t, "0 0 30 2 *").Next(from); !next.IsZero() {
+		t.Fatal(next)
+	}
+}
+
+func TestSchedulerPublishCron(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileScheduleStore(filepath.Join(dir, "schedule"))
+	bus := New().(*EventBus)
+	defer bus.Close()
+	today := time.Now().UTC()
+	s, _ := bus.NewScheduler(store, CatchUpAll, WithCalendar(SkipDates(today)))
+	id, err := s.PublishCron("report", "0 9 * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _ := store.Load()
+	if len(events) != 1 || events[0].ID != id || events[0].Zone != "UTC" || events[0].At.Hour() != 9 ||
+		events[0].At.Format("2006-01-02") == today.Format("2006-01-02") {
+		t.Fatal(events)
+	}
+}
+
+func mustParseCron(t *testing.T, spec string) *CronSchedule {
+	schedule, err := ParseCron(spec, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schedule
+}
@@ -0,0 +1,33 @@
+package EventBus
+
+// DeadLetterTopic - topic a DeadLetter is published to for every event no handler received
+const DeadLetterTopic = "bus:dead-letter"
+
+// DeadLetter - an event published to a topic without handlers
+type DeadLetter struct {
+	Topic   string
+	Headers Headers
+	Args    []interface{}
+}
+
+// DeadLetterHandler - receives the events no handler received, with their topic
+type DeadLetterHandler func(topic string, args []interface{})
+
+// SetDeadLetterHandler passes the events published to a topic without handlers to handle, once
+// the bus lock is released, so misrouted or orphaned events can be detected. Events whose
+// handlers were all disabled by flags or tags count as well, those of a cancelled
+// PublishWithContext do not. A nil handle stops the calls. With or without handle, such events
+// are also published as a DeadLetter to DeadLetterTopic when it has handlers.
+func (bus *EventBus) SetDeadLetterHandler(handle func(topic string, args []interface{})) {
+	bus.deadLetters.Store(DeadLetterHandler(handle))
+}
+
+// deadLetter reports an event no handler received
+func (bus *EventBus) deadLetter(topic string, headers Headers, args []interface{}) {
+	if handle, _ := bus.deadLetters.Load().(DeadLetterHandler); handle != nil {
+		handle(topic, args)
+	}
+	if topic != DeadLetterTopic && bus.HasCallback(DeadLetterTopic) {
+		bus.Publish(DeadLetterTopic, DeadLetter{Topic: topic, Headers: headers, Args: args})
+	}
+}
@@ -0,0 +1,32 @@
+package EventBus
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetDeadLetterHandler(t *testing.T) {
+	bus := New().(*EventBus)
+	var topics []string
+	bus.SetDeadLetterHandler(func(topic string, args []interface{}) {
+		bus.Subscribe("late", func() {}) // the bus is unlocked
+		topics = append(topics, topic)
+	})
+	bus.Subscribe("handled", func(int) {})
+	bus.Publish("handled", 1)
+	bus.Publish("orphan", 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.PublishWithContext(ctx, "cancelled")
+	if len(topics) != 1 || topics[0] != "orphan" {
+		t.Fatal(topics)
+	}
+
+	bus.SetDeadLetterHandler(nil)
+	var letters []DeadLetter
+	bus.Subscribe(DeadLetterTopic, func(letter DeadLetter) { letters = append(letters, letter) })
+	bus.PublishWithHeaders("misrouted", Headers{CorrelationIDHeader: "c-1"}, "payload")
+	if len(topics) != 1 || len(letters) != 1 || letters[0].Topic != "misrouted" || letters[0].Args[0] != "payload" || letters[0].Headers[CorrelationIDHeader] != "c-1" {
+		t.Fatal(topics, letters)
+	}
+}
@@ -0,0 +1,69 @@
+package EventBus
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer - publishes to a topic once a key has been quiet for a delay, collapsing bursts of
+// triggers (e.g. the several writes an editor does when saving a file) into a single event
+// carrying the arguments of the last trigger
+type Debouncer struct {
+	bus     Bus
+	topic   string
+	delay   time.Duration
+	lock    sync.Mutex
+	pending map[string]*debounced
+}
+
+// debounced - event waiting for the quiet period of its key to end
+type debounced struct {
+	timer *time.Timer
+	args  []interface{}
+}
+
+// NewDebouncer - create a debouncer publishing to topic after delay without triggers
+func NewDebouncer(bus Bus, topic string, delay time.Duration) *Debouncer {
+	return &Debouncer{
+		bus:     bus,
+		topic:   topic,
+		delay:   delay,
+		pending: make(map[string]*debounced),
+	}
+}
+
+// Trigger (re)starts the quiet period of key, args replace those of earlier triggers of the key.
+// Events of a watcher such as fsnotify can be fed in directly, keyed by file name.
+func (debouncer *Debouncer) Trigger(key string, args ...interface{}) {
+	debouncer.lock.Lock()
+	defer debouncer.lock.Unlock()
+	if event, ok := debouncer.pending[key]; ok {
+		event.timer.Stop()
+	}
+	event := &debounced{args: args}
+	debouncer.pending[key] = event
+	event.timer = time.AfterFunc(debouncer.delay, func() { debouncer.fire(key, event) })
+}
+
+// Stop cancels every pending event.
+func (debouncer *Debouncer) Stop() {
+	debouncer.lock.Lock()
+	defer debouncer.lock.Unlock()
+	for key, event := range debouncer.pending {
+		event.timer.Stop()
+		delete(debouncer.pending, key)
+	}
+}
+
+func (debouncer *Debouncer) fire(key string, event *debounced) {
+	debouncer.lock.Lock()
+	// a timer which was stopped too late must not publish on behalf of a newer trigger
+	current := debouncer.pending[key] == event
+	if current {
+		delete(debouncer.pending, key)
+	}
+	debouncer.lock.Unlock()
+	if current {
+		debouncer.bus.Publish(debouncer.topic, event.args...)
+	}
+}
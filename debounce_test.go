@@ -0,0 +1,65 @@
+package EventBus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	bus := New()
+	events := make(chan int, 10)
+	bus.Subscribe("changed", func(a int) { events <- a })
+	debouncer := NewDebouncer(bus, "changed", 20*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		debouncer.Trigger("key", i)
+	}
+	debouncer.Trigger("other", 10)
+	got := []int{<-events, <-events}
+	if !(got[0] == 4 && got[1] == 10 || got[0] == 10 && got[1] == 4) {
+		t.Fatal(got)
+	}
+
+	debouncer.Trigger("key", 1)
+	debouncer.Stop()
+	select {
+	case <-events:
+		t.Fail()
+	case <-time.After(40 * time.Millisecond):
+	}
+}
+
+func TestFileWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+
+	bus := New()
+	events := make(chan string, 10)
+	bus.Subscribe("file", func(p, op string) {
+		if p == path {
+			events <- op
+		}
+	})
+	watcher := NewFileWatcher(bus, "file", 5*time.Millisecond, 20*time.Millisecond, path)
+	watcher.Start()
+	defer watcher.Stop()
+
+	ioutil.WriteFile(path, []byte("a"), 0644)
+	if op := <-events; op != FileCreated {
+		t.Fatal(op)
+	}
+	ioutil.WriteFile(path, []byte("abc"), 0644)
+	if op := <-events; op != FileWritten {
+		t.Fatal(op)
+	}
+	os.Remove(path)
+	if op := <-events; op != FileRemoved {
+		t.Fatal(op)
+	}
+}
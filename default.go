@@ -0,0 +1,49 @@
+package EventBus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	defaultBus  atomic.Value // *EventBus returned by Default
+	defaultLock sync.Mutex   // creates the default bus once
+)
+
+// Default returns the process-wide bus, created by New on first use. Small programs use it
+// instead of passing a bus around: Default().Subscribe subscribes to it, Publish publishes to
+// it. The package-level names Subscribe and SubscribeOnce being the SubscribeType constants of
+// the rpc transport, there are no package-level subscribe functions.
+func Default() *EventBus {
+	if bus, ok := defaultBus.Load().(*EventBus); ok {
+		return bus
+	}
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+	if bus, ok := defaultBus.Load().(*EventBus); ok {
+		return bus
+	}
+	bus := New().(*EventBus)
+	defaultBus.Store(bus)
+	return bus
+}
+
+// SetDefault makes bus the default bus, e.g. a fresh bus for every test, until restore puts the
+// previous one back. The handlers subscribed to the previous bus stay there.
+func SetDefault(bus *EventBus) (restore func()) {
+	previous := Default()
+	defaultBus.Store(bus)
+	return func() {
+		defaultBus.Store(previous)
+	}
+}
+
+// Publish publishes args to a topic of the default bus, see EventBus.Publish.
+func Publish(topic string, args ...interface{}) {
+	Default().Publish(topic, args...)
+}
+
+// WaitAsync waits for the async callbacks of the default bus to complete.
+func WaitAsync() {
+	Default().WaitAsync()
+}
@@ -0,0 +1,38 @@
+package EventBus
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	var wg sync.WaitGroup
+	buses := make([]*EventBus, 8)
+	for i := range buses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			buses[i] = Default()
+		}(i)
+	}
+	wg.Wait()
+	for _, bus := range buses {
+		if bus != buses[0] || bus == nil {
+			t.Fatal("default bus created twice")
+		}
+	}
+
+	fresh := New().(*EventBus)
+	restore := SetDefault(fresh)
+	var got []string
+	Default().Subscribe("greeting", func(s string) { got = append(got, s) })
+	Publish("greeting", "hello")
+	WaitAsync()
+	if len(got) != 1 || !fresh.HasCallback("greeting") || buses[0].HasCallback("greeting") {
+		t.Fatal(got)
+	}
+	restore()
+	if Default() != buses[0] {
+		t.Fatal("previous default not restored")
+	}
+}
@@ -0,0 +1,88 @@
+package EventBus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// emitter - producer publishing the current time to a topic at a fixed interval
+type emitter struct {
+	topic  string
+	ticker *time.Ticker
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// EmitEvery publishes the tick time (a time.Time argument) to topic every interval until
+// the returned stop function or Close is called, so components can share heartbeat topics
+// instead of each owning a time.Ticker. Once the bus is closed nothing is emitted and stop
+// does nothing.
+func (bus *EventBus) EmitEvery(topic string, interval time.Duration) (stop func()) {
+	bus.lock.Lock()
+	if atomic.LoadInt32(&bus.closed) != 0 {
+		bus.lock.Unlock()
+		return func() {}
+	}
+	e := &emitter{topic, time.NewTicker(interval), make(chan struct{}), make(chan struct{})}
+	bus.emitters[e] = true
+	bus.lock.Unlock()
+	go bus.emit(e)
+	return func() { bus.stopEmitter(e) }
+}
+
+func (bus *EventBus) emit(e *emitter) {
+	defer close(e.done)
+	for {
+		select {
+		case <-e.stop:
+			return
+		case tick := <-e.ticker.C:
+			bus.Publish(e.topic, tick)
+		}
+	}
+}
+
+func (bus *EventBus) stopEmitter(e *emitter) {
+	bus.lock.Lock()
+	running := bus.emitters[e]
+	delete(bus.emitters, e)
+	bus.lock.Unlock()
+	if running {
+		e.ticker.Stop()
+		close(e.stop)
+		<-e.done
+	}
+}
+
+// Close stops every producer, scheduler and change feed managed by the bus, cancels its Scope,
+// waits for async callbacks and scope goroutines to complete and stops the async worker pool. Subscribing afterwards returns ErrBusClosed.
+func (bus *EventBus) Close() {
+	bus.lock.Lock()
+	atomic.StoreInt32(&bus.closed, 1)
+	emitters := make([]*emitter, 0, len(bus.emitters))
+	for e := range bus.emitters {
+		emitters = append(emitters, e)
+	}
+	schedulers := make([]*Scheduler, 0, len(bus.schedulers))
+	for s := range bus.schedulers {
+		schedulers = append(schedulers, s)
+	}
+	feeds := make([]*ChangeFeed, 0, len(bus.feeds))
+	for feed := range bus.feeds {
+		feeds = append(feeds, feed)
+	}
+	bus.lock.Unlock()
+	for _, feed := range feeds {
+		feed.Stop()
+	}
+	for _, e := range emitters {
+		bus.stopEmitter(e)
+	}
+	for _, s := range schedulers {
+		s.Stop()
+	}
+	bus.scope.close()
+	bus.WaitAsync()
+	// the pointer stays, so publishers reading it without the lock (sealed bus) see a stopped pool
+	bus.pool().stop()
+}
@@ -0,0 +1,43 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmitEvery(t *testing.T) {
+	bus := New().(*EventBus)
+	ticks := make(chan time.Time, 100)
+	bus.Subscribe("tick", func(tick time.Time) { ticks <- tick })
+	stop := bus.EmitEvery("tick", time.Millisecond)
+	<-ticks
+	<-ticks
+	stop()
+	stop()
+	for len(ticks) > 0 {
+		<-ticks
+	}
+	time.Sleep(5 * time.Millisecond)
+	if len(ticks) != 0 {
+		t.Fail()
+	}
+}
+
+func TestCloseStopsEmitters(t *testing.T) {
+	bus := New().(*EventBus)
+	count := make(chan bool, 100)
+	bus.Subscribe("heartbeat", func(tick time.Time) { count <- true })
+	bus.EmitEvery("heartbeat", time.Millisecond)
+	bus.EmitEvery("heartbeat", 2*time.Millisecond)
+	<-count
+	bus.Close()
+	for len(count) > 0 {
+		<-count
+	}
+	stop := bus.EmitEvery("heartbeat", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if len(count) != 0 || len(bus.emitters) != 0 {
+		t.Fail()
+	}
+	stop()
+}
@@ -0,0 +1,137 @@
+package EventBus
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// EntitySeparator - separates the kind of an entity topic from the entity ID, see EntityTopic
+const EntitySeparator = ":"
+
+// entityWildcard - ID of the topic SubscribeEntities subscribes to
+const entityWildcard = "*"
+
+// EntityTopic returns the topic of a single entity of a kind, e.g. "order:42"
+func EntityTopic(kind string, id interface{}) string {
+	return kind + EntitySeparator + fmt.Sprint(id)
+}
+
+// EntityOf splits an entity topic into its kind and ID, handlers subscribed by
+// SubscribeEntities read the topic from EventMeta.
+func EntityOf(topic string) (kind, id string, ok bool) {
+	i := strings.LastIndex(topic, EntitySeparator)
+	if i < 0 {
+		return "", "", false
+	}
+	return topic[:i], topic[i+len(EntitySeparator):], true
+}
+
+// SubscribeEntity subscribes to the topic of a single entity with the given options. Once its
+// last handler is gone, the statistics, sequence number, Barrier and PublishOnce bookkeeping of
+// the topic are reclaimed as by GC, so topics of short lived entities do not pile up.
+func (bus *EventBus) SubscribeEntity(kind string, id interface{}, fn interface{}, opts ...SubscribeOption) error {
+	_, err := bus.subscribeEntity(kind, id, fn, opts)
+	return err
+}
+
+func (bus *EventBus) subscribeEntity(kind string, id interface{}, fn interface{}, opts []SubscribeOption) (*Subscription, error) {
+	topic := EntityTopic(kind, id)
+	sub, err := bus.SubscribeHandle(topic, fn, opts...)
+	if err != nil {
+		return nil, err
+	}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if len(bus.handlers[topic]) > 0 {
+		if bus.entities == nil {
+			bus.entities = make(map[string]bool)
+		}
+		bus.entities[topic] = true
+	}
+	return sub, nil
+}
+
+// SubscribeEntities subscribes to the topics of every entity of a kind, with the given options.
+// Its handlers run after those of the entity topic itself. Unsubscribe from EntityTopic(kind, "*").
+func (bus *EventBus) SubscribeEntities(kind string, fn interface{}, opts ...SubscribeOption) error {
+	_, err := bus.subscribeEntities(kind, fn, opts)
+	return err
+}
+
+func (bus *EventBus) subscribeEntities(kind string, fn interface{}, opts []SubscribeOption) (*Subscription, error) {
+	bus.lock.Lock()
+	if bus.sealedTable() != nil {
+		bus.lock.Unlock()
+		return nil, ErrSealed
+	}
+	atomic.StoreInt32(&bus.wildcards, 1)
+	bus.lock.Unlock()
+	return bus.SubscribeHandle(EntityTopic(kind, entityWildcard), fn, opts...)
+}
+
+// wildcardOf returns the topic subscribed by SubscribeEntities for every entity of the topic's kind
+func (bus *EventBus) wildcardOf(topic string) (string, bool) {
+	if atomic.LoadInt32(&bus.wildcards) == 0 {
+		return "", false
+	}
+	kind, id, ok := EntityOf(topic)
+	if !ok || id == entityWildcard {
+		return "", false
+	}
+	return EntityTopic(kind, entityWildcard), true
+}
+
+// subscribers returns the handlers of the topic in handlers, followed by those subscribed to
+// every entity of its kind and by the tree handlers of its ancestors
+func (bus *EventBus) subscribers(handlers handlerSource, topic string) []*eventHandler {
+	return bus.withAncestors(handlers, topic, bus.entitySubscribers(handlers, topic))
+}
+
+// entitySubscribers returns the handlers of the topic in handlers, followed by those subscribed
+// to every entity of its kind
+func (bus *EventBus) entitySubscribers(handlers handlerSource, topic string) []*eventHandler {
+	exact := handlers.lookup(topic)
+	wildcard, ok := bus.wildcardOf(topic)
+	if !ok {
+		return exact
+	}
+	all := handlers.lookup(wildcard)
+	if len(all) == 0 {
+		return exact
+	}
+	// both in priority order, the handlers of the entity first among equals
+	merged := make([]*eventHandler, 0, len(exact)+len(all))
+	for _, handler := range all {
+		for len(exact) > 0 && exact[0].priority >= handler.priority {
+			merged, exact = append(merged, exact[0]), exact[1:]
+		}
+		merged = append(merged, handler)
+	}
+	return append(merged, exact...)
+}
+
+// dropTopic removes the entry of a topic left without handlers, reclaiming the rest of an entity
+// topic with it. The bus lock must be held.
+func (bus *EventBus) dropTopic(topic string) {
+	bus.setHandlers(topic, nil)
+	if bus.entities[topic] {
+		delete(bus.entities, topic)
+		bus.reclaim(topic)
+		if _, busy := bus.progress.Load(topic); busy {
+			// a once handler removed by Publish, the event is pending until Publish returns
+			bus.dropped = append(bus.dropped, topic)
+		}
+	}
+}
+
+// reclaimDropped reclaims the entity topics whose last handler was removed by Publish, once the
+// event is no longer pending. The bus lock must be held.
+func (bus *EventBus) reclaimDropped() {
+	for _, topic := range bus.dropped {
+		if len(bus.handlers[topic]) == 0 {
+			bus.reclaim(topic)
+		}
+	}
+	bus.dropped = nil
+}
@@ -0,0 +1,61 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestEntityTopic(t *testing.T) {
+	topic := EntityTopic("order", 42)
+	if topic != "order:42" {
+		t.Fatal(topic)
+	}
+	if kind, id, ok := EntityOf("fsm:entered:42"); !ok || kind != "fsm:entered" || id != "42" {
+		t.Fatal(kind, id, ok)
+	}
+	if _, _, ok := EntityOf("order"); ok {
+		t.Fatal("topic without ID split")
+	}
+}
+
+func TestSubscribeEntityReclaimed(t *testing.T) {
+	bus := New().(*EventBus)
+	handler := func() {}
+	bus.SubscribeEntity("order", 1, handler)
+	bus.SubscribeEntity("order", 2, handler, WithOnce())
+	bus.Publish(EntityTopic("order", 1))
+	bus.Publish(EntityTopic("order", 2))
+	if _, ok := bus.progress.Load("order:2"); ok || bus.HasCallback("order:2") || bus.TopicStats("order:2").Published != 0 {
+		t.Fatal("once entity topic not reclaimed")
+	}
+	bus.Unsubscribe("order:1", handler)
+	if _, ok := bus.progress.Load("order:1"); ok || bus.Sequence("order:1") != 0 || len(bus.entities) != 0 {
+		t.Fatal("entity topic not reclaimed")
+	}
+}
+
+func TestSubscribeEntities(t *testing.T) {
+	bus := New().(*EventBus)
+	var received []string
+	bus.SubscribeEntity("order", 1, func(n int) { received = append(received, "order:1") })
+	bus.SubscribeEntities("order", func(meta EventMeta, n int) { received = append(received, "*"+meta.Topic) })
+	bus.SubscribeEntities("user", func(n int) { received = append(received, "user") }, WithOnce())
+
+	if delivered := bus.PublishEx(EntityTopic("order", 1), 1); delivered != 2 {
+		t.Fatal(delivered)
+	}
+	bus.Publish(EntityTopic("order", 2), 2)
+	bus.Publish(EntityTopic("user", 1), 3)
+	bus.Seal()
+	bus.Publish(EntityTopic("user", 2), 4)
+	bus.Publish(EntityTopic("order", 3), 5)
+
+	expected := []string{"order:1", "*order:1", "*order:2", "user", "*order:3"}
+	if len(received) != len(expected) {
+		t.Fatal(received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Fatal(received)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package EventBus
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// HandlerEquality - tells whether a subscribed handler is the one given to Unsubscribe or Replace
+type HandlerEquality func(subscribed, handler interface{}) bool
+
+// SameCode - default HandlerEquality, matching functions of the same type and code. Closures
+// created by the same function literal match whatever they captured, and so do the method
+// values of a method whatever their receiver.
+func SameCode(subscribed, handler interface{}) bool {
+	a, b := reflect.ValueOf(subscribed), reflect.ValueOf(handler)
+	return a.Type() == b.Type() && a.Pointer() == b.Pointer()
+}
+
+// SameInstance - HandlerEquality matching the very function value subscribed, so two closures
+// of one literal or two method values bound to different receivers are told apart. Every
+// evaluation of a closure or method value makes a new instance: keep the one subscribed to
+// unsubscribe it.
+func SameInstance(subscribed, handler interface{}) bool {
+	return reflect.TypeOf(subscribed) == reflect.TypeOf(handler) && funcInstance(subscribed) == funcInstance(handler)
+}
+
+// funcInstance returns the address of the function value held by fn, the data word of the interface
+func funcInstance(fn interface{}) unsafe.Pointer {
+	return (*[2]unsafe.Pointer)(unsafe.Pointer(&fn))[1]
+}
+
+// WithHandlerEquality matches the handlers given to Unsubscribe and Replace with equal, e.g. SameInstance
+func WithHandlerEquality(equal HandlerEquality) Option {
+	return func(bus *EventBus) {
+		bus.equality = equal
+	}
+}
+
+// WithKey identifies the handler by key, a comparable value, so UnsubscribeKey removes it
+// whatever the function.
+func WithKey(key interface{}) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.key = key
+	}
+}
+
+// UnsubscribeKey removes the handlers of the topic subscribed WithKey(key).
+// Returns ErrTopicNotFound if there are no callbacks subscribed to the topic,
+// *ErrHandlerNotFound if none has the key.
+func (bus *EventBus) UnsubscribeKey(topic string, key interface{}) error {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	if len(bus.handlers[topic]) == 0 {
+		return fmt.Errorf("topic %s: %w", topic, ErrTopicNotFound)
+	}
+	removed := false
+	for idx := len(bus.handlers[topic]) - 1; idx >= 0; idx-- {
+		if handler := bus.handlers[topic][idx]; handler.key != nil && handler.key == key {
+			bus.removeHandler(topic, idx)
+			removed = true
+		}
+	}
+	if !removed {
+		return &ErrHandlerNotFound{Topic: topic, Handler: fmt.Sprintf("key %v", key)}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+type counterHandler struct {
+	calls int
+}
+
+func (c *counterHandler) Handle() { c.calls++ }
+
+func TestSameInstance(t *testing.T) {
+	bus := NewWithOptions(WithHandlerEquality(SameInstance)).(*EventBus)
+	first, second := &counterHandler{}, &counterHandler{}
+	firstHandle, secondHandle := first.Handle, second.Handle
+	bus.Subscribe("topic", firstHandle)
+	bus.Subscribe("topic", secondHandle)
+	if err := bus.Unsubscribe("topic", second.Handle); err == nil {
+		t.Fatal("another method value instance matched")
+	}
+	if err := bus.Unsubscribe("topic", secondHandle); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic")
+	if first.calls != 1 || second.calls != 0 {
+		t.Fatal(first.calls, second.calls)
+	}
+
+	closures := make([]func(), 2)
+	var calls []int
+	for i := range closures {
+		i := i
+		closures[i] = func() { calls = append(calls, i) }
+		bus.Subscribe("closures", closures[i])
+	}
+	bus.Unsubscribe("closures", closures[1])
+	bus.Publish("closures")
+	if len(calls) != 1 || calls[0] != 0 {
+		t.Fatal(calls)
+	}
+}
+
+func TestSameCodeDefault(t *testing.T) {
+	bus := New().(*EventBus)
+	first, second := &counterHandler{}, &counterHandler{}
+	bus.Subscribe("topic", first.Handle)
+	bus.Unsubscribe("topic", second.Handle) // same code, so the first subscription goes
+	if bus.HasCallback("topic") {
+		t.Fatal("method values of the same method did not match")
+	}
+}
+
+func TestUnsubscribeKey(t *testing.T) {
+	bus := New().(*EventBus)
+	calls := 0
+	for i := 0; i < 2; i++ {
+		bus.SubscribeWith("topic", func() { calls++ }, WithKey("component-a"))
+	}
+	bus.SubscribeWith("topic", func() { calls += 10 }, WithKey("component-b"))
+	bus.Subscribe("topic", func() { calls += 100 })
+	if err := bus.UnsubscribeKey("topic", "component-a"); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic")
+	if calls != 110 {
+		t.Fatal(calls)
+	}
+	var notFound *ErrHandlerNotFound
+	if err := bus.UnsubscribeKey("topic", "component-a"); !errors.As(err, &notFound) {
+		t.Fatal(err)
+	}
+	if err := bus.UnsubscribeKey("none", "component-a"); !errors.Is(err, ErrTopicNotFound) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,44 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrNotAFunction - a handler given to the bus is not a function
+	ErrNotAFunction = errors.New("handler is not a function")
+	// ErrTopicNotFound - no handler is subscribed to the topic
+	ErrTopicNotFound = errors.New("topic not found")
+	// ErrBusClosed - the bus was closed, it takes no more subscriptions
+	ErrBusClosed = errors.New("bus is closed")
+	// ErrAlreadyStarted - a service, producer or helper was started twice
+	ErrAlreadyStarted = errors.New("already started")
+	// ErrEventParameters - a handler taking an *Event declares parameters for the published
+	// arguments as well, which the *Event holds instead
+	ErrEventParameters = errors.New("handler taking an *Event declares published arguments")
+)
+
+// ErrHandlerNotFound - the handler is not subscribed to the topic, which has other handlers
+type ErrHandlerNotFound struct {
+	Topic   string
+	Handler string // name of the handler's function
+}
+
+func (err *ErrHandlerNotFound) Error() string {
+	return fmt.Sprintf("handler %s not subscribed to topic %s", err.Handler, err.Topic)
+}
+
+// checkFunc returns an error wrapping ErrNotAFunction unless fn is a function
+func checkFunc(fn interface{}) error {
+	if reflect.TypeOf(fn) == nil || reflect.TypeOf(fn).Kind() != reflect.Func {
+		return fmt.Errorf("%v is not of type reflect.Func: %w", reflect.TypeOf(fn), ErrNotAFunction)
+	}
+	return nil
+}
+
+// handlerNotFound returns the error reported when fn is not subscribed to the topic
+func handlerNotFound(topic string, fn interface{}) error {
+	return &ErrHandlerNotFound{Topic: topic, Handler: newEventHandler(fn, false, false, false).name()}
+}
@@ -0,0 +1,53 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrNotAFunction(t *testing.T) {
+	bus := New().(*EventBus)
+	for _, err := range []error{
+		bus.Subscribe("topic", 1),
+		bus.Subscribe("topic", nil),
+		bus.Unsubscribe("topic", "handler"),
+		bus.Replace("topic", func() {}, 1),
+	} {
+		if !errors.Is(err, ErrNotAFunction) {
+			t.Error(err)
+		}
+	}
+}
+
+func TestUnsubscribeErrors(t *testing.T) {
+	bus := New()
+	if err := bus.Unsubscribe("topic", func() {}); !errors.Is(err, ErrTopicNotFound) {
+		t.Fatal(err)
+	}
+	bus.Subscribe("topic", func() {})
+	var notFound *ErrHandlerNotFound
+	if err := bus.Unsubscribe("topic", TestUnsubscribeErrors); !errors.As(err, &notFound) || notFound.Topic != "topic" || notFound.Handler != "github.com/asaskevich/EventBus.TestUnsubscribeErrors" {
+		t.Fatal(err)
+	}
+}
+
+func TestErrBusClosed(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.Close()
+	if err := bus.Subscribe("topic", func() {}); err != ErrBusClosed {
+		t.Fatal(err)
+	}
+	if _, err := bus.NewScheduler(nil, CatchUpAll); err != ErrBusClosed {
+		t.Fatal(err)
+	}
+}
+
+func TestErrAlreadyStarted(t *testing.T) {
+	bus := New().(*EventBus)
+	fsm := NewFSM(bus, "idle")
+	fsm.Start()
+	defer fsm.Stop()
+	if err := fsm.Start(); !errors.Is(err, ErrAlreadyStarted) || err.Error() != "FSM already started" {
+		t.Fatal(err)
+	}
+}
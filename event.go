@@ -0,0 +1,23 @@
+package EventBus
+
+import (
+	"time"
+)
+
+// Event - a published event: its topic, the arguments passed to Publish and, when injected into
+// a handler declaring an *Event parameter, the metadata of EventMeta
+type Event struct {
+	Topic     string
+	Args      []interface{}
+	ID        string // generated by the bus IDGenerator, empty without one
+	Seq       uint64 // position of the event in its topic
+	Published time.Time
+	Headers   Headers
+}
+
+// PublishEvent publishes the arguments of event to its topic along with its headers. The
+// ID, sequence number and publish time are those the bus gives every event, the values of
+// event are ignored.
+func (bus *EventBus) PublishEvent(event Event) {
+	bus.PublishWithHeaders(event.Topic, event.Headers, event.Args...)
+}
@@ -1,12 +1,17 @@
 package EventBus
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-//BusSubscriber defines subscription-related bus behavior
+// BusSubscriber defines subscription-related bus behavior
 type BusSubscriber interface {
 	Subscribe(topic string, fn interface{}) error
 	SubscribeAsync(topic string, fn interface{}, transactional bool) error
@@ -15,18 +20,18 @@ type BusSubscriber interface {
 	Unsubscribe(topic string, handler interface{}) error
 }
 
-//BusPublisher defines publishing-related bus behavior
+// BusPublisher defines publishing-related bus behavior
 type BusPublisher interface {
 	Publish(topic string, args ...interface{})
 }
 
-//BusController defines bus control behavior (checking handler's presence, synchronization)
+// BusController defines bus control behavior (checking handler's presence, synchronization)
 type BusController interface {
 	HasCallback(topic string) bool
 	WaitAsync()
 }
 
-//Bus englobes global (subscribe, publish, control) bus behavior
+// Bus englobes global (subscribe, publish, control) bus behavior
 type Bus interface {
 	BusController
 	BusSubscriber
@@ -35,9 +40,52 @@ type Bus interface {
 
 // EventBus - box for handlers and callbacks.
 type EventBus struct {
-	handlers map[string][]*eventHandler
-	lock     sync.Mutex // a lock for the map
-	wg       sync.WaitGroup
+	handlers    handlerMap   // never modified in place, see setHandlers
+	live        liveHandlers // copy of handlers read by Publish without the lock
+	lock        sync.Mutex   // a lock for the map
+	wg          sync.WaitGroup
+	trace       *traceRing // ring of recently published events, nil when tracing is disabled
+	emitters    map[*emitter]bool
+	objects     map[interface{}][]registration    // handlers subscribed by RegisterHandlers, per object
+	validate    bool                              // check published arguments against handler signatures
+	inlineAsync bool                              // run async handlers on the publishing goroutine
+	workers     atomic.Value                      // *workerPool running async handlers, a goroutine per delivery when nil, see Tune
+	onceLock    sync.Mutex                        // a lock for onceKeys
+	onceKeys    map[string]*dedupSet              // keys delivered by PublishOnce, per topic
+	ids         IDGenerator                       // identifies published events, none when nil
+	sealed      atomic.Value                      // *sealedTable once Seal was called
+	causation   atomic.Value                      // *causationLog, see RecordCausation
+	flags       *flagCache                        // gates handlers subscribed WithEnabledWhen
+	shadows     []*shadow                         // handlers subscribed by SubscribeShadow
+	inFlight    *inFlightSet                      // deliveries running, nil unless tracked
+	stats       topicStatsSet                     // publishing statistics per topic
+	cloner      func(arg interface{}) interface{} // copies the arguments of every async delivery, see WithArgCloner
+	mutations   func(MutationReport)              // reports handlers changing their arguments, see WithMutationDetection
+	progress    sync.Map                          // *topicProgress per topic, see Barrier
+	seqLock     sync.Mutex                        // creation of the topicProgress entries, against GC
+	seqFloor    uint64                            // highest sequence number of the topics reclaimed by GC
+	schedulers  map[*Scheduler]bool               // schedulers publishing to the bus, stopped by Close
+	states      sync.Map                          // *topicState per topic, see SetState
+	watchers    watcherSet                        // watchers of the state topics, see Watch
+	running     runningSet                        // deliveries of correlated events, see CancelTopicPrefix
+	closed      int32                             // set by Close, the bus takes no more subscriptions
+	ordered     bool                              // queue async deliveries per handler, see WithOrderedAsync
+	failure     FailurePolicy                     // policy of the handlers subscribed without one
+	tags        tagSet                            // controls of the subscription tags, see WithTags
+	memory      *memoryAccount                    // memory held by the bus, nil unless counted
+	entities    map[string]bool                   // topics subscribed by SubscribeEntity
+	dropped     []string                          // entity topics left to reclaim once Publish returns
+	wildcards   int32                             // SubscribeEntities was called, accessed atomically
+	trees       int32                             // SubscribeTree was called, accessed atomically
+	feeds       map[*ChangeFeed]bool              // change feeds publishing to the bus, stopped by Close
+	middleware  atomic.Value                      // []Middleware wrapping every publish, see Use
+	panics      atomic.Value                      // PanicHandler recovering the panics of handlers, see SetPanicHandler
+	deadLetters atomic.Value                      // DeadLetterHandler receiving the events no handler got, see SetDeadLetterHandler
+	stickies    sync.Map                          // *stickyTopic per topic, see PublishSticky
+	declared    map[string]TopicDeclaration       // topics documented by DeclareTopic
+	schemas     sync.Map                          // topic to the *JSONSchema of its payload, see ImportAsyncAPI
+	scope       Scope                             // goroutines spawned by handlers, see Scope
+	equality    HandlerEquality                   // matches the handlers given to Unsubscribe, by code pointer when nil
 }
 
 type eventHandler struct {
@@ -45,63 +93,126 @@ type eventHandler struct {
 	flagOnce      bool
 	async         bool
 	transactional bool
-	sync.Mutex    // lock for an event handler - useful for running async callbacks serially
+	sources       []paramSource // where the parameters come from, nil when all are published arguments
+	tolerant      bool          // drop surplus arguments and zero missing ones instead of failing
+	flag          string        // feature flag gating deliveries, see WithEnabledWhen
+	failure       FailurePolicy // policy of the handler, the bus policy when zero
+	tags          []string      // see WithTags
+	priority      int           // handlers of higher priority are called first, see WithPriority
+	key           interface{}   // identifies the handler to UnsubscribeKey, see WithKey
+	executor      Executor      // runs the deliveries of the async handler, see WithExecutor
+	tree          bool          // receives the events of the descendants of its topic, see SubscribeTree
+	*handlerState               // kept by the handler replacing it, see Replace
+}
+
+// handlerState - what a handler updates while events are delivered to it, the other fields of
+// eventHandler never change once it is subscribed
+type handlerState struct {
+	queue    handlerQueue    // deliveries waiting for a transactional handler, or any async one WithOrderedAsync
+	counters handlerCounters // calls of the handler, see Stats
+	claimed  int32           // set atomically by the single delivery of a once handler, see claim
+}
+
+func newEventHandler(fn interface{}, flagOnce, async, transactional bool) *eventHandler {
+	callBack := reflect.ValueOf(fn)
+	return &eventHandler{
+		callBack:      callBack,
+		flagOnce:      flagOnce,
+		async:         async,
+		transactional: transactional,
+		sources:       paramSources(callBack),
+		handlerState:  &handlerState{},
+	}
+}
+
+// claim reports whether an event may be delivered to the handler: always, unless it is a once
+// handler which was delivered one already, concurrent publishes claiming it at most once
+func (handler *eventHandler) claim() bool {
+	return !handler.flagOnce || atomic.CompareAndSwapInt32(&handler.claimed, 0, 1)
+}
+
+// name returns the name of the handler's function, as reported by the runtime
+func (handler *eventHandler) name() string {
+	if fn := runtime.FuncForPC(handler.callBack.Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return handler.callBack.Type().String()
 }
 
 // New returns new EventBus with empty handlers.
 func New() Bus {
 	b := &EventBus{
-		make(map[string][]*eventHandler),
-		sync.Mutex{},
-		sync.WaitGroup{},
+		handlers:   make(map[string][]*eventHandler),
+		emitters:   make(map[*emitter]bool),
+		schedulers: make(map[*Scheduler]bool),
+	}
+	b.scope.bus = b
+	if detectMutations() {
+		WithMutationDetection(nil)(b)
 	}
 	return Bus(b)
 }
 
 // doSubscribe handles the subscription logic and is utilized by the public Subscribe functions
 func (bus *EventBus) doSubscribe(topic string, fn interface{}, handler *eventHandler) error {
+	if sticky, ok := bus.stickies.Load(topic); ok {
+		return bus.subscribeSticky(sticky.(*stickyTopic), topic, fn, handler)
+	}
+	return bus.addHandler(topic, fn, handler)
+}
+
+func (bus *EventBus) addHandler(topic string, fn interface{}, handler *eventHandler) error {
 	bus.lock.Lock()
 	defer bus.lock.Unlock()
-	if !(reflect.TypeOf(fn).Kind() == reflect.Func) {
-		return fmt.Errorf("%s is not of type reflect.Func", reflect.TypeOf(fn).Kind())
+	if bus.sealedTable() != nil {
+		return ErrSealed
 	}
-	bus.handlers[topic] = append(bus.handlers[topic], handler)
+	if atomic.LoadInt32(&bus.closed) != 0 {
+		return ErrBusClosed
+	}
+	if err := checkFunc(fn); err != nil {
+		return err
+	}
+	if err := handler.checkEventParams(); err != nil {
+		return err
+	}
+	bus.setHandlers(topic, insertByPriority(bus.handlers[topic], handler))
 	return nil
 }
 
+// subscribeHandler subscribes fn and returns its handler, helpers use it to unsubscribe
+// exactly their own handler later on with removeHandlerPtr
+func (bus *EventBus) subscribeHandler(topic string, fn interface{}, flagOnce, async, transactional bool) (*eventHandler, error) {
+	handler := newEventHandler(fn, flagOnce, async, transactional)
+	return handler, bus.doSubscribe(topic, fn, handler)
+}
+
 // Subscribe subscribes to a topic.
 // Returns error if `fn` is not a function.
 func (bus *EventBus) Subscribe(topic string, fn interface{}) error {
-	return bus.doSubscribe(topic, fn, &eventHandler{
-		reflect.ValueOf(fn), false, false, false, sync.Mutex{},
-	})
+	return bus.doSubscribe(topic, fn, newEventHandler(fn, false, false, false))
 }
 
 // SubscribeAsync subscribes to a topic with an asynchronous callback
 // Transactional determines whether subsequent callbacks for a topic are
-// run serially (true) or concurrently (false)
+// run serially (true) or concurrently (false). Deliveries to a transactional
+// callback wait in a FIFO queue of their own, so Publish never waits for it.
 // Returns error if `fn` is not a function.
 func (bus *EventBus) SubscribeAsync(topic string, fn interface{}, transactional bool) error {
-	return bus.doSubscribe(topic, fn, &eventHandler{
-		reflect.ValueOf(fn), false, true, transactional, sync.Mutex{},
-	})
+	return bus.doSubscribe(topic, fn, newEventHandler(fn, false, true, transactional))
 }
 
 // SubscribeOnce subscribes to a topic once. Handler will be removed after executing.
 // Returns error if `fn` is not a function.
 func (bus *EventBus) SubscribeOnce(topic string, fn interface{}) error {
-	return bus.doSubscribe(topic, fn, &eventHandler{
-		reflect.ValueOf(fn), true, false, false, sync.Mutex{},
-	})
+	return bus.doSubscribe(topic, fn, newEventHandler(fn, true, false, false))
 }
 
 // SubscribeOnceAsync subscribes to a topic once with an asynchronous callback
 // Handler will be removed after executing.
 // Returns error if `fn` is not a function.
 func (bus *EventBus) SubscribeOnceAsync(topic string, fn interface{}) error {
-	return bus.doSubscribe(topic, fn, &eventHandler{
-		reflect.ValueOf(fn), true, true, false, sync.Mutex{},
-	})
+	return bus.doSubscribe(topic, fn, newEventHandler(fn, true, true, false))
 }
 
 // HasCallback returns true if exists any callback subscribed to the topic.
@@ -116,77 +227,296 @@ func (bus *EventBus) HasCallback(topic string) bool {
 }
 
 // Unsubscribe removes callback defined for a topic.
-// Returns error if there are no callbacks subscribed to the topic.
+// Returns ErrTopicNotFound if there are no callbacks subscribed to the topic,
+// *ErrHandlerNotFound if handler is not one of them.
 func (bus *EventBus) Unsubscribe(topic string, handler interface{}) error {
+	if err := checkFunc(handler); err != nil {
+		return err
+	}
 	bus.lock.Lock()
 	defer bus.lock.Unlock()
-	if _, ok := bus.handlers[topic]; ok && len(bus.handlers[topic]) > 0 {
-		bus.removeHandler(topic, bus.findHandlerIdx(topic, reflect.ValueOf(handler)))
-		return nil
+	if bus.sealedTable() != nil {
+		return ErrSealed
 	}
-	return fmt.Errorf("topic %s doesn't exist", topic)
+	if len(bus.handlers[topic]) == 0 {
+		return fmt.Errorf("topic %s: %w", topic, ErrTopicNotFound)
+	}
+	idx := bus.findHandlerIdx(topic, reflect.ValueOf(handler))
+	if idx < 0 || !bus.handlers[topic][idx].claim() {
+		// a once handler claimed by a Publish is gone already, Publish removes it
+		return handlerNotFound(topic, handler)
+	}
+	bus.removeHandler(topic, idx)
+	return nil
 }
 
 // Publish executes callback defined for a topic. Any additional argument will be transferred to the callback.
 func (bus *EventBus) Publish(topic string, args ...interface{}) {
-	bus.lock.Lock() // will unlock if handler is not found or always after setUpPublish
-	defer bus.lock.Unlock()
-	if handlers, ok := bus.handlers[topic]; ok && 0 < len(handlers) {
-		// Handlers slice may be changed by removeHandler and Unsubscribe during iteration,
-		// so make a copy and iterate the copied slice.
-		copyHandlers := make([]*eventHandler, len(handlers))
-		copy(copyHandlers, handlers)
-		for i, handler := range copyHandlers {
-			if handler.flagOnce {
-				bus.removeHandler(topic, i)
+	bus.publishHeaders(context.Background(), topic, nil, args)
+}
+
+// PublishWithHeaders is Publish attaching headers to the event, handlers read them from
+// EventMeta or a Headers parameter. See CorrelationIDHeader.
+func (bus *EventBus) PublishWithHeaders(topic string, headers Headers, args ...interface{}) {
+	bus.publishHeaders(context.Background(), topic, headers, args)
+}
+
+// PublishEx is Publish returning how many handlers received the event, async ones counting once
+// started or queued, so publishers of critical events can tell when nobody handled them.
+func (bus *EventBus) PublishEx(topic string, args ...interface{}) (delivered int) {
+	return bus.publishHeaders(context.Background(), topic, nil, args)
+}
+
+// PublishWithContext is Publish handing ctx to the handlers declaring a context.Context parameter,
+// e.g. the context of an HTTP request. Once ctx is done the handlers not called yet are skipped,
+// and so are the async deliveries which did not start, those running see ctx cancelled.
+func (bus *EventBus) PublishWithContext(ctx context.Context, topic string, args ...interface{}) {
+	bus.publishHeaders(ctx, topic, nil, args)
+}
+
+func (bus *EventBus) publishHeaders(ctx context.Context, topic string, headers Headers, args []interface{}) (delivered int) {
+	if chain := bus.middlewares(); len(chain) > 0 {
+		// copied, as middlewares may replace arguments of a slice owned by the publisher
+		args = append([]interface{}(nil), args...)
+		intercept(chain, topic, args, func() { delivered = bus.dispatch(ctx, topic, headers, args) })
+		return delivered
+	}
+	return bus.dispatch(ctx, topic, headers, args)
+}
+
+// dispatch delivers a published event once the middlewares let it through
+func (bus *EventBus) dispatch(ctx context.Context, topic string, headers Headers, args []interface{}) (delivered int) {
+	if strings.HasPrefix(topic, CancelTopicPrefix) {
+		bus.running.cancel(strings.TrimPrefix(topic, CancelTopicPrefix))
+	}
+	handlers, ring := bus.published(topic)
+	inline, delivered := bus.publish(ctx, handlers, ring, topic, headers, args)
+	for _, run := range inline {
+		run()
+	}
+	if delivered == 0 && ctx.Err() == nil {
+		bus.deadLetter(topic, headers, args)
+	}
+	bus.limitMemory()
+	return delivered
+}
+
+// publish delivers the event to the handlers without the bus lock, a handler unsubscribed
+// meanwhile may still receive it. Async deliveries which must run on the calling goroutine
+// (WithInlineAsync) are returned with the number of handlers the event was delivered to.
+func (bus *EventBus) publish(ctx context.Context, handlers []*eventHandler, ring *traceRing, topic string, headers Headers, args []interface{}) (inline []func(), delivered int) {
+	record := ring.begin(topic, args)
+	defer ring.end(record)
+	claimed := false
+	defer func() {
+		if claimed {
+			// once the event is no longer pending, see dropTopic
+			bus.lock.Lock()
+			bus.reclaimDropped()
+			bus.lock.Unlock()
+		}
+	}()
+	env := bus.newEnvelope(ctx, topic, headers, args)
+	defer env.progress.done(env.seq)
+	bus.stats.record(topic, len(handlers))
+	if len(handlers) == 0 {
+		return nil, 0
+	}
+	bus.validateAll(topic, handlers, args)
+	for _, handler := range handlers {
+		if ctx.Err() != nil {
+			break // the remaining handlers are skipped, see PublishWithContext
+		}
+		if !bus.flags.enabled(handler.flag) || !bus.tags.allow(handler) {
+			continue
+		}
+		if handler.flagOnce {
+			if !handler.claim() {
+				continue // delivered by a concurrent Publish
 			}
-			if !handler.async {
-				bus.doPublish(handler, topic, args...)
-			} else {
-				bus.wg.Add(1)
-				if handler.transactional {
-					bus.lock.Unlock()
-					handler.Lock()
-					bus.lock.Lock()
-				}
-				go bus.doPublishAsync(handler, topic, args...)
+			claimed = true
+			bus.lock.Lock()
+			bus.removeOnce(topic, handler)
+			bus.lock.Unlock()
+		}
+		delivered++
+		run, failure := bus.deliver(handler, ring, record, env)
+		if run != nil {
+			inline = append(inline, run)
+		}
+		if failure != nil {
+			inline = append(inline, func() { bus.report(failure) })
+			if bus.failurePolicy(handler) == FailFast && failure.Recovered == nil {
+				break
 			}
 		}
 	}
+	return inline, delivered
 }
 
-func (bus *EventBus) doPublish(handler *eventHandler, topic string, args ...interface{}) {
-	passedArguments := bus.setUpPublish(handler, args...)
-	handler.callBack.Call(passedArguments)
+// removeOnce removes a claimed once handler of the topic, of every entity of its kind, or of an
+// ancestor. It reports false when the handler is gone already. The bus lock must be held.
+func (bus *EventBus) removeOnce(topic string, handler *eventHandler) bool {
+	if idx := bus.findHandlerPtrIdx(topic, handler); idx >= 0 {
+		bus.removeHandler(topic, idx)
+		return true
+	}
+	if wildcard, ok := bus.wildcardOf(topic); ok {
+		if idx := bus.findHandlerPtrIdx(wildcard, handler); idx >= 0 {
+			bus.removeHandler(wildcard, idx)
+			return true
+		}
+	}
+	return bus.removeTreeOnce(topic, handler)
 }
 
-func (bus *EventBus) doPublishAsync(handler *eventHandler, topic string, args ...interface{}) {
-	defer bus.wg.Done()
-	if handler.transactional {
-		defer handler.Unlock()
+// newEnvelope wraps a published event, numbered in its topic and identified when the bus has an IDGenerator
+func (bus *EventBus) newEnvelope(ctx context.Context, topic string, headers Headers, args []interface{}) *envelope {
+	env := newEnvelope(topic, args)
+	env.ctx = ctx
+	env.meta.Headers = headers
+	if bus.ids != nil {
+		env.meta.ID = bus.ids()
+	}
+	for ok := false; !ok; {
+		env.progress = bus.progressOf(topic)
+		env.seq, ok = env.progress.begin()
 	}
-	bus.doPublish(handler, topic, args...)
+	env.meta.Seq = env.seq
+	bus.recordCausation(env.meta)
+	return env
 }
 
-func (bus *EventBus) removeHandler(topic string, idx int) {
-	if _, ok := bus.handlers[topic]; !ok {
+// validateAll panics unless args suit every handler and the payload schema of the topic, when
+// the bus validates arguments
+func (bus *EventBus) validateAll(topic string, handlers []*eventHandler, args []interface{}) {
+	if !bus.validate {
 		return
 	}
-	l := len(bus.handlers[topic])
+	if err := bus.ValidateEvent(topic, args...); err != nil {
+		panic(err)
+	}
+	for _, handler := range handlers {
+		if err := validateArgs(handler, args); err != nil {
+			panic(fmt.Errorf("topic %s: %v", topic, err))
+		}
+	}
+}
 
-	if !(0 <= idx && idx < l) {
-		return
+// deliver hands the event to a single handler, the delivery is returned instead when it must
+// run on the calling goroutine once the lock is released (WithInlineAsync). The failure of a
+// synchronous handler is returned to be reported once the lock is released. Deliveries to a
+// transactional handler are queued, so the bus lock is never held waiting for one.
+func (bus *EventBus) deliver(handler *eventHandler, ring *traceRing, record *TraceRecord, env *envelope) (func(), *HandlerFailure) {
+	ticket := ring.deliver(record, handler)
+	if handler.async && bus.cloner != nil {
+		env = env.clone(bus.cloner)
+	}
+	if !handler.async {
+		return nil, bus.doPublishRecovering(handler, ticket, env)
+	} else if handler.executor != nil {
+		bus.wg.Add(1)
+		env.progress.add(env.seq)
+		release := bus.memory.hold(memoryQueues, env.args)
+		handler.executor.Execute(func() {
+			defer release()
+			bus.doPublishAsync(handler, ticket, env)
+		})
+	} else if bus.inlineAsync {
+		// serial on the calling goroutine already, no need for the transactional queue
+		bus.wg.Add(1)
+		env.progress.add(env.seq)
+		release := bus.memory.hold(memoryQueues, env.args)
+		return func() {
+			defer bus.wg.Done()
+			defer env.progress.done(env.seq)
+			defer release()
+			bus.report(bus.doPublishRecovering(handler, ticket, env))
+		}, nil
+	} else if bus.ordered || handler.transactional {
+		// a FIFO queue per handler drained by a single goroutine at a time
+		bus.wg.Add(1)
+		env.progress.add(env.seq)
+		release := bus.memory.hold(memoryQueues, env.args)
+		handler.queue.push(bus.pool(), func() {
+			defer bus.wg.Done()
+			defer env.progress.done(env.seq)
+			defer release()
+			bus.report(bus.doPublishRecovering(handler, ticket, env))
+		})
+	} else {
+		bus.wg.Add(1)
+		env.progress.add(env.seq)
+		release := bus.memory.hold(memoryQueues, env.args)
+		bus.pool().run(func() {
+			defer release()
+			bus.doPublishAsync(handler, ticket, env)
+		})
+	}
+	return nil, nil
+}
+
+// doPublish calls the handler, its failure is returned when it has a failure policy
+func (bus *EventBus) doPublish(handler *eventHandler, ticket *traceTicket, env *envelope) *HandlerFailure {
+	if env.ctx.Err() != nil {
+		// published WithContext and cancelled before this delivery started
+		ticket.done(TraceCancelled, time.Now())
+		return nil
+	}
+	if id := env.meta.Headers[CorrelationIDHeader]; id != "" {
+		var done func()
+		env, done = bus.running.track(id, env)
+		defer done()
+	}
+	passedArguments := bus.setUpPublish(handler, env)
+	if ticket != nil {
+		started := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				ticket.done(TracePanicked, started)
+				panic(r)
+			}
+			ticket.done(TraceDelivered, started)
+		}()
 	}
+	if bus.inFlight != nil {
+		defer bus.inFlight.remove(bus.inFlight.add(handler, env))
+	}
+	if bus.mutations != nil {
+		defer newMutationCheck(env.args).verify(bus.mutations, handler, env)
+	}
+	return bus.call(handler, env, passedArguments)
+}
 
-	copy(bus.handlers[topic][idx:], bus.handlers[topic][idx+1:])
-	bus.handlers[topic][l-1] = nil // or the zero value of T
-	bus.handlers[topic] = bus.handlers[topic][:l-1]
+func (bus *EventBus) doPublishAsync(handler *eventHandler, ticket *traceTicket, env *envelope) {
+	defer bus.wg.Done()
+	defer env.progress.done(env.seq)
+	bus.report(bus.doPublishRecovering(handler, ticket, env))
+}
+
+func (bus *EventBus) removeHandler(topic string, idx int) {
+	handlers, ok := bus.handlers[topic]
+	if !ok || !(0 <= idx && idx < len(handlers)) {
+		return
+	}
+	if len(handlers) == 1 {
+		// dynamic topics would otherwise leave their entry behind, see GC
+		bus.dropTopic(topic)
+		return
+	}
+	// copied, Publish may be iterating the current slice without the lock
+	kept := make([]*eventHandler, 0, len(handlers)-1)
+	bus.setHandlers(topic, append(append(kept, handlers[:idx]...), handlers[idx+1:]...))
 }
 
 func (bus *EventBus) findHandlerIdx(topic string, callback reflect.Value) int {
 	if _, ok := bus.handlers[topic]; ok {
 		for idx, handler := range bus.handlers[topic] {
-			if handler.callBack.Type() == callback.Type() &&
+			if bus.equality != nil {
+				if bus.equality(handler.callBack.Interface(), callback.Interface()) {
+					return idx
+				}
+			} else if handler.callBack.Type() == callback.Type() &&
 				handler.callBack.Pointer() == callback.Pointer() {
 				return idx
 			}
@@ -195,18 +525,119 @@ func (bus *EventBus) findHandlerIdx(topic string, callback reflect.Value) int {
 	return -1
 }
 
-func (bus *EventBus) setUpPublish(callback *eventHandler, args ...interface{}) []reflect.Value {
+func (bus *EventBus) findHandlerPtrIdx(topic string, handler *eventHandler) int {
+	for idx, h := range bus.handlers[topic] {
+		if h == handler {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (bus *EventBus) setUpPublish(callback *eventHandler, env *envelope) []reflect.Value {
 	funcType := callback.callBack.Type()
+	args := env.args
+	if callback.takesEvent() {
+		args = nil
+	}
+	sources := callback.bind(len(args))
+	types := positionalTypes(funcType, sources)
+	if callback.tolerant {
+		args = tolerateArity(types, funcType.IsVariadic(), args)
+	}
 	passedArguments := make([]reflect.Value, len(args))
 	for i, v := range args {
 		if v == nil {
-			passedArguments[i] = reflect.New(funcType.In(i)).Elem()
+			passedArguments[i] = reflect.New(argumentType(types, funcType.IsVariadic(), i)).Elem()
 		} else {
 			passedArguments[i] = reflect.ValueOf(v)
 		}
 	}
+	if sources == nil {
+		return passedArguments
+	}
+
+	// interleave injected values with the published arguments, the variadic parameter comes last
+	injected := make([]reflect.Value, 0, len(sources)+len(passedArguments))
+	for i, source := range sources {
+		if source == fromScope {
+			injected = append(injected, reflect.ValueOf(&bus.scope))
+		} else if source != fromArgs {
+			injected = append(injected, env.injectedValue(source))
+		} else if len(passedArguments) > 0 && !(funcType.IsVariadic() && i == len(sources)-1) {
+			injected = append(injected, passedArguments[0])
+			passedArguments = passedArguments[1:]
+		}
+	}
+	return append(injected, passedArguments...)
+}
+
+// tolerateArity cuts surplus arguments and pads missing ones with nil, standing for zero values
+func tolerateArity(types []reflect.Type, variadic bool, args []interface{}) []interface{} {
+	want := len(types)
+	if variadic {
+		want--
+		if len(args) >= want {
+			return args
+		}
+	}
+	if len(args) > want {
+		return args[:want]
+	}
+	return append(append(make([]interface{}, 0, want), args...), make([]interface{}, want-len(args))...)
+}
+
+// argumentType returns the type of the i-th published argument given the types of the
+// parameters receiving published arguments, arguments beyond the last parameter of a
+// variadic function have its element type
+func argumentType(types []reflect.Type, variadic bool, i int) reflect.Type {
+	if variadic && i >= len(types)-1 {
+		return types[len(types)-1].Elem()
+	}
+	return types[i]
+}
+
+// validateArgs checks that the handler can be called with args,
+// nil arguments stand for the zero value of any parameter type
+func validateArgs(handler *eventHandler, args []interface{}) error {
+	funcType := handler.callBack.Type()
+	if handler.takesEvent() {
+		args = nil
+	}
+	types := positionalTypes(funcType, handler.bind(len(args)))
+	variadic := funcType.IsVariadic()
+	if handler.tolerant {
+		args = tolerateArity(types, variadic, args)
+	}
+	if variadic && len(args) < len(types)-1 || !variadic && len(args) != len(types) {
+		return fmt.Errorf("handler %s takes %d arguments, %d given", handler.name(), len(types), len(args))
+	}
+	for i, arg := range args {
+		if arg == nil {
+			continue
+		}
+		if argType := argumentType(types, variadic, i); !reflect.TypeOf(arg).AssignableTo(argType) {
+			return fmt.Errorf("handler %s argument %d: %T is not assignable to %s", handler.name(), i, arg, argType)
+		}
+	}
+	return nil
+}
 
-	return passedArguments
+// removeHandlerPtr removes exactly the given handler from a topic, it reports whether the handler
+// was still subscribed. A once handler claimed by a Publish is not: it is delivered the event and
+// removed by Publish.
+func (bus *EventBus) removeHandlerPtr(topic string, handler *eventHandler) bool {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return false
+	}
+	idx := bus.findHandlerPtrIdx(topic, handler)
+	if idx < 0 || !handler.claim() {
+		return false
+	}
+	bus.removeHandler(topic, idx)
+	return true
 }
 
 // WaitAsync waits for all async callbacks to complete
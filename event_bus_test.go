@@ -1,6 +1,8 @@
 package EventBus
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -135,6 +137,27 @@ func TestSubcribeOnceAsync(t *testing.T) {
 	}
 }
 
+func TestSubscribeOnceAsyncConcurrentPublish(t *testing.T) {
+	bus := New()
+	var calls int32
+	for i := 0; i < 10; i++ {
+		bus.SubscribeOnceAsync("topic", func() { atomic.AddInt32(&calls, 1) })
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bus.Publish("topic")
+		}()
+	}
+	wg.Wait()
+	bus.WaitAsync()
+	if atomic.LoadInt32(&calls) != 10 || bus.HasCallback("topic") {
+		t.Fatal(calls)
+	}
+}
+
 func TestSubscribeAsyncTransactional(t *testing.T) {
 	results := make([]int, 0)
 
@@ -159,6 +182,35 @@ func TestSubscribeAsyncTransactional(t *testing.T) {
 	}
 }
 
+func TestTransactionalQueue(t *testing.T) {
+	bus := New()
+	release := make(chan struct{})
+	var results []int
+	bus.SubscribeAsync("topic", func(a int) {
+		<-release
+		results = append(results, a)
+	}, true)
+	published := make(chan struct{})
+	go func() {
+		for i := 1; i <= 3; i++ {
+			bus.Publish("topic", i)
+		}
+		// the lock is free while the handler is busy
+		bus.Subscribe("other", func() {})
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish waited for the transactional handler")
+	}
+	close(release)
+	bus.WaitAsync()
+	if len(results) != 3 || results[0] != 1 || results[1] != 2 || results[2] != 3 {
+		t.Fatal(results)
+	}
+}
+
 func TestSubscribeAsync(t *testing.T) {
 	results := make(chan int)
 
@@ -188,3 +240,37 @@ func TestSubscribeAsync(t *testing.T) {
 	//	t.Fail()
 	//}
 }
+
+func TestManySubscribeOnceKeepsOtherHandlers(t *testing.T) {
+	bus := New()
+	event := "topic"
+	flag := 0
+	fn := func() { flag += 1 }
+	bus.SubscribeOnce(event, fn)
+	bus.SubscribeOnce(event, fn)
+	bus.Subscribe(event, fn)
+	bus.Publish(event)
+	bus.Publish(event)
+
+	if flag != 4 {
+		t.Fail()
+	}
+}
+
+func TestPublishEx(t *testing.T) {
+	bus := New().(*EventBus)
+	if delivered := bus.PublishEx("topic", 1); delivered != 0 {
+		t.Fatal(delivered)
+	}
+	bus.Subscribe("topic", func(n int) {})
+	bus.SubscribeAsync("topic", func(n int) {}, false)
+	bus.SubscribeOnce("topic", func(n int) {})
+	if delivered := bus.PublishEx("topic", 1); delivered != 3 {
+		t.Fatal(delivered)
+	}
+	bus.Seal()
+	if delivered := bus.PublishEx("topic", 2); delivered != 2 {
+		t.Fatal(delivered)
+	}
+	bus.WaitAsync()
+}
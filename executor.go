@@ -0,0 +1,100 @@
+package EventBus
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Executor - runs the deliveries of handlers subscribed WithExecutor. Execute is called with
+// the bus lock held: it must hand the task over without waiting for it to run.
+type Executor interface {
+	Execute(task func())
+}
+
+// ExecutorFunc - function used as an Executor, e.g. ExecutorFunc(fyne.Do) running the
+// deliveries on the Fyne UI goroutine
+type ExecutorFunc func(task func())
+
+// Execute calls fn with the task
+func (fn ExecutorFunc) Execute(task func()) {
+	fn(task)
+}
+
+// WithExecutor runs the handler asynchronously on exec instead of a goroutine of the bus, for
+// handlers which must run on a particular goroutine or thread, such as the UI goroutine of a
+// GUI toolkit or a goroutine locked to its OS thread for cgo. Deliveries run in the order and
+// with the concurrency exec gives them, and count for WaitAsync: do not wait on the goroutine
+// exec runs them on.
+func WithExecutor(exec Executor) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.async = true
+		handler.executor = exec
+	}
+}
+
+// LoopExecutor - Executor queuing tasks for the goroutine calling Run, e.g. the main goroutine
+type LoopExecutor struct {
+	lock    sync.Mutex
+	ready   *sync.Cond
+	tasks   []func()
+	stopped bool
+}
+
+// NewLoopExecutor returns an executor whose tasks run once a goroutine calls Run
+func NewLoopExecutor() *LoopExecutor {
+	loop := &LoopExecutor{}
+	loop.ready = sync.NewCond(&loop.lock)
+	return loop
+}
+
+// NewThreadExecutor returns an executor running its tasks, in order, on a goroutine locked to
+// its OS thread, until Stop.
+func NewThreadExecutor() *LoopExecutor {
+	loop := NewLoopExecutor()
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		loop.Run()
+	}()
+	return loop
+}
+
+// Execute queues the task. Tasks queued after Stop run on a goroutine of their own.
+func (loop *LoopExecutor) Execute(task func()) {
+	loop.lock.Lock()
+	defer loop.lock.Unlock()
+	if loop.stopped {
+		go task()
+		return
+	}
+	loop.tasks = append(loop.tasks, task)
+	loop.ready.Signal()
+}
+
+// Run runs the queued tasks in order on the calling goroutine until Stop, returning once
+// the tasks queued before Stop are done.
+func (loop *LoopExecutor) Run() {
+	for {
+		loop.lock.Lock()
+		for len(loop.tasks) == 0 && !loop.stopped {
+			loop.ready.Wait()
+		}
+		if len(loop.tasks) == 0 {
+			loop.lock.Unlock()
+			return
+		}
+		task := loop.tasks[0]
+		loop.tasks[0] = nil
+		loop.tasks = loop.tasks[1:]
+		loop.lock.Unlock()
+		task()
+	}
+}
+
+// Stop makes Run return once the queued tasks are done
+func (loop *LoopExecutor) Stop() {
+	loop.lock.Lock()
+	defer loop.lock.Unlock()
+	loop.stopped = true
+	loop.ready.Broadcast()
+}
@@ -0,0 +1,43 @@
+package EventBus
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoopExecutor(t *testing.T) {
+	bus := New().(*EventBus)
+	loop := NewLoopExecutor()
+	var got []int
+	bus.SubscribeWith("ui:update", func(n int) { got = append(got, n) }, WithExecutor(loop))
+	for i := 1; i <= 3; i++ {
+		bus.Publish("ui:update", i)
+	}
+	if len(got) != 0 {
+		t.Fatal("ran before the loop", got)
+	}
+	loop.Stop()
+	loop.Run() // on the test goroutine
+	bus.WaitAsync()
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatal(got)
+	}
+}
+
+func TestThreadExecutor(t *testing.T) {
+	bus := New().(*EventBus)
+	thread := NewThreadExecutor()
+	defer thread.Stop()
+	var calls, wrapped int32
+	bus.SubscribeWith("cgo:call", func() { atomic.AddInt32(&calls, 1) }, WithExecutor(thread), WithAsync(true))
+	bus.SubscribeWith("cgo:call", func() {}, WithExecutor(ExecutorFunc(func(task func()) {
+		atomic.AddInt32(&wrapped, 1)
+		go task()
+	})))
+	bus.Publish("cgo:call")
+	bus.Publish("cgo:call")
+	bus.WaitAsync()
+	if atomic.LoadInt32(&calls) != 2 || atomic.LoadInt32(&wrapped) != 2 {
+		t.Fatal(calls, wrapped)
+	}
+}
@@ -0,0 +1,145 @@
+package EventBus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	// HandlerFailedTopic - topic a HandlerFailure is published to when a handler with a
+	// failure policy returns an error or, continuing on failure, panics
+	HandlerFailedTopic = "bus:handler_failed"
+)
+
+// FailurePolicy - what a failing handler does to the delivery of the event to the remaining
+// handlers. Without a policy, panics reach the publisher and returned errors are ignored.
+type FailurePolicy int
+
+const (
+	// FailFast - a handler returning an error stops the delivery to the remaining synchronous
+	// handlers, a panic reaches the publisher. Suits command topics.
+	FailFast FailurePolicy = iota + 1
+	// ContinueOnFailure - errors and panics are recovered and reported, the delivery goes on.
+	// Suits notification topics.
+	ContinueOnFailure
+)
+
+// HandlerFailure - failure of a handler, published to HandlerFailedTopic
+type HandlerFailure struct {
+	Topic     string
+	Handler   string
+	Err       error       // error returned by the handler, or describing its panic
+	Recovered interface{} // value the handler panicked with, nil when it returned an error
+	fn        interface{} // the handler, for the PanicHandler
+	quiet     bool        // recovered for the PanicHandler only, not published
+}
+
+// WithFailurePolicy applies the policy to every handler subscribed without one of its own.
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return func(bus *EventBus) {
+		bus.failure = policy
+	}
+}
+
+// WithHandlerFailurePolicy applies the policy to the handler, whatever the policy of the bus.
+func WithHandlerFailurePolicy(policy FailurePolicy) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.failure = policy
+	}
+}
+
+func (bus *EventBus) failurePolicy(handler *eventHandler) FailurePolicy {
+	if handler.failure != 0 {
+		return handler.failure
+	}
+	return bus.failure
+}
+
+// call runs the handler with the arguments, applying its failure policy. The error of a sync
+// handler is also collected for PublishWithResult.
+func (bus *EventBus) call(handler *eventHandler, env *envelope, args []reflect.Value) (failure *HandlerFailure) {
+	policy := bus.failurePolicy(handler)
+	if policy == ContinueOnFailure {
+		defer func() {
+			if r := recover(); r != nil {
+				failure = handler.panicked(env, r)
+				if !handler.async {
+					collectError(env, failure.Err)
+				}
+			}
+		}()
+	}
+	started, returned := time.Now(), false
+	defer func() {
+		if !returned {
+			handler.counters.record(started, false, true)
+		}
+	}()
+	results := handler.callBack.Call(args)
+	returned = true
+	if len(results) == 0 {
+		handler.counters.record(started, false, false)
+		return nil
+	}
+	err, ok := results[len(results)-1].Interface().(error)
+	if !ok || err == nil {
+		handler.counters.record(started, false, false)
+		return nil
+	}
+	handler.counters.record(started, true, false)
+	if !handler.async {
+		collectError(env, err)
+	}
+	if policy == 0 {
+		return nil
+	}
+	return &HandlerFailure{Topic: env.meta.Topic, Handler: handler.name(), Err: err}
+}
+
+// panicked returns the failure of the handler panicking with recovered
+func (handler *eventHandler) panicked(env *envelope, recovered interface{}) *HandlerFailure {
+	err := fmt.Errorf("handler panicked: %v", recovered)
+	return &HandlerFailure{Topic: env.meta.Topic, Handler: handler.name(), Err: err, Recovered: recovered, fn: handler.callBack.Interface()}
+}
+
+// publishResults - errors of the sync handlers of an event published by PublishWithResult
+type publishResults struct {
+	errs []error // appended by the sync handlers, on the publishing goroutine
+}
+
+// resultsKey - context key of the publishResults of an event
+type resultsKey struct{}
+
+func collectError(env *envelope, err error) {
+	if results, ok := env.ctx.Value(resultsKey{}).(*publishResults); ok {
+		results.errs = append(results.errs, err)
+	}
+}
+
+// PublishWithResult is Publish returning the errors of the sync handlers declaring an error as
+// last result, in delivery order, nil when none failed. A panic recovered by ContinueOnFailure
+// counts as an error, a FailFast failure ends the delivery as with Publish. Async handlers are not
+// waited for, their errors are reported through HandlerFailedTopic by a failure policy.
+func (bus *EventBus) PublishWithResult(topic string, args ...interface{}) []error {
+	results := &publishResults{}
+	bus.publishHeaders(context.WithValue(context.Background(), resultsKey{}, results), topic, nil, args)
+	return results.errs
+}
+
+// report hands a recovered panic to the PanicHandler and publishes the failure, unless it is
+// nil, quiet or a handler of HandlerFailedTopic failed. The bus must not be locked.
+func (bus *EventBus) report(failure *HandlerFailure) {
+	if failure == nil {
+		return
+	}
+	if failure.Recovered != nil {
+		if handle, _ := bus.panics.Load().(PanicHandler); handle != nil {
+			handle(failure.Topic, failure.fn, failure.Recovered)
+		}
+	}
+	if !failure.quiet && failure.Topic != HandlerFailedTopic {
+		bus.Publish(HandlerFailedTopic, *failure)
+	}
+}
@@ -0,0 +1,81 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFailFast(t *testing.T) {
+	bus := NewWithOptions(WithFailurePolicy(FailFast)).(*EventBus)
+	var failures []HandlerFailure
+	bus.Subscribe(HandlerFailedTopic, func(failure HandlerFailure) { failures = append(failures, failure) })
+	reached := false
+	bus.Subscribe("command", func() error { return errors.New("rejected") })
+	bus.Subscribe("command", func() { reached = true })
+	if delivered := bus.PublishEx("command"); delivered != 1 || reached {
+		t.Fatal(delivered, reached)
+	}
+	if len(failures) != 1 || failures[0].Topic != "command" || failures[0].Err.Error() != "rejected" || failures[0].Recovered != nil {
+		t.Fatal(failures)
+	}
+
+	bus.Subscribe("panic", func() { panic("boom") })
+	defer func() {
+		if recover() != "boom" {
+			t.Fail()
+		}
+	}()
+	bus.Publish("panic")
+}
+
+func TestContinueOnFailure(t *testing.T) {
+	bus := NewWithOptions(WithFailurePolicy(FailFast)).(*EventBus)
+	var failures []HandlerFailure
+	bus.Subscribe(HandlerFailedTopic, func(failure HandlerFailure) { failures = append(failures, failure) })
+	reached := 0
+	continueOnFailure := WithHandlerFailurePolicy(ContinueOnFailure)
+	bus.SubscribeWith("notification", func() { panic("boom") }, continueOnFailure)
+	bus.SubscribeWith("notification", func() error { return errors.New("unreachable") }, continueOnFailure)
+	bus.SubscribeWith("notification", func() { reached++ }, continueOnFailure)
+	bus.SubscribeWith("notification", func() { panic("async") }, continueOnFailure, WithAsync(false))
+	bus.Publish("notification")
+	bus.WaitAsync()
+	if reached != 1 || len(failures) != 3 || failures[0].Recovered != "boom" || failures[1].Err.Error() != "unreachable" {
+		t.Fatal(reached, failures)
+	}
+}
+
+func TestNoFailurePolicy(t *testing.T) {
+	bus := New()
+	reached := false
+	bus.Subscribe(HandlerFailedTopic, func(failure HandlerFailure) { t.Fail() })
+	bus.Subscribe("topic", func() error { return errors.New("ignored") })
+	bus.Subscribe("topic", func() { reached = true })
+	bus.Publish("topic")
+	if !reached {
+		t.Fail()
+	}
+}
+
+func TestPublishWithResult(t *testing.T) {
+	bus := New().(*EventBus)
+	errInvalid := errors.New("invalid")
+	bus.Subscribe("order", func(n int) error { return nil })
+	bus.Subscribe("order", func(n int) error {
+		if n < 0 {
+			return errInvalid
+		}
+		return nil
+	})
+	bus.SubscribeWith("order", func(n int) { panic("boom") }, WithHandlerFailurePolicy(ContinueOnFailure))
+	bus.SubscribeAsync("order", func(n int) error { return errors.New("async") }, false)
+	if errs := bus.PublishWithResult("order", -1); len(errs) != 2 || errs[0] != errInvalid || errs[1].Error() != "handler panicked: boom" {
+		t.Fatal(errs)
+	}
+	bus.WaitAsync()
+
+	bus.Subscribe("quiet", func() error { return nil })
+	if errs := bus.PublishWithResult("quiet"); errs != nil {
+		t.Fatal(errs)
+	}
+}
@@ -0,0 +1,225 @@
+package EventBus
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Federation - bus partitioned into shards, in this process or others: the events of a topic
+// are published to the shard owning the hash of their ordering key, so keyed workloads scale
+// horizontally while the events of a key keep their order on a single shard. Each shard runs
+// the handlers of its keys, subscribed to its own bus.
+type Federation struct {
+	lock     sync.RWMutex
+	members  []federationMember
+	keys     map[string]func(args []interface{}) string // ordering key per topic, see KeyBy
+	replicas int                                        // points per member on the hash ring, 0 to pick by modulo
+	ring     []ringPoint                                // sorted by hash
+
+	policy      *PartitionPolicy           // see SetPartitionPolicy
+	scoped      map[string]bool            // cluster-scoped topics of the policy
+	partitioned map[string]*partitionState // unreachable nodes by name
+	done        chan struct{}              // closed to stop checking the nodes
+}
+
+// federationMember - shard of a Federation and the name of its node
+type federationMember struct {
+	node  string
+	shard BusPublisher
+}
+
+// ringPoint - point of a member on the hash ring, owning the hashes from the previous point
+type ringPoint struct {
+	hash   uint32
+	member int
+}
+
+// NewFederation returns a federation of the shards, typically local buses and RemoteShard
+// values, named "0", "1" and so on. The owner of a key is its hash modulo the number of shards,
+// so every process publishing to the federation must list the same shards in the same order,
+// and most keys move when a shard joins or leaves.
+func NewFederation(shards ...BusPublisher) *Federation {
+	federation := &Federation{keys: make(map[string]func(args []interface{}) string)}
+	for i, shard := range shards {
+		federation.members = append(federation.members, federationMember{strconv.Itoa(i), shard})
+	}
+	return federation
+}
+
+// NewConsistentFederation returns an empty federation picking the owner of a key by consistent
+// hashing, replicas points per node on the hash ring, e.g. 128. Owners depend on the names of
+// the nodes only, not on the order they joined in, and a node joining or leaving moves only the
+// keys it takes or gives back, so per-key state stays sticky to a node.
+func NewConsistentFederation(replicas int) *Federation {
+	if replicas <= 0 {
+		replicas = 1
+	}
+	return &Federation{keys: make(map[string]func(args []interface{}) string), replicas: replicas}
+}
+
+// Join adds the shard of a node to the federation, replacing the shard of a node of the same name
+func (federation *Federation) Join(node string, shard BusPublisher) {
+	federation.lock.Lock()
+	defer federation.lock.Unlock()
+	for i, member := range federation.members {
+		if member.node == node {
+			federation.members[i].shard = shard
+			return
+		}
+	}
+	federation.members = append(federation.members, federationMember{node, shard})
+	federation.buildRing()
+}
+
+// Leave removes the shard of a node, its keys moving to the others. It reports whether the node
+// was a member.
+func (federation *Federation) Leave(node string) bool {
+	federation.lock.Lock()
+	defer federation.lock.Unlock()
+	for i, member := range federation.members {
+		if member.node == node {
+			federation.members = append(federation.members[:i:i], federation.members[i+1:]...)
+			delete(federation.partitioned, node)
+			federation.buildRing()
+			return true
+		}
+	}
+	return false
+}
+
+// Nodes returns the names of the members in the order they joined
+func (federation *Federation) Nodes() []string {
+	federation.lock.RLock()
+	defer federation.lock.RUnlock()
+	nodes := make([]string, len(federation.members))
+	for i, member := range federation.members {
+		nodes[i] = member.node
+	}
+	return nodes
+}
+
+// buildRing places the members on the hash ring of a consistent federation, the lock must be held
+func (federation *Federation) buildRing() {
+	if federation.replicas == 0 {
+		return
+	}
+	federation.ring = federation.ring[:0]
+	for i, member := range federation.members {
+		for replica := 0; replica < federation.replicas; replica++ {
+			federation.ring = append(federation.ring, ringPoint{hashOf(member.node + "#" + strconv.Itoa(replica)), i})
+		}
+	}
+	sort.Slice(federation.ring, func(i, j int) bool {
+		a, b := federation.ring[i], federation.ring[j]
+		if a.hash == b.hash {
+			// the same owner whatever the order the nodes joined in
+			return federation.members[a.member].node < federation.members[b.member].node
+		}
+		return a.hash < b.hash
+	})
+}
+
+// KeyBy sets the function returning the ordering key of the events of the topic, e.g. the ID of
+// the order they concern. Events of topics without a key are all published to the shard owning
+// the topic.
+func (federation *Federation) KeyBy(topic string, key func(args []interface{}) string) {
+	federation.lock.Lock()
+	defer federation.lock.Unlock()
+	federation.keys[topic] = key
+}
+
+// ShardOf returns the index of the shard the event is published to, -1 without shards
+func (federation *Federation) ShardOf(topic string, args ...interface{}) int {
+	federation.lock.RLock()
+	defer federation.lock.RUnlock()
+	return federation.owner(topic, args)
+}
+
+// NodeOf returns the name of the node the event is published to, empty without shards
+func (federation *Federation) NodeOf(topic string, args ...interface{}) string {
+	federation.lock.RLock()
+	defer federation.lock.RUnlock()
+	if i := federation.owner(topic, args); i >= 0 {
+		return federation.members[i].node
+	}
+	return ""
+}
+
+// owner returns the index of the member owning the event, the lock must be held
+func (federation *Federation) owner(topic string, args []interface{}) int {
+	if len(federation.members) == 0 {
+		return -1
+	}
+	hash := hashOf(topic)
+	if key, ok := federation.keys[topic]; ok {
+		hash = hashOf(key(args))
+	}
+	if federation.replicas == 0 {
+		return int(hash % uint32(len(federation.members)))
+	}
+	i := sort.Search(len(federation.ring), func(i int) bool { return federation.ring[i].hash >= hash })
+	if i == len(federation.ring) {
+		i = 0
+	}
+	return federation.ring[i].member
+}
+
+func hashOf(s string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(s))
+	return hash.Sum32()
+}
+
+// Shard returns the i-th shard
+func (federation *Federation) Shard(i int) BusPublisher {
+	federation.lock.RLock()
+	defer federation.lock.RUnlock()
+	return federation.members[i].shard
+}
+
+// Publish publishes the event to the shard owning its key, events published to a federation
+// without shards are dropped. Events owned by an unreachable node are handled according to the
+// PartitionPolicy.
+func (federation *Federation) Publish(topic string, args ...interface{}) {
+	federation.lock.RLock()
+	i := federation.owner(topic, args)
+	var member federationMember
+	if i >= 0 {
+		member = federation.members[i]
+	}
+	kept := i >= 0 && federation.keeps(member.node, topic)
+	federation.lock.RUnlock()
+	if kept {
+		federation.keep(member.node, member.shard, topic, args)
+	} else if member.shard != nil {
+		member.shard.Publish(topic, args...)
+	}
+}
+
+// RemoteShard - shard of a Federation in another process, a Client there publishing the events
+// received by its service to its bus. Events are sent in the background, in publish order; those
+// published while the process is unreachable are dropped.
+type RemoteShard struct {
+	box    *outbox
+	server remoteServer // the Client, see Ping
+}
+
+// NewRemoteShard returns the shard served by the Client started at address and path
+func NewRemoteShard(address, path string) *RemoteShard {
+	return &RemoteShard{box: &outbox{client: address + path, send: func(event *remoteEvent) error {
+		return sendEvent(address, path, event)
+	}}, server: remoteServer{address, path}}
+}
+
+// Publish sends the event to the remote bus
+func (shard *RemoteShard) Publish(topic string, args ...interface{}) {
+	shard.box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: topic, Args: args}, time.Now()})
+}
+
+// Status returns the state of the connection to the remote process
+func (shard *RemoteShard) Status() BridgeStatus {
+	return shard.box.status()
+}
@@ -0,0 +1,122 @@
+package EventBus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFederation(t *testing.T) {
+	shards := []*EventBus{New().(*EventBus), New().(*EventBus), New().(*EventBus)}
+	federation := NewFederation(shards[0], shards[1], shards[2])
+	federation.KeyBy("order:updated", func(args []interface{}) string { return args[0].(string) })
+	owners := make(map[string]int)
+	seen := make([][]string, len(shards))
+	for i, shard := range shards {
+		i := i
+		shard.Subscribe("order:updated", func(id string, version int) {
+			seen[i] = append(seen[i], fmt.Sprint(id, "@", version))
+			if owner, ok := owners[id]; ok && owner != i {
+				t.Fatal(id, "on shards", owner, i)
+			}
+			owners[id] = i
+		})
+	}
+	for version := 1; version <= 3; version++ {
+		for id := 0; id < 30; id++ {
+			federation.Publish("order:updated", fmt.Sprint("o-", id), version)
+		}
+	}
+	for i := range shards {
+		// keys are spread over every shard, the versions of a key in order
+		if len(seen[i]) == 0 || len(seen[i])%3 != 0 {
+			t.Fatal(i, seen[i])
+		}
+	}
+	if len(owners) != 30 || federation.ShardOf("order:updated", "o-7", 1) != owners["o-7"] {
+		t.Fatal(owners)
+	}
+	if federation.ShardOf("unkeyed", 1) != federation.ShardOf("unkeyed", 2) {
+		t.Fatal("unkeyed topic spread over shards")
+	}
+}
+
+func TestRemoteShard(t *testing.T) {
+	client := NewClient("localhost:2135", "/_client_bus_shard", New())
+	if err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Stop()
+	received := make(chan string, 1)
+	client.EventBus().Subscribe("order:updated", func(id string) { received <- id })
+
+	shard := NewRemoteShard("localhost:2135", "/_client_bus_shard")
+	NewFederation(shard).Publish("order:updated", "o-1")
+	select {
+	case id := <-received:
+		if id != "o-1" || !shard.Status().Connected {
+			t.Fatal(id, shard.Status())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received by the remote shard")
+	}
+}
+
+func TestConsistentFederation(t *testing.T) {
+	shards := map[string]*EventBus{"node-a": New().(*EventBus), "node-b": New().(*EventBus), "node-c": New().(*EventBus)}
+	federation := NewConsistentFederation(64)
+	federation.KeyBy("cart", func(args []interface{}) string { return args[0].(string) })
+	federation.Join("node-a", shards["node-a"])
+	federation.Join("node-b", shards["node-b"])
+	owners := make(map[string]string)
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprint("cart-", i)
+		owners[key] = federation.NodeOf("cart", key)
+	}
+
+	// a joining node takes keys from the others, none move between them
+	federation.Join("node-c", shards["node-c"])
+	moved := 0
+	for key, owner := range owners {
+		if now := federation.NodeOf("cart", key); now != owner {
+			if now != "node-c" {
+				t.Fatal(key, owner, now)
+			}
+			moved++
+		}
+	}
+	if moved == 0 || moved > 200 {
+		t.Fatal(moved)
+	}
+
+	// owners depend on the names of the nodes, not on the order they joined in
+	reordered := NewConsistentFederation(64)
+	reordered.KeyBy("cart", func(args []interface{}) string { return args[0].(string) })
+	for _, node := range []string{"node-c", "node-a", "node-b"} {
+		reordered.Join(node, shards[node])
+	}
+	for key := range owners {
+		if reordered.NodeOf("cart", key) != federation.NodeOf("cart", key) {
+			t.Fatal(key)
+		}
+	}
+
+	// a leaving node gives back only its keys
+	if !federation.Leave("node-c") || federation.Leave("node-c") {
+		t.Fatal(federation.Nodes())
+	}
+	for key, owner := range owners {
+		if federation.NodeOf("cart", key) != owner {
+			t.Fatal(key, owner)
+		}
+	}
+	received := 0
+	shards[owners["cart-7"]].Subscribe("cart", func(string) { received++ })
+	federation.Publish("cart", "cart-7")
+	if received != 1 {
+		t.Fatal(received)
+	}
+	if NewConsistentFederation(8).NodeOf("cart", "cart-7") != "" {
+		t.Fatal("owner without nodes")
+	}
+}
@@ -0,0 +1,109 @@
+package EventBus
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// FileCreated - a watched path appeared
+	FileCreated = "create"
+	// FileWritten - the modification time or size of a watched path changed
+	FileWritten = "write"
+	// FileRemoved - a watched path disappeared
+	FileRemoved = "remove"
+)
+
+// FileWatcher - producer polling a set of paths and publishing debounced (path, op) events
+// to a topic when they are created, written or removed. It only needs the standard library;
+// events of a notification based watcher can be published the same way through a Debouncer.
+type FileWatcher struct {
+	debouncer *Debouncer
+	paths     []string
+	interval  time.Duration
+	lock      sync.Mutex
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// fileState - what FileWatcher knows about a path, the zero value stands for a missing path
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// NewFileWatcher - create a watcher polling paths every interval, events of a path are published
+// once it has been unchanged for the debounce duration
+func NewFileWatcher(bus Bus, topic string, interval, debounce time.Duration, paths ...string) *FileWatcher {
+	return &FileWatcher{
+		debouncer: NewDebouncer(bus, topic, debounce),
+		paths:     paths,
+		interval:  interval,
+	}
+}
+
+// Start - starts polling in the background, the current state of the paths is the baseline
+func (watcher *FileWatcher) Start() error {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	if watcher.stop != nil {
+		return fmt.Errorf("FileWatcher %w", ErrAlreadyStarted)
+	}
+	watcher.stop = make(chan struct{})
+	watcher.done = make(chan struct{})
+	states := make(map[string]fileState)
+	for _, path := range watcher.paths {
+		states[path] = statFile(path)
+	}
+	go watcher.poll(states, watcher.stop, watcher.done)
+	return nil
+}
+
+// Stop - stops polling and drops events still being debounced
+func (watcher *FileWatcher) Stop() {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	if watcher.stop == nil {
+		return
+	}
+	close(watcher.stop)
+	<-watcher.done
+	watcher.stop = nil
+	watcher.debouncer.Stop()
+}
+
+func (watcher *FileWatcher) poll(states map[string]fileState, stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, path := range watcher.paths {
+			previous, current := states[path], statFile(path)
+			states[path] = current
+			switch {
+			case !previous.exists && current.exists:
+				watcher.debouncer.Trigger(path, path, FileCreated)
+			case previous.exists && !current.exists:
+				watcher.debouncer.Trigger(path, path, FileRemoved)
+			case previous != current:
+				watcher.debouncer.Trigger(path, path, FileWritten)
+			}
+		}
+	}
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{true, info.ModTime(), info.Size()}
+}
@@ -0,0 +1,94 @@
+package EventBus
+
+import (
+	"sync"
+	"time"
+)
+
+// FlagProvider - source of the feature flags gating subscriptions made WithEnabledWhen.
+// It is queried while publishing, so it must not use the bus itself.
+type FlagProvider interface {
+	Enabled(flag string) bool
+}
+
+// FlagProviderFunc - function adapter for FlagProvider
+type FlagProviderFunc func(flag string) bool
+
+// Enabled calls fn(flag).
+func (fn FlagProviderFunc) Enabled(flag string) bool {
+	return fn(flag)
+}
+
+// flagCache - flag values of the provider, kept for ttl, and deliveries skipped per flag
+type flagCache struct {
+	provider FlagProvider
+	ttl      time.Duration
+	lock     sync.Mutex
+	values   map[string]cachedFlag
+	skips    map[string]uint64
+}
+
+type cachedFlag struct {
+	enabled bool
+	expires time.Time
+}
+
+// enabled reports whether a handler gated by flag receives the event, counting the skips.
+// Without a provider every flag is disabled.
+func (cache *flagCache) enabled(flag string) bool {
+	if flag == "" {
+		return true
+	}
+	if cache == nil {
+		return false
+	}
+	now := time.Now()
+	cache.lock.Lock()
+	value, ok := cache.values[flag]
+	cache.lock.Unlock()
+	if !ok || now.After(value.expires) {
+		value = cachedFlag{cache.provider.Enabled(flag), now.Add(cache.ttl)}
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.values[flag] = value
+	if !value.enabled {
+		cache.skips[flag]++
+	}
+	return value.enabled
+}
+
+// WithFlagProvider gates the subscriptions made WithEnabledWhen with the flags of provider,
+// whose values are cached for ttl (zero asks the provider on every delivery).
+func WithFlagProvider(provider FlagProvider, ttl time.Duration) Option {
+	return func(bus *EventBus) {
+		bus.flags = &flagCache{
+			provider: provider,
+			ttl:      ttl,
+			values:   make(map[string]cachedFlag),
+			skips:    make(map[string]uint64),
+		}
+	}
+}
+
+// WithEnabledWhen delivers to the handler only while flag is enabled in the bus FlagProvider,
+// see WithFlagProvider. Skipped once handlers stay subscribed.
+func WithEnabledWhen(flag string) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.flag = flag
+	}
+}
+
+// FlagSkips returns how many deliveries were skipped because of each disabled flag.
+func (bus *EventBus) FlagSkips() map[string]uint64 {
+	skips := make(map[string]uint64)
+	if bus.flags == nil {
+		return skips
+	}
+	bus.flags.lock.Lock()
+	defer bus.flags.lock.Unlock()
+	for flag, count := range bus.flags.skips {
+		skips[flag] = count
+	}
+	return skips
+}
@@ -0,0 +1,47 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnabledWhen(t *testing.T) {
+	flags := map[string]bool{"new-consumer": false}
+	queries := 0
+	bus := NewWithOptions(WithFlagProvider(FlagProviderFunc(func(flag string) bool {
+		queries++
+		return flags[flag]
+	}), time.Hour)).(*EventBus)
+	legacy, next := 0, 0
+	bus.Subscribe("topic", func() { legacy++ })
+	bus.SubscribeWith("topic", func() { next++ }, WithEnabledWhen("new-consumer"), WithOnce())
+	bus.Publish("topic")
+	flags["new-consumer"] = true
+	bus.Publish("topic")
+	if legacy != 2 || next != 0 || queries != 1 || !bus.HasCallback("topic") {
+		t.Fatal(legacy, next, queries)
+	}
+	if skips := bus.FlagSkips(); skips["new-consumer"] != 2 {
+		t.Fatal(skips)
+	}
+}
+
+func TestEnabledWhenUncached(t *testing.T) {
+	enabled := false
+	bus := NewWithOptions(WithFlagProvider(FlagProviderFunc(func(flag string) bool { return enabled }), 0)).(*EventBus)
+	count := 0
+	bus.SubscribeWith("topic", func() { count++ }, WithEnabledWhen("flag"))
+	bus.Publish("topic")
+	enabled = true
+	bus.Publish("topic")
+	if count != 1 {
+		t.Fail()
+	}
+
+	plain := New().(*EventBus)
+	plain.SubscribeWith("topic", func() { t.Fail() }, WithEnabledWhen("flag"))
+	plain.Publish("topic")
+	if len(plain.FlagSkips()) != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,132 @@
+package EventBus
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	// FSMEnteredPrefix - prefix of the topic published when a state machine enters a state,
+	// the event carries the previous state and the topic that caused the transition
+	FSMEnteredPrefix = "fsm:entered:"
+)
+
+// FSM - state machine whose transitions are driven by events published on a bus
+type FSM struct {
+	bus         *EventBus
+	lock        sync.Mutex
+	state       string
+	transitions map[string]map[string]string // topic -> from state -> to state
+	handlers    map[string]*eventHandler
+	queueLock   sync.Mutex
+	queue       []string // topics of the events waiting to be handled, in publish order
+	draining    bool
+}
+
+// NewFSM - create a state machine in the initial state, declare transitions before starting it
+func NewFSM(bus *EventBus, initial string) *FSM {
+	return &FSM{
+		bus:         bus,
+		state:       initial,
+		transitions: make(map[string]map[string]string),
+	}
+}
+
+// Transition declares that an event on topic moves the machine from state `from` to state `to`.
+// Events on the topic received in another state are ignored.
+func (fsm *FSM) Transition(topic, from, to string) *FSM {
+	fsm.lock.Lock()
+	defer fsm.lock.Unlock()
+	if _, ok := fsm.transitions[topic]; !ok {
+		fsm.transitions[topic] = make(map[string]string)
+	}
+	fsm.transitions[topic][from] = to
+	return fsm
+}
+
+// State returns the current state.
+func (fsm *FSM) State() string {
+	fsm.lock.Lock()
+	defer fsm.lock.Unlock()
+	return fsm.state
+}
+
+// Start subscribes the machine to the topics of its transitions.
+// Events of every topic are queued in publish order and handled serially in the background,
+// use WaitAsync to wait for pending transitions. When a subscription fails, those made already
+// are removed and the machine may be started again.
+func (fsm *FSM) Start() error {
+	fsm.lock.Lock()
+	defer fsm.lock.Unlock()
+	if fsm.handlers != nil {
+		return fmt.Errorf("FSM %w", ErrAlreadyStarted)
+	}
+	fsm.handlers = make(map[string]*eventHandler)
+	for topic := range fsm.transitions {
+		topic := topic
+		handler, err := fsm.bus.subscribeHandler(topic, func(args ...interface{}) { fsm.enqueue(topic) }, false, false, false)
+		if err != nil {
+			fsm.unsubscribe()
+			return err
+		}
+		fsm.handlers[topic] = handler
+	}
+	return nil
+}
+
+// Stop unsubscribes the machine, its state is kept.
+func (fsm *FSM) Stop() {
+	fsm.lock.Lock()
+	defer fsm.lock.Unlock()
+	fsm.unsubscribe()
+}
+
+func (fsm *FSM) unsubscribe() {
+	for topic, handler := range fsm.handlers {
+		fsm.bus.removeHandlerPtr(topic, handler)
+	}
+	fsm.handlers = nil
+}
+
+// enqueue runs synchronously in Publish, so the queue keeps the publish order across topics
+func (fsm *FSM) enqueue(topic string) {
+	fsm.bus.wg.Add(1)
+	fsm.queueLock.Lock()
+	defer fsm.queueLock.Unlock()
+	fsm.queue = append(fsm.queue, topic)
+	if !fsm.draining {
+		fsm.draining = true
+		go fsm.drain()
+	}
+}
+
+// drain handles the queued events one at a time, off the publishing goroutine so that
+// entering a state can be published
+func (fsm *FSM) drain() {
+	for {
+		fsm.queueLock.Lock()
+		if len(fsm.queue) == 0 {
+			fsm.draining = false
+			fsm.queueLock.Unlock()
+			return
+		}
+		topic := fsm.queue[0]
+		fsm.queue = fsm.queue[1:]
+		fsm.queueLock.Unlock()
+		fsm.fire(topic)
+		fsm.bus.wg.Done()
+	}
+}
+
+func (fsm *FSM) fire(topic string) {
+	fsm.lock.Lock()
+	from := fsm.state
+	to, ok := fsm.transitions[topic][from]
+	if ok {
+		fsm.state = to
+	}
+	fsm.lock.Unlock()
+	if ok {
+		fsm.bus.Publish(FSMEnteredPrefix+to, from, topic)
+	}
+}
@@ -0,0 +1,73 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestFSM(t *testing.T) {
+	bus := New().(*EventBus)
+	fsm := NewFSM(bus, "idle").
+		Transition("job:start", "idle", "running").
+		Transition("job:done", "running", "idle")
+	if err := fsm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if fsm.Start() == nil {
+		t.Fail()
+	}
+
+	entered := make(chan string, 2)
+	bus.Subscribe(FSMEnteredPrefix+"running", func(from, topic string) {
+		entered <- from + " " + topic
+	})
+
+	bus.Publish("job:done") // ignored in idle
+	bus.WaitAsync()
+	if fsm.State() != "idle" {
+		t.Fail()
+	}
+
+	bus.Publish("job:start")
+	bus.WaitAsync()
+	if fsm.State() != "running" || <-entered != "idle job:start" {
+		t.Fail()
+	}
+
+	fsm.Stop()
+	bus.Publish("job:done")
+	bus.WaitAsync()
+	if fsm.State() != "running" || bus.HasCallback("job:done") {
+		t.Fail()
+	}
+}
+
+func TestFSMOrdersTopics(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		bus := New().(*EventBus)
+		fsm := NewFSM(bus, "idle").
+			Transition("start", "idle", "running").
+			Transition("finish", "running", "done")
+		fsm.Start()
+		bus.Publish("start")
+		bus.Publish("finish")
+		bus.WaitAsync()
+		if fsm.State() != "done" {
+			t.Fatal(fsm.State())
+		}
+	}
+}
+
+func TestFSMStartFailure(t *testing.T) {
+	bus := New().(*EventBus)
+	fsm := NewFSM(bus, "idle").
+		Transition("job:start", "idle", "running").
+		Transition("job:done", "running", "idle")
+	bus.Close()
+	if err := fsm.Start(); err != ErrBusClosed {
+		t.Fatal(err)
+	}
+	// not left half started
+	if fsm.handlers != nil || bus.HasCallback("job:start") || bus.HasCallback("job:done") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,127 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// fuzzTopics - topics used by FuzzBus, every topic has its own handler signature
+var fuzzTopics = []string{"fuzz:none", "fuzz:int", "fuzz:string_int", "fuzz:error", "fuzz:pointer"}
+
+func fuzzHandler(topic int, async bool, calls *int) interface{} {
+	count := func() {
+		if !async {
+			*calls++
+		}
+	}
+	switch topic {
+	case 0:
+		return func() { count() }
+	case 1:
+		return func(a int) { count() }
+	case 2:
+		return func(s string, a int) { count() }
+	case 3:
+		return func(err error) { count() }
+	default:
+		return func(p *int, v interface{}) { count() }
+	}
+}
+
+func fuzzArgs(topic int, b byte) []interface{} {
+	switch topic {
+	case 0:
+		return nil
+	case 1:
+		return []interface{}{int(b)}
+	case 2:
+		return []interface{}{string([]byte{b}), int(b)}
+	case 3:
+		if b%2 == 0 {
+			return []interface{}{nil}
+		}
+		return []interface{}{errors.New("fuzz")}
+	default:
+		value := int(b)
+		if b%2 == 0 {
+			return []interface{}{nil, nil}
+		}
+		return []interface{}{&value, b}
+	}
+}
+
+// fuzzSubscription - handler FuzzBus expects on a topic, in subscription order
+type fuzzSubscription struct {
+	once  bool
+	async bool
+}
+
+// FuzzBus interprets data as a sequence of subscribe, unsubscribe and publish operations
+// against a fresh bus, with handler signatures and arguments picked from data.
+// It panics when the bus panics or misbehaves: when HasCallback, Unsubscribe or the number of
+// sync handlers a Publish calls disagree with the subscriptions made so far. It follows the
+// go-fuzz convention of returning 1 for inputs which exercised publishing, 0 otherwise.
+func FuzzBus(data []byte) int {
+	bus := New()
+	calls := 0
+	subscriptions := make([][]fuzzSubscription, len(fuzzTopics))
+	published := 0
+	for i := 0; i+2 < len(data); i += 3 {
+		op, topicIdx, arg := data[i]%7, int(data[i+1])%len(fuzzTopics), data[i+2]
+		topic := fuzzTopics[topicIdx]
+		subscribed := subscriptions[topicIdx]
+		var err error
+		switch op {
+		case 0:
+			err = bus.Subscribe(topic, fuzzHandler(topicIdx, false, &calls))
+			subscribed = append(subscribed, fuzzSubscription{})
+		case 1:
+			err = bus.SubscribeOnce(topic, fuzzHandler(topicIdx, false, &calls))
+			subscribed = append(subscribed, fuzzSubscription{once: true})
+		case 2:
+			err = bus.SubscribeAsync(topic, fuzzHandler(topicIdx, true, &calls), arg%2 == 0)
+			subscribed = append(subscribed, fuzzSubscription{async: true})
+		case 3:
+			err = bus.SubscribeOnceAsync(topic, fuzzHandler(topicIdx, true, &calls))
+			subscribed = append(subscribed, fuzzSubscription{once: true, async: true})
+		case 4, 5:
+			// the handlers of a topic share their code, Unsubscribe removes the first one
+			removed := bus.Unsubscribe(topic, fuzzHandler(topicIdx, op == 5, &calls)) == nil
+			if removed != (len(subscribed) > 0) {
+				panic(fmt.Sprintf("unsubscribe %s: removed %v with %d handlers", topic, removed, len(subscribed)))
+			}
+			if removed {
+				subscribed = append(subscribed[:0:0], subscribed[1:]...)
+			}
+		case 6:
+			want, before := 0, calls
+			var kept []fuzzSubscription
+			for _, subscription := range subscribed {
+				if !subscription.async {
+					want++
+				}
+				if !subscription.once {
+					kept = append(kept, subscription)
+				}
+			}
+			bus.Publish(topic, fuzzArgs(topicIdx, arg)...)
+			if calls-before != want {
+				panic(fmt.Sprintf("publish %s: %d sync handlers called, %d subscribed", topic, calls-before, want))
+			}
+			subscribed = kept
+			published++
+		}
+		if err != nil {
+			panic(fmt.Sprintf("subscribe %s: %v", topic, err))
+		}
+		subscriptions[topicIdx] = subscribed
+		if bus.HasCallback(topic) != (len(subscribed) > 0) {
+			panic(fmt.Sprintf("HasCallback(%s) with %d handlers", topic, len(subscribed)))
+		}
+	}
+	bus.WaitAsync()
+	if published == 0 {
+		return 0
+	}
+	return 1
+}
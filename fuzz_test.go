@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package EventBus
+
+import (
+	"testing"
+)
+
+func FuzzPublishSubscribe(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 6, 1, 7})
+	f.Add([]byte{1, 0, 0, 1, 0, 0, 6, 0, 0, 6, 0, 0})
+	f.Add([]byte{2, 2, 0, 3, 2, 1, 6, 2, 9, 4, 2, 0, 6, 2, 9})
+	f.Add([]byte{0, 3, 0, 6, 3, 0, 6, 3, 1, 0, 4, 0, 6, 4, 0, 6, 4, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		FuzzBus(data)
+	})
+}
@@ -0,0 +1,95 @@
+package EventBus
+
+import (
+	"time"
+)
+
+// GCStats - bookkeeping reclaimed by GC
+type GCStats struct {
+	Topics int // topics without handlers whose entries were dropped
+	Keys   int // PublishOnce keys which left their DedupWindow
+}
+
+// GC drops what the bus keeps for topics without handlers: the empty entries of the handler
+// map, their statistics and Barrier bookkeeping, and their PublishOnce bookkeeping once no key is
+// left. Keys which left their DedupWindow are evicted on every topic. The handler entry of a topic
+// is dropped by its last Unsubscribe already, GC reclaims the rest for processes creating
+// topics per entity; state topics are kept. Topics created after GC number their events above
+// the highest sequence number it reclaimed, so no topic numbers two events alike.
+func (bus *EventBus) GC() GCStats {
+	var stats GCStats
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	topics := make(map[string]bool)
+	for topic := range bus.handlers {
+		topics[topic] = true
+	}
+	bus.stats.lock.Lock()
+	for topic := range bus.stats.topics {
+		topics[topic] = true
+	}
+	bus.stats.lock.Unlock()
+	bus.progress.Range(func(key, value interface{}) bool {
+		topics[key.(string)] = true
+		return true
+	})
+	now := time.Now()
+	bus.onceLock.Lock()
+	for topic, set := range bus.onceKeys {
+		kept := len(set.keys)
+		set.evict(now)
+		stats.Keys += kept - len(set.keys)
+		topics[topic] = true
+	}
+	bus.onceLock.Unlock()
+
+	for topic := range topics {
+		if len(bus.handlers[topic]) == 0 && bus.reclaim(topic) {
+			stats.Topics++
+		}
+	}
+	return stats
+}
+
+// reclaim drops the statistics, Barrier and PublishOnce bookkeeping of a topic without
+// handlers, reporting whether anything was dropped. The bus lock must be held.
+func (bus *EventBus) reclaim(topic string) bool {
+	reclaimed := false
+	if _, ok := bus.handlers[topic]; ok {
+		bus.setHandlers(topic, nil)
+		reclaimed = true
+	}
+
+	bus.stats.lock.Lock()
+	if _, ok := bus.stats.topics[topic]; ok {
+		delete(bus.stats.topics, topic)
+		reclaimed = true
+	}
+	bus.stats.lock.Unlock()
+
+	bus.seqLock.Lock()
+	if value, ok := bus.progress.Load(topic); ok {
+		progress := value.(*topicProgress)
+		progress.lock.Lock()
+		if len(progress.pending) == 0 && len(progress.waiters) == 0 {
+			// topics created from now on number their events above it, so a topic published
+			// again never reuses a number: servers retain and clients resync events by them
+			if progress.seq > bus.seqFloor {
+				bus.seqFloor = progress.seq
+			}
+			progress.reclaimed = true
+			bus.progress.Delete(topic)
+			reclaimed = true
+		}
+		progress.lock.Unlock()
+	}
+	bus.seqLock.Unlock()
+
+	bus.onceLock.Lock()
+	if set, ok := bus.onceKeys[topic]; ok && len(set.keys) == 0 && set.window == (DedupWindow{}) {
+		delete(bus.onceKeys, topic)
+		reclaimed = true
+	}
+	bus.onceLock.Unlock()
+	return reclaimed
+}
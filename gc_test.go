@@ -0,0 +1,72 @@
+package EventBus
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUnsubscribeDropsTopic(t *testing.T) {
+	bus := New().(*EventBus)
+	handler := func() {}
+	bus.Subscribe("order:1", handler)
+	bus.Unsubscribe("order:1", handler)
+	if _, ok := bus.handlers["order:1"]; ok {
+		t.Fatal("empty topic entry kept")
+	}
+	bus.SubscribeWith("order:2", handler, WithTags("orders"))
+	bus.UnsubscribeTag("orders")
+	if len(bus.handlers) != 0 {
+		t.Fatal(bus.handlers)
+	}
+}
+
+func TestGC(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.Subscribe("kept", func() {})
+	for i := 0; i < 10; i++ {
+		bus.Publish("order:" + strconv.Itoa(i))
+	}
+	bus.Publish("kept")
+	bus.PublishOnce("init", "a")
+	bus.SetDedupWindow("session", DedupWindow{TTL: time.Millisecond})
+	bus.PublishOnce("session", "a")
+	bus.PublishOnce("session", "b")
+	time.Sleep(5 * time.Millisecond)
+
+	// the order topics, session and the counters of init, which still remembers its key
+	if stats := bus.GC(); stats.Topics != 12 || stats.Keys != 2 {
+		t.Fatal(stats)
+	}
+	if bus.TopicStats("order:1").Published != 0 || bus.Sequence("order:1") != 0 {
+		t.Fatal("order:1 kept")
+	}
+	if bus.TopicStats("kept").Published != 1 || bus.Sequence("kept") != 1 || bus.PublishOnce("init", "a") {
+		t.Fatal("topic with handlers dropped")
+	}
+	if stats := bus.GC(); stats != (GCStats{}) {
+		t.Fatal(stats)
+	}
+
+	// a reclaimed topic numbers its events above every reclaimed one, session reached 2
+	var seq uint64
+	bus.Subscribe("order:1", func(meta EventMeta) { seq = meta.Seq })
+	bus.Publish("order:1")
+	if seq != 3 || bus.Sequence("order:1") != 3 {
+		t.Fatal(seq)
+	}
+
+	// nothing is kept per reclaimed topic
+	for i := 0; i < 1000; i++ {
+		bus.Publish("entity:" + strconv.Itoa(i))
+	}
+	bus.GC()
+	n := 0
+	bus.progress.Range(func(key, value interface{}) bool {
+		n++
+		return true
+	})
+	if n != 2 {
+		t.Fatal(n)
+	}
+}
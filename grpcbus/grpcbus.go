@@ -0,0 +1,90 @@
+package grpcbus
+
+import (
+	"context"
+
+	"github.com/asaskevich/EventBus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey - metadata key carrying the correlation ID of a call, lower case as gRPC metadata keys are
+const requestIDKey = "x-request-id"
+
+// Option - setting of the interceptors
+type Option func(observer *EventBus.CallObserver)
+
+// WithTopics publishes the start and end of calls to the given topics instead of
+// EventBus.RPCCallStartTopic and EventBus.RPCCallFinishTopic
+func WithTopics(start, finish string) Option {
+	return func(observer *EventBus.CallObserver) {
+		observer.StartTopic, observer.FinishTopic = start, finish
+	}
+}
+
+func newObserver(bus *EventBus.EventBus, opts []Option) EventBus.CallObserver {
+	observer := EventBus.CallObserver{Bus: bus}
+	for _, opt := range opts {
+		opt(&observer)
+	}
+	return observer
+}
+
+// headers returns the correlation ID of a call, from the x-request-id metadata
+func headers(md metadata.MD) EventBus.Headers {
+	if ids := md.Get(requestIDKey); len(ids) > 0 && ids[0] != "" {
+		return EventBus.Headers{EventBus.CorrelationIDHeader: ids[0]}
+	}
+	return nil
+}
+
+func incoming(ctx context.Context) EventBus.Headers {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return headers(md)
+}
+
+func outgoing(ctx context.Context) EventBus.Headers {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return headers(md)
+}
+
+// end publishes the end of a call with the status code of err
+func end(finish func(code int, status string, err error), err error) {
+	code := status.Code(err)
+	finish(int(code), code.String(), err)
+}
+
+// UnaryServerInterceptor publishes the start and end of every unary call served
+func UnaryServerInterceptor(bus *EventBus.EventBus, opts ...Option) grpc.UnaryServerInterceptor {
+	observer := newObserver(bus, opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		finish := observer.Begin(info.FullMethod, false, incoming(ctx))
+		resp, err := handler(ctx, req)
+		end(finish, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor publishes the start and end of every streaming call served
+func StreamServerInterceptor(bus *EventBus.EventBus, opts ...Option) grpc.StreamServerInterceptor {
+	observer := newObserver(bus, opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		finish := observer.Begin(info.FullMethod, true, incoming(ss.Context()))
+		err := handler(srv, ss)
+		end(finish, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor publishes the start and end of every unary call made. Streaming calls
+// made by clients end when the application stops reading, they are not observed.
+func UnaryClientInterceptor(bus *EventBus.EventBus, opts ...Option) grpc.UnaryClientInterceptor {
+	observer := newObserver(bus, opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		finish := observer.Begin(method, false, outgoing(ctx))
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		end(finish, err)
+		return err
+	}
+}
@@ -0,0 +1,31 @@
+package grpcbus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asaskevich/EventBus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	bus := EventBus.New().(*EventBus.EventBus)
+	var calls []EventBus.RPCCall
+	var ids []string
+	bus.Subscribe("grpc:end", func(meta EventBus.EventMeta, call EventBus.RPCCall) {
+		calls = append(calls, call)
+		ids = append(ids, meta.Headers[EventBus.CorrelationIDHeader])
+	})
+	interceptor := UnaryServerInterceptor(bus, WithTopics("grpc:start", "grpc:end"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Request-ID", "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}
+	interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "no such order")
+	})
+	if len(calls) != 1 || calls[0].Method != info.FullMethod || calls[0].Code != int(codes.NotFound) || calls[0].Status != "NotFound" || ids[0] != "req-1" {
+		t.Fatal(calls, ids)
+	}
+}
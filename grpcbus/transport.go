@@ -0,0 +1,300 @@
+// Package grpcbus provides a gRPC transport for EventBus: NewGRPCServer serves a bus and
+// NewGRPCClient publishes to it and subscribes to its topics over server streams. Its
+// interceptors publish the lifecycle of calls to a bus, see EventBus.CallObserver for the events.
+package grpcbus
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative eventbus.proto
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/gob"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/asaskevich/EventBus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	publishMethod   = "/eventbus.v1.EventBus/Publish"
+	subscribeMethod = "/eventbus.v1.EventBus/Subscribe"
+)
+
+// StreamEndTopic - topic a StreamEnd is published to on the local bus when a subscription of a
+// GRPCClient fails
+const StreamEndTopic = "grpc:stream:end"
+
+// StreamEnd - a subscription of a GRPCClient stopped receiving events
+type StreamEnd struct {
+	Target string
+	Topics []string
+	Err    error
+}
+
+// ServerTLS serves over TLS with config, an option of NewGRPCServer
+func ServerTLS(config *tls.Config) grpc.ServerOption {
+	return grpc.Creds(credentials.NewTLS(config))
+}
+
+// ClientTLS dials over TLS with config, an option of NewGRPCClient
+func ClientTLS(config *tls.Config) grpc.DialOption {
+	return grpc.WithTransportCredentials(credentials.NewTLS(config))
+}
+
+// encodeArgs - gob encoding of the arguments of an event, so non-basic argument types must be
+// registered with gob.Register as with the rpc transport
+func encodeArgs(args []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(args); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeArgs(data []byte) ([]interface{}, error) {
+	var args []interface{}
+	if len(data) == 0 {
+		return args, nil
+	}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&args)
+	return args, err
+}
+
+// eventBusServer - implementation of the EventBus service of eventbus.proto
+type eventBusServer interface {
+	publish(ctx context.Context, event *Event) (*PublishReply, error)
+	subscribe(req *SubscribeRequest, stream grpc.ServerStream) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "eventbus.v1.EventBus",
+	HandlerType: (*eventBusServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Publish",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			event := new(Event)
+			if err := dec(event); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return srv.(eventBusServer).publish(ctx, event)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: publishMethod}
+			return interceptor(ctx, event, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.(eventBusServer).publish(ctx, req.(*Event))
+			})
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName: "Subscribe",
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			req := new(SubscribeRequest)
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			return srv.(eventBusServer).subscribe(req, stream)
+		},
+		ServerStreams: true,
+	}},
+	Metadata: "eventbus.proto",
+}
+
+// GRPCServer - serves a bus over gRPC: clients publish events on it and stream the events of
+// the topics they subscribe to
+type GRPCServer struct {
+	bus    *EventBus.EventBus
+	server *grpc.Server
+}
+
+// busService - the EventBus service over a bus
+type busService struct {
+	bus *EventBus.EventBus
+}
+
+// NewGRPCServer returns a server of the bus, opts configure the gRPC server, e.g. ServerTLS
+// or the interceptors of this package
+func NewGRPCServer(bus *EventBus.EventBus, opts ...grpc.ServerOption) *GRPCServer {
+	server := &GRPCServer{bus: bus, server: grpc.NewServer(opts...)}
+	Register(server.server, bus)
+	return server
+}
+
+// Register serves the bus on a gRPC server of the application, next to its own services
+func Register(registrar grpc.ServiceRegistrar, bus *EventBus.EventBus) {
+	registrar.RegisterService(&serviceDesc, &busService{bus: bus})
+}
+
+// Serve accepts connections on lis until Stop, see grpc.Server.Serve
+func (server *GRPCServer) Serve(lis net.Listener) error {
+	return server.server.Serve(lis)
+}
+
+// Stop closes the connections, ending the subscriptions of the clients
+func (server *GRPCServer) Stop() {
+	server.server.Stop()
+}
+
+func (service *busService) publish(ctx context.Context, event *Event) (*PublishReply, error) {
+	if event.Topic == "" {
+		return nil, status.Error(codes.InvalidArgument, "event without topic")
+	}
+	args, err := decodeArgs(event.Args)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decoding arguments: %v", err)
+	}
+	service.bus.PublishWithHeaders(event.Topic, event.Headers, args...)
+	return &PublishReply{}, nil
+}
+
+// delivery - an event of a subscribed topic, encoded by the goroutine sending it
+type delivery struct {
+	meta EventBus.EventMeta
+	args []interface{}
+}
+
+// subscribe forwards the events of the topics to the stream. The events of a topic are
+// forwarded in order by an async transactional handler, so handlers may publish to them.
+func (service *busService) subscribe(req *SubscribeRequest, stream grpc.ServerStream) error {
+	if len(req.Topics) == 0 {
+		return status.Error(codes.InvalidArgument, "subscription without topics")
+	}
+	ctx := stream.Context()
+	events := make(chan delivery, 64)
+	forward := func(meta EventBus.EventMeta, args ...interface{}) {
+		select {
+		case events <- delivery{meta: meta, args: args}:
+		case <-ctx.Done():
+		}
+	}
+	subscribed := make([]string, 0, len(req.Topics))
+	defer func() {
+		for _, topic := range subscribed {
+			service.bus.UnsubscribeKey(topic, events)
+		}
+	}()
+	for _, topic := range req.Topics {
+		// the channel is the key of the handlers, every stream having one of its own
+		if err := service.bus.SubscribeWith(topic, forward, EventBus.WithAsync(true), EventBus.WithKey(events)); err != nil {
+			if errors.Is(err, EventBus.ErrBusClosed) {
+				return status.Error(codes.Unavailable, err.Error())
+			}
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		subscribed = append(subscribed, topic)
+	}
+	// the headers tell the client the subscription is in place
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d := <-events:
+			data, err := encodeArgs(d.args)
+			if err != nil {
+				return status.Errorf(codes.Internal, "encoding arguments of %s: %v", d.meta.Topic, err)
+			}
+			if err := stream.SendMsg(&Event{Topic: d.meta.Topic, Headers: d.meta.Headers, Args: data}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GRPCClient - connection to a bus served by a GRPCServer, publishing on it and streaming the
+// events of its topics into a local bus
+type GRPCClient struct {
+	target string
+	bus    *EventBus.EventBus
+	conn   *grpc.ClientConn
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewGRPCClient connects to the server at target, streaming the remote events into bus.
+// opts configure the connection and must give its credentials, ClientTLS or
+// grpc.WithTransportCredentials(insecure.NewCredentials()) for plain text.
+func NewGRPCClient(target string, bus *EventBus.EventBus, opts ...grpc.DialOption) (*GRPCClient, error) {
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &GRPCClient{target: target, bus: bus, conn: conn, ctx: ctx, cancel: cancel}, nil
+}
+
+// Publish publishes args to topic on the remote bus
+func (client *GRPCClient) Publish(ctx context.Context, topic string, args ...interface{}) error {
+	return client.PublishWithHeaders(ctx, topic, nil, args...)
+}
+
+// PublishWithHeaders publishes args to topic on the remote bus along with headers
+func (client *GRPCClient) PublishWithHeaders(ctx context.Context, topic string, headers EventBus.Headers, args ...interface{}) error {
+	data, err := encodeArgs(args)
+	if err != nil {
+		return err
+	}
+	return client.conn.Invoke(ctx, publishMethod, &Event{Topic: topic, Headers: headers, Args: data}, new(PublishReply))
+}
+
+// Subscribe streams the events published to the topics of the remote bus into the local bus,
+// each on its topic with its headers, until stop or Close. The subscription is in place on the
+// server once Subscribe returns. A failing stream publishes a StreamEnd to StreamEndTopic.
+func (client *GRPCClient) Subscribe(topics ...string) (stop func(), err error) {
+	ctx, cancel := context.WithCancel(client.ctx)
+	stream, err := client.conn.NewStream(ctx, &serviceDesc.Streams[0], subscribeMethod)
+	if err == nil {
+		err = stream.SendMsg(&SubscribeRequest{Topics: topics})
+	}
+	if err == nil {
+		err = stream.CloseSend()
+	}
+	if err == nil {
+		_, err = stream.Header()
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	client.wg.Add(1)
+	go func() {
+		defer client.wg.Done()
+		err := client.receive(stream)
+		if ctx.Err() == nil {
+			client.bus.Publish(StreamEndTopic, StreamEnd{Target: client.target, Topics: topics, Err: err})
+		}
+		cancel()
+	}()
+	return cancel, nil
+}
+
+// receive publishes the events of the stream until it ends
+func (client *GRPCClient) receive(stream grpc.ClientStream) error {
+	for {
+		event := new(Event)
+		if err := stream.RecvMsg(event); err != nil {
+			return err
+		}
+		args, err := decodeArgs(event.Args)
+		if err != nil {
+			return err
+		}
+		client.bus.PublishWithHeaders(event.Topic, event.Headers, args...)
+	}
+}
+
+// Close ends the subscriptions and closes the connection
+func (client *GRPCClient) Close() error {
+	client.cancel()
+	client.wg.Wait()
+	return client.conn.Close()
+}
@@ -0,0 +1,133 @@
+package grpcbus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/asaskevich/EventBus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func serve(t *testing.T, bus *EventBus.EventBus, opts ...grpc.ServerOption) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewGRPCServer(bus, opts...)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestGRPCTransport(t *testing.T) {
+	remote := EventBus.New().(*EventBus.EventBus)
+	local := EventBus.New().(*EventBus.EventBus)
+	client, err := NewGRPCClient(serve(t, remote), local, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	received := make(chan EventBus.EventMeta, 1)
+	local.Subscribe("order:placed", func(meta EventBus.EventMeta, id string, amount int) {
+		if id == "o-1" && amount == 42 {
+			received <- meta
+		}
+	})
+	if _, err := client.Subscribe("order:placed"); err != nil {
+		t.Fatal(err)
+	}
+	remote.PublishWithHeaders("order:placed", EventBus.Headers{EventBus.CorrelationIDHeader: "c-1"}, "o-1", 42)
+	select {
+	case meta := <-received:
+		if meta.Headers[EventBus.CorrelationIDHeader] != "c-1" {
+			t.Fatal(meta.Headers)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not streamed")
+	}
+
+	published := make(chan string, 1)
+	remote.Subscribe("order:shipped", func(id string) { published <- id })
+	if err := client.Publish(context.Background(), "order:shipped", "o-1"); err != nil {
+		t.Fatal(err)
+	}
+	if id := <-published; id != "o-1" {
+		t.Fatal(id)
+	}
+}
+
+func TestGRPCSubscribeStop(t *testing.T) {
+	remote := EventBus.New().(*EventBus.EventBus)
+	client, err := NewGRPCClient(serve(t, remote), EventBus.New().(*EventBus.EventBus), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	stop, err := client.Subscribe("a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !remote.HasCallback("a") || !remote.HasCallback("b") {
+		t.Fatal("subscription not in place")
+	}
+	stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for remote.HasCallback("a") || remote.HasCallback("b") {
+		if time.Now().After(deadline) {
+			t.Fatal("handlers left after stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGRPCTransportTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	remote := EventBus.New().(*EventBus.EventBus)
+	addr := serve(t, remote, ServerTLS(&tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}))
+	client, err := NewGRPCClient(addr, EventBus.New().(*EventBus.EventBus), ClientTLS(&tls.Config{RootCAs: roots}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	published := make(chan string, 1)
+	remote.Subscribe("ping", func(s string) { published <- s })
+	if err := client.Publish(context.Background(), "ping", "pong"); err != nil {
+		t.Fatal(err)
+	}
+	if s := <-published; s != "pong" {
+		t.Fatal(s)
+	}
+}
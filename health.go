@@ -0,0 +1,90 @@
+package EventBus
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// HealthCheckTopic - topic published to ask components for a health report, the event carries
+	// the check time. Components publish their report from their handler, sync or async.
+	HealthCheckTopic = "health:check"
+	// HealthReportTopic - topic components publish their health to,
+	// the event carries the component name, whether it is healthy and a message
+	HealthReportTopic = "health:report"
+)
+
+// ComponentHealth - last health report of a component
+type ComponentHealth struct {
+	Healthy    bool      `json:"healthy"`
+	Message    string    `json:"message,omitempty"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+// HealthStatus - aggregated health of every component which reported so far
+type HealthStatus struct {
+	Healthy    bool                       `json:"healthy"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// Health - aggregator of the health reports published on a bus
+type Health struct {
+	bus     Bus
+	maxAge  time.Duration
+	lock    sync.Mutex
+	reports map[string]ComponentHealth
+}
+
+// NewHealth - create an aggregator subscribed to HealthReportTopic, reports older than maxAge
+// count as unhealthy (zero keeps reports valid forever)
+func NewHealth(bus Bus, maxAge time.Duration) (*Health, error) {
+	health := &Health{bus: bus, maxAge: maxAge, reports: make(map[string]ComponentHealth)}
+	if err := bus.Subscribe(HealthReportTopic, health.report); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+func (health *Health) report(component string, healthy bool, message string) {
+	health.lock.Lock()
+	defer health.lock.Unlock()
+	health.reports[component] = ComponentHealth{healthy, message, time.Now()}
+}
+
+// Check asks every component for a fresh report.
+func (health *Health) Check() {
+	health.bus.Publish(HealthCheckTopic, time.Now())
+}
+
+// Status aggregates the latest reports, the bus is healthy when every component is.
+func (health *Health) Status() HealthStatus {
+	health.lock.Lock()
+	defer health.lock.Unlock()
+	status := HealthStatus{Healthy: true, Components: make(map[string]ComponentHealth, len(health.reports))}
+	for component, report := range health.reports {
+		if health.maxAge > 0 && time.Since(report.ReportedAt) > health.maxAge {
+			report.Healthy = false
+			report.Message = "stale report: " + report.Message
+		}
+		status.Healthy = status.Healthy && report.Healthy
+		status.Components[component] = report
+	}
+	return status
+}
+
+// Handler returns an http.Handler running a check, waiting for reports and serving the
+// status as JSON, with status code 503 when unhealthy.
+func (health *Health) Handler(wait time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health.Check()
+		time.Sleep(wait)
+		status := health.Status()
+		w.Header().Set("Content-Type", "application/json")
+		if !status.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
@@ -0,0 +1,46 @@
+package EventBus
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	bus := New()
+	health, err := NewHealth(bus, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbHealthy := true
+	bus.SubscribeAsync(HealthCheckTopic, func(at time.Time) {
+		bus.Publish(HealthReportTopic, "db", dbHealthy, "")
+	}, false)
+	bus.Subscribe(HealthCheckTopic, func(at time.Time) {
+		bus.Publish(HealthReportTopic, "cache", true, "warm")
+	})
+
+	health.Check()
+	bus.WaitAsync()
+	status := health.Status()
+	if !status.Healthy || len(status.Components) != 2 || status.Components["cache"].Message != "warm" {
+		t.Fail()
+	}
+
+	dbHealthy = false
+	rec := httptest.NewRecorder()
+	health.Handler(10*time.Millisecond).ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if rec.Code != 503 || health.Status().Healthy {
+		t.Fail()
+	}
+}
+
+func TestHealthStaleReports(t *testing.T) {
+	bus := New()
+	health, _ := NewHealth(bus, time.Millisecond)
+	bus.Publish(HealthReportTopic, "db", true, "")
+	time.Sleep(5 * time.Millisecond)
+	if health.Status().Healthy {
+		t.Fail()
+	}
+}
@@ -0,0 +1,89 @@
+package EventBus
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// HTTPRequestStartTopic - topic HTTPMiddleware publishes an HTTPRequest to when a request arrives
+	HTTPRequestStartTopic = "http:request:start"
+	// HTTPRequestFinishTopic - topic HTTPMiddleware publishes an HTTPRequest to once the response is written
+	HTTPRequestFinishTopic = "http:request:finish"
+)
+
+// RequestIDHeader - HTTP request header whose value becomes the correlation ID of the lifecycle events
+const RequestIDHeader = "X-Request-ID"
+
+// HTTPRequest - request observed by HTTPMiddleware, Status, Bytes and Duration are set once it finished
+type HTTPRequest struct {
+	Method     string
+	Path       string
+	RemoteAddr string
+	Started    time.Time
+	Status     int
+	Bytes      int64 // response body bytes written
+	Duration   time.Duration
+}
+
+// statusWriter - http.ResponseWriter recording the status and size of the response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush lets streaming handlers flush through the middleware
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// HTTPMiddleware wraps next so every request publishes an HTTPRequest to HTTPRequestStartTopic
+// when it arrives and to HTTPRequestFinishTopic once next returned, letting metrics, audit or
+// alerting subscribers observe the traffic without depending on the HTTP stack. The X-Request-ID
+// header of the request, when present, is the correlation ID of both events. A panicking handler
+// finishes with status 500 before the panic goes on.
+func (bus *EventBus) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var headers Headers
+		if id := r.Header.Get(RequestIDHeader); id != "" {
+			headers = Headers{CorrelationIDHeader: id}
+		}
+		request := HTTPRequest{Method: r.Method, Path: r.URL.Path, RemoteAddr: r.RemoteAddr, Started: time.Now()}
+		bus.PublishWithHeaders(HTTPRequestStartTopic, headers, request)
+
+		sw := &statusWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			request.Status, request.Bytes, request.Duration = sw.status, sw.bytes, time.Since(request.Started)
+			if recovered != nil {
+				request.Status = http.StatusInternalServerError
+			} else if request.Status == 0 {
+				request.Status = http.StatusOK
+			}
+			bus.PublishWithHeaders(HTTPRequestFinishTopic, headers, request)
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
@@ -0,0 +1,48 @@
+package EventBus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	bus := New().(*EventBus)
+	var events []HTTPRequest
+	var correlation []string
+	record := func(meta EventMeta, request HTTPRequest) {
+		events = append(events, request)
+		correlation = append(correlation, meta.Headers[CorrelationIDHeader])
+	}
+	bus.Subscribe(HTTPRequestStartTopic, record)
+	bus.Subscribe(HTTPRequestFinishTopic, record)
+	handler := bus.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+
+	r := httptest.NewRequest("POST", "/orders", nil)
+	r.Header.Set(RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if len(events) != 2 || events[0].Path != "/orders" || events[0].Status != 0 || correlation[0] != "req-1" {
+		t.Fatal(events, correlation)
+	}
+	if finished := events[1]; finished.Method != "POST" || finished.Status != http.StatusCreated || finished.Bytes != 7 || correlation[1] != "req-1" {
+		t.Fatal(finished)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic swallowed")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	}()
+	if len(events) != 4 || events[3].Status != http.StatusInternalServerError {
+		t.Fatal(events)
+	}
+}
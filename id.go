@@ -0,0 +1,28 @@
+package EventBus
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator - source of unique identifiers for events and correlation, e.g. UUIDv7, ULID or snowflake
+type IDGenerator func() string
+
+var idSequence = rand.New(rand.NewSource(time.Now().UnixNano())).Uint32()
+
+// TimeOrderedID - default IDGenerator, identifiers are the creation time in nanoseconds followed
+// by a process wide sequence, both in fixed width hexadecimal, so they sort by creation time.
+func TimeOrderedID() string {
+	return fmt.Sprintf("%016x%08x", time.Now().UnixNano(), atomic.AddUint32(&idSequence, 1))
+}
+
+// NewID returns a new identifier from the bus IDGenerator, TimeOrderedID without one.
+// Use it for correlation IDs so they follow the same scheme as event IDs.
+func (bus *EventBus) NewID() string {
+	if bus.ids != nil {
+		return bus.ids()
+	}
+	return TimeOrderedID()
+}
@@ -0,0 +1,39 @@
+package EventBus
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestTimeOrderedID(t *testing.T) {
+	first, second := TimeOrderedID(), TimeOrderedID()
+	if len(first) != 24 || first == second {
+		t.Fatal(first, second)
+	}
+	if New().(*EventBus).NewID() == "" {
+		t.Fail()
+	}
+}
+
+func TestIDGenerator(t *testing.T) {
+	next := 0
+	bus := NewWithOptions(WithIDGenerator(func() string {
+		next++
+		return "id-" + strconv.Itoa(next)
+	})).(*EventBus)
+	var ids []string
+	bus.Subscribe("topic", func(meta EventMeta) { ids = append(ids, meta.ID) })
+	bus.Publish("topic")
+	bus.Publish("topic")
+	if len(ids) != 2 || ids[0] != "id-1" || ids[1] != "id-2" || bus.NewID() != "id-3" {
+		t.Fatal(ids)
+	}
+
+	plain := New()
+	plain.Subscribe("topic", func(meta EventMeta) {
+		if meta.ID != "" {
+			t.Fail()
+		}
+	})
+	plain.Publish("topic")
+}
@@ -0,0 +1,78 @@
+package EventBus
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InFlightEvent - delivery of an event to a handler which has not returned yet
+type InFlightEvent struct {
+	Topic     string
+	Handler   string
+	Async     bool
+	Started   time.Time
+	Published time.Time
+	Args      string
+}
+
+// inFlightSet - deliveries currently running
+type inFlightSet struct {
+	lock       sync.Mutex
+	deliveries map[*inFlightDelivery]struct{}
+}
+
+type inFlightDelivery struct {
+	handler *eventHandler
+	env     *envelope
+	started time.Time
+}
+
+func (set *inFlightSet) add(handler *eventHandler, env *envelope) *inFlightDelivery {
+	delivery := &inFlightDelivery{handler, env, time.Now()}
+	set.lock.Lock()
+	set.deliveries[delivery] = struct{}{}
+	set.lock.Unlock()
+	return delivery
+}
+
+func (set *inFlightSet) remove(delivery *inFlightDelivery) {
+	set.lock.Lock()
+	delete(set.deliveries, delivery)
+	set.lock.Unlock()
+}
+
+// WithInFlightTracking keeps track of the deliveries running, listed by InFlight.
+func WithInFlightTracking() Option {
+	return func(bus *EventBus) {
+		bus.inFlight = &inFlightSet{deliveries: make(map[*inFlightDelivery]struct{})}
+	}
+}
+
+// InFlight returns the deliveries which have not returned yet, oldest first, so a wedged bus
+// shows what it is busy with. It is empty unless the bus was created WithInFlightTracking.
+func (bus *EventBus) InFlight() []InFlightEvent {
+	if bus.inFlight == nil {
+		return nil
+	}
+	bus.inFlight.lock.Lock()
+	deliveries := make([]*inFlightDelivery, 0, len(bus.inFlight.deliveries))
+	for delivery := range bus.inFlight.deliveries {
+		deliveries = append(deliveries, delivery)
+	}
+	bus.inFlight.lock.Unlock()
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].started.Before(deliveries[j].started) })
+	events := make([]InFlightEvent, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		events = append(events, InFlightEvent{
+			Topic:     delivery.env.meta.Topic,
+			Handler:   delivery.handler.name(),
+			Async:     delivery.handler.async,
+			Started:   delivery.started,
+			Published: delivery.env.meta.Published,
+			Args:      fmt.Sprint(delivery.env.args...),
+		})
+	}
+	return events
+}
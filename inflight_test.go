@@ -0,0 +1,31 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestInFlight(t *testing.T) {
+	bus := NewWithOptions(WithInFlightTracking()).(*EventBus)
+	started, release := make(chan struct{}), make(chan struct{})
+	bus.SubscribeAsync("slow", func(n int) {
+		close(started)
+		<-release
+	}, false)
+	bus.Subscribe("fast", func() {
+		if inFlight := bus.InFlight(); len(inFlight) != 2 || inFlight[1].Topic != "fast" {
+			t.Error(inFlight)
+		}
+	})
+	bus.Publish("slow", 42)
+	<-started
+	bus.Publish("fast")
+	inFlight := bus.InFlight()
+	if len(inFlight) != 1 || inFlight[0].Topic != "slow" || inFlight[0].Args != "42" || !inFlight[0].Async {
+		t.Fatal(inFlight)
+	}
+	close(release)
+	bus.WaitAsync()
+	if len(bus.InFlight()) != 0 || New().(*EventBus).InFlight() != nil {
+		t.Fail()
+	}
+}
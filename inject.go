@@ -0,0 +1,183 @@
+package EventBus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// EventMeta - description of the event being delivered, injected into handlers declaring
+// a parameter of this type
+type EventMeta struct {
+	ID        string // generated by the bus IDGenerator, empty without one
+	Topic     string
+	Seq       uint64 // position of the event in its topic, numbered from 1 without gaps
+	Published time.Time
+	Headers   Headers
+}
+
+// Headers - metadata travelling with an event, injected into handlers declaring a parameter of this type
+type Headers map[string]string
+
+// paramSource - where the value of a handler parameter comes from
+type paramSource int
+
+const (
+	fromArgs paramSource = iota
+	fromMeta
+	fromHeaders
+	fromContext
+	fromScope
+	fromEvent
+)
+
+var (
+	metaType    = reflect.TypeOf(EventMeta{})
+	headersType = reflect.TypeOf(Headers(nil))
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	eventType   = reflect.TypeOf((*Event)(nil))
+)
+
+// envelope - a published event on its way to the handlers
+type envelope struct {
+	ctx      context.Context
+	meta     EventMeta
+	args     []interface{}
+	progress *topicProgress // tracks the event for Barrier, nil when untracked
+	seq      uint64
+}
+
+func newEnvelope(topic string, args []interface{}) *envelope {
+	return &envelope{ctx: context.Background(), meta: EventMeta{Topic: topic, Published: time.Now()}, args: args}
+}
+
+// clone returns a copy of the envelope with every argument passed through cloner
+func (env *envelope) clone(cloner func(arg interface{}) interface{}) *envelope {
+	args := make([]interface{}, len(env.args))
+	for i, arg := range env.args {
+		args[i] = cloner(arg)
+	}
+	copied := *env
+	copied.args = args
+	return &copied
+}
+
+// paramSources returns the sources of the parameters of fn, nil when all come from the published arguments
+func paramSources(fn reflect.Value) []paramSource {
+	if fn.Kind() != reflect.Func {
+		return nil
+	}
+	funcType := fn.Type()
+	var sources []paramSource
+	for i := 0; i < funcType.NumIn(); i++ {
+		source := fromArgs
+		switch funcType.In(i) {
+		case metaType:
+			source = fromMeta
+		case headersType:
+			source = fromHeaders
+		case contextType:
+			source = fromContext
+		case scopeType:
+			source = fromScope
+		case eventType:
+			source = fromEvent
+		}
+		if source != fromArgs && sources == nil {
+			sources = make([]paramSource, funcType.NumIn())
+		}
+		if sources != nil {
+			sources[i] = source
+		}
+	}
+	return sources
+}
+
+// checkEventParams returns an error wrapping ErrEventParameters when the handler declares an
+// *Event parameter besides parameters for the published arguments, which would be left empty
+func (handler *eventHandler) checkEventParams() error {
+	if !handler.takesEvent() {
+		return nil
+	}
+	for i, source := range handler.sources {
+		if source == fromArgs {
+			return fmt.Errorf("parameter %d of %s: %w", i, handler.name(), ErrEventParameters)
+		}
+	}
+	return nil
+}
+
+// takesEvent reports whether the handler declares an *Event parameter, receiving the published
+// arguments through it rather than as parameters of their own
+func (handler *eventHandler) takesEvent() bool {
+	for _, source := range handler.sources {
+		if source == fromEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// bind returns the sources of the handler's parameters for an event with nargs arguments.
+// A context.Context parameter is injected unless the publisher passes as many arguments as the
+// handler has parameters besides EventMeta and Headers, so handlers which always took a context
+// as ordinary argument keep receiving it.
+func (handler *eventHandler) bind(nargs int) []paramSource {
+	if handler.sources == nil {
+		return nil
+	}
+	funcType := handler.callBack.Type()
+	ordinary, contexts := 0, 0
+	for _, source := range handler.sources {
+		switch source {
+		case fromArgs:
+			ordinary++
+		case fromContext:
+			contexts++
+		}
+	}
+	if contexts == 0 || funcType.IsVariadic() || nargs != ordinary+contexts {
+		return handler.sources
+	}
+	sources := make([]paramSource, len(handler.sources))
+	for i, source := range handler.sources {
+		if source != fromContext {
+			sources[i] = source
+		}
+	}
+	return sources
+}
+
+// positionalTypes returns the types of the parameters receiving published arguments,
+// for a variadic handler the last one is the variadic slice
+func positionalTypes(funcType reflect.Type, sources []paramSource) []reflect.Type {
+	types := make([]reflect.Type, 0, funcType.NumIn())
+	for i := 0; i < funcType.NumIn(); i++ {
+		if sources == nil || sources[i] == fromArgs {
+			types = append(types, funcType.In(i))
+		}
+	}
+	return types
+}
+
+// injectedValue returns the value of a parameter which does not come from the published arguments
+func (env *envelope) injectedValue(source paramSource) reflect.Value {
+	switch source {
+	case fromMeta:
+		return reflect.ValueOf(env.meta)
+	case fromHeaders:
+		return reflect.ValueOf(env.meta.Headers)
+	case fromEvent:
+		return reflect.ValueOf(&Event{
+			Topic:     env.meta.Topic,
+			Args:      env.args,
+			ID:        env.meta.ID,
+			Seq:       env.meta.Seq,
+			Published: env.meta.Published,
+			Headers:   env.meta.Headers,
+		})
+	default:
+		return reflect.ValueOf(&env.ctx).Elem()
+	}
+}
@@ -0,0 +1,99 @@
+package EventBus
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInjectMeta(t *testing.T) {
+	bus := New()
+	called := false
+	bus.Subscribe("topic", func(a int, meta EventMeta, s string) {
+		called = true
+		if a != 1 || s != "x" || meta.Topic != "topic" || meta.Published.IsZero() {
+			t.Fail()
+		}
+	})
+	bus.Publish("topic", 1, "x")
+	if !called {
+		t.Fail()
+	}
+}
+
+func TestInjectContextAndHeaders(t *testing.T) {
+	bus := NewStrict()
+	called := 0
+	bus.Subscribe("topic", func(ctx context.Context, headers Headers, rest ...interface{}) {
+		called++
+		if ctx == nil || headers != nil || len(rest) != 2 || rest[1] != nil {
+			t.Fail()
+		}
+	})
+	bus.Publish("topic", 1, nil)
+	if called != 1 {
+		t.Fail()
+	}
+}
+
+func TestContextPassedAsArgument(t *testing.T) {
+	bus := New()
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	values := []interface{}{}
+	bus.Subscribe("topic", func(c context.Context, a int) {
+		values = append(values, c.Value(key{}), a)
+	})
+	bus.Publish("topic", ctx, 2)
+	bus.Publish("topic", 3)
+	if len(values) != 4 || values[0] != "value" || values[1] != 2 || values[2] != nil || values[3] != 3 {
+		t.Fatal(values)
+	}
+}
+
+func TestInjectSequence(t *testing.T) {
+	bus := New().(*EventBus)
+	var seqs []uint64
+	bus.Subscribe("topic", func(meta EventMeta) { seqs = append(seqs, meta.Seq) })
+	bus.Publish("topic")
+	bus.Publish("other")
+	bus.Publish("topic")
+	if len(seqs) != 2 || seqs[0] != 1 || seqs[1] != 2 {
+		t.Fatal(seqs)
+	}
+	if bus.Sequence("topic") != 2 || bus.Sequence("other") != 1 || bus.Sequence("unknown") != 0 {
+		t.Fail()
+	}
+}
+
+func TestInjectEvent(t *testing.T) {
+	bus := NewWithOptions(WithValidation(), WithIDGenerator(TimeOrderedID)).(*EventBus)
+	var events []*Event
+	handler := func(ctx context.Context, event *Event) { events = append(events, event) }
+	bus.Subscribe("order:placed", handler)
+	bus.PublishEvent(Event{Topic: "order:placed", Args: []interface{}{"o-1", 42}, Headers: Headers{CorrelationIDHeader: "c-1"}})
+	bus.Publish("order:placed")
+	if len(events) != 2 {
+		t.Fatal(events)
+	}
+	first := events[0]
+	if first.Topic != "order:placed" || len(first.Args) != 2 || first.Args[1] != 42 || first.Seq != 1 ||
+		first.ID == "" || first.Published.IsZero() || first.Headers[CorrelationIDHeader] != "c-1" {
+		t.Fatal(first)
+	}
+	if len(events[1].Args) != 0 || events[1].Seq != 2 {
+		t.Fatal(events[1])
+	}
+
+	// the arguments are in the event, a handler cannot take them as parameters too
+	if err := bus.Subscribe("order:placed", func(event *Event, n int) {}); !errors.Is(err, ErrEventParameters) {
+		t.Fatal(err)
+	}
+	if err := bus.Replace("order:placed", handler, func(event *Event, args ...interface{}) {}); !errors.Is(err, ErrEventParameters) {
+		t.Fatal(err)
+	}
+	bus.Publish("order:placed", "o-2", 1)
+	if len(events) != 3 || len(bus.handlers["order:placed"]) != 1 {
+		t.Fatal(events)
+	}
+}
@@ -0,0 +1,124 @@
+//go:build js && wasm
+// +build js,wasm
+
+package EventBus
+
+import (
+	"encoding/json"
+	"sync"
+	"syscall/js"
+)
+
+// JSBridge - connects a bus to JavaScript in a Go wasm frontend: DOM events and JS callbacks
+// are published to topics, and topics are forwarded to JS listeners
+type JSBridge struct {
+	bus   *EventBus
+	lock  sync.Mutex
+	stops map[*func()]bool
+}
+
+// NewJSBridge returns a bridge between the bus and JavaScript
+func (bus *EventBus) NewJSBridge() *JSBridge {
+	return &JSBridge{bus: bus, stops: make(map[*func()]bool)}
+}
+
+// track keeps stop for Release and returns it, stopping once and forgetting itself
+func (bridge *JSBridge) track(stop func()) func() {
+	var once sync.Once
+	key := new(func())
+	*key = func() {
+		once.Do(func() {
+			bridge.lock.Lock()
+			delete(bridge.stops, key)
+			bridge.lock.Unlock()
+			stop()
+		})
+	}
+	bridge.lock.Lock()
+	defer bridge.lock.Unlock()
+	bridge.stops[key] = true
+	return *key
+}
+
+// Listen publishes every event of type eventType dispatched to target, an EventTarget such as an
+// element, the document or the window, to topic with the js.Value of the event as argument.
+// The handlers run within the JS event listener: they may call preventDefault, and must not
+// block on other JS callbacks. stop removes the listener.
+func (bridge *JSBridge) Listen(target js.Value, eventType, topic string) (stop func()) {
+	fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := js.Undefined()
+		if len(args) > 0 {
+			event = args[0]
+		}
+		bridge.bus.Publish(topic, event)
+		return nil
+	})
+	target.Call("addEventListener", eventType, fn)
+	return bridge.track(func() {
+		target.Call("removeEventListener", eventType, fn)
+		fn.Release()
+	})
+}
+
+// Callback returns a JS function publishing its arguments to topic, one js.Value each, for JS
+// libraries taking callbacks. Release frees it, the function must not be called afterwards.
+func (bridge *JSBridge) Callback(topic string) js.Func {
+	fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg
+		}
+		bridge.bus.Publish(topic, values...)
+		return nil
+	})
+	bridge.track(fn.Release)
+	return fn
+}
+
+// Forward calls listener, a JS function, with the arguments of every event of topic. Arguments
+// are converted by js.ValueOf when it supports them, through their JSON encoding otherwise.
+// The listener is called from a goroutine of its own, in publish order, so it may call back
+// into Go. stop unsubscribes it.
+func (bridge *JSBridge) Forward(topic string, listener js.Value) (stop func(), err error) {
+	if listener.Type() != js.TypeFunction {
+		return nil, ErrNotAFunction
+	}
+	handler, err := bridge.bus.subscribeHandler(topic, func(args ...interface{}) {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = toJS(arg)
+		}
+		listener.Invoke(values...)
+	}, false, true, true)
+	if err != nil {
+		return nil, err
+	}
+	return bridge.track(func() { bridge.bus.removeHandlerPtr(topic, handler) }), nil
+}
+
+// Release removes the listeners and forwards of the bridge and frees its callbacks
+func (bridge *JSBridge) Release() {
+	bridge.lock.Lock()
+	stops := make([]func(), 0, len(bridge.stops))
+	for stop := range bridge.stops {
+		stops = append(stops, *stop)
+	}
+	bridge.lock.Unlock()
+	for _, stop := range stops {
+		stop()
+	}
+}
+
+// toJS converts a published argument for a JS listener
+func toJS(arg interface{}) (value interface{}) {
+	switch arg.(type) {
+	case nil, js.Value, js.Func, bool, string, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+		return arg
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return err.Error()
+	}
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
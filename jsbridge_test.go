@@ -0,0 +1,50 @@
+//go:build js && wasm
+// +build js,wasm
+
+package EventBus
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+func TestJSBridgeListen(t *testing.T) {
+	bus := New().(*EventBus)
+	bridge := bus.NewJSBridge()
+	defer bridge.Release()
+	var types []string
+	bus.Subscribe("ui:click", func(event js.Value) { types = append(types, event.Get("type").String()) })
+	target := js.Global().Get("EventTarget").New()
+	stop := bridge.Listen(target, "click", "ui:click")
+	target.Call("dispatchEvent", js.Global().Get("Event").New("click"))
+	stop()
+	target.Call("dispatchEvent", js.Global().Get("Event").New("click"))
+	if len(types) != 1 || types[0] != "click" {
+		t.Fatal(types)
+	}
+
+	var got []int
+	bus.Subscribe("js:callback", func(a, b js.Value) { got = append(got, a.Int(), b.Int()) })
+	bridge.Callback("js:callback").Invoke(1, 2)
+	if len(got) != 2 || got[1] != 2 {
+		t.Fatal(got)
+	}
+}
+
+func TestJSBridgeForward(t *testing.T) {
+	bus := New().(*EventBus)
+	bridge := bus.NewJSBridge()
+	defer bridge.Release()
+	listener := js.Global().Get("Function").New("id", "order", "globalThis.forwarded = id + ':' + order.Total")
+	if _, err := bridge.Forward("order:placed", listener); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("order:placed", "o-1", struct{ Total int }{42})
+	bus.WaitAsync()
+	if forwarded := js.Global().Get("forwarded").String(); forwarded != "o-1:42" {
+		t.Fatal(forwarded)
+	}
+	if _, err := bridge.Forward("order:placed", js.ValueOf(1)); err == nil {
+		t.Fatal("forwarded to a number")
+	}
+}
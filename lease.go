@@ -0,0 +1,212 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LeaseStore - named leases shared by the processes using the same store, so a single one of
+// them acts on it at a time
+type LeaseStore interface {
+	// Acquire takes the lease for owner, or renews it when owner holds it already, until ttl
+	// elapsed. It reports whether owner holds the lease, false while another owner's lease runs.
+	Acquire(name, owner string, ttl time.Duration) (bool, error)
+	// Release gives the lease up, when owner holds it
+	Release(name, owner string) error
+}
+
+// fileLeaseStore - LeaseStore keeping a file per lease with its owner and expiry time. A lock
+// file created exclusively guards every change, so processes on the same machine or sharing a
+// file system with atomic exclusive creates can use it.
+type fileLeaseStore struct {
+	dir string
+}
+
+// leaseLockTimeout - age of a lock file taken as left by a crashed process
+const leaseLockTimeout = 10 * time.Second
+
+// NewFileLeaseStore returns a LeaseStore keeping leases in files of dir, typically next to the
+// file of the ScheduleStore it coordinates.
+func NewFileLeaseStore(dir string) (LeaseStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &fileLeaseStore{dir}, nil
+}
+
+func (store *fileLeaseStore) Acquire(name, owner string, ttl time.Duration) (bool, error) {
+	unlock, err := store.lock(name)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	holder, expires, err := store.read(name)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if holder != "" && holder != owner && now.Before(expires) {
+		return false, nil
+	}
+	return true, store.write(name, owner, now.Add(ttl))
+}
+
+func (store *fileLeaseStore) Release(name, owner string) error {
+	unlock, err := store.lock(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	holder, _, err := store.read(name)
+	if err != nil || holder != owner {
+		return err
+	}
+	return os.Remove(store.path(name))
+}
+
+func (store *fileLeaseStore) path(name string) string {
+	return filepath.Join(store.dir, unsafeFileChars.ReplaceAllString(name, "_")+".lease")
+}
+
+// lock creates the lock file of the lease, waiting while another process holds it
+func (store *fileLeaseStore) lock(name string) (func(), error) {
+	return lockFile(store.path(name))
+}
+
+// lockFile creates the lock file guarding changes to the file at path, waiting while another
+// process holds it. Lock files older than leaseLockTimeout are taken as left by a crash.
+func lockFile(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(leaseLockTimeout)
+	for {
+		file, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lock) }, nil
+		} else if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > leaseLockTimeout {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by %s", path, lock)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// read returns the owner and expiry time of the lease, no owner when it is not held
+func (store *fileLeaseStore) read(name string) (string, time.Time, error) {
+	data, err := ioutil.ReadFile(store.path(name))
+	if os.IsNotExist(err) {
+		return "", time.Time{}, nil
+	} else if err != nil {
+		return "", time.Time{}, err
+	}
+	fields := strings.SplitN(string(data), "\n", 2)
+	if len(fields) != 2 {
+		return "", time.Time{}, errors.New("malformed lease file " + store.path(name))
+	}
+	expires, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return fields[0], time.Unix(0, expires), nil
+}
+
+func (store *fileLeaseStore) write(name, owner string, expires time.Time) error {
+	path := store.path(name)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(owner+"\n"+strconv.FormatInt(expires.UnixNano(), 10)), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// WithLease makes schedulers of several processes sharing a ScheduleStore active-passive: only the one holding the
+// named lease of leases publishes, the others save the events they are given and take over
+// once the lease expires. The lease is renewed every third of ttl. Events scheduled or
+// cancelled by a passive scheduler reach the active one at its next renewal, and events which
+// became due meanwhile are handled by the catch-up policy on takeover.
+func WithLease(leases LeaseStore, name string, ttl time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.leases, s.lease, s.ttl = leases, name, ttl
+	}
+}
+
+// Active reports whether the scheduler publishes its events, false while another scheduler
+// holds its lease, see WithLease.
+func (s *Scheduler) Active() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return !s.stopped && (s.leases == nil || s.active)
+}
+
+// renew keeps the lease until Stop, taking over when it becomes free and disarming every event
+// when it is lost
+func (s *Scheduler) renew() {
+	ticker := time.NewTicker(s.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+		held, err := s.leases.Acquire(s.lease, s.owner, s.ttl)
+		held = held && err == nil
+		s.lock.Lock()
+		if s.stopped {
+			s.lock.Unlock()
+			if held {
+				s.leases.Release(s.lease, s.owner)
+			}
+			return
+		}
+		took := held && !s.active
+		if s.active = held; !held {
+			s.disarmLocked()
+		}
+		s.lock.Unlock()
+		if took {
+			s.load()
+		} else if held {
+			s.sync()
+		}
+	}
+}
+
+// sync arms the stored events scheduled by passive schedulers and disarms those they cancelled.
+// The store is read with the lock held, so an event published and deleted meanwhile is not armed again.
+func (s *Scheduler) sync() {
+	if s.store == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	events, err := s.store.Load()
+	if err != nil || !s.active || s.stopped {
+		return
+	}
+	stored := make(map[string]bool, len(events))
+	for _, event := range events {
+		stored[event.ID] = true
+		if _, armed := s.timers[event.ID]; !armed {
+			s.armLocked(event)
+		}
+	}
+	for id, timer := range s.timers {
+		if !stored[id] {
+			timer.Stop()
+			delete(s.timers, id)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package EventBus
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLeaseStore(t *testing.T) {
+	leases, err := NewFileLeaseStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if held, err := leases.Acquire("jobs", "a", 50*time.Millisecond); !held || err != nil {
+		t.Fatal(held, err)
+	}
+	if held, _ := leases.Acquire("jobs", "b", time.Minute); held {
+		t.Fatal("lease taken while held")
+	}
+	if held, _ := leases.Acquire("jobs", "a", 50*time.Millisecond); !held {
+		t.Fatal("lease not renewed")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if held, _ := leases.Acquire("jobs", "b", time.Minute); !held {
+		t.Fatal("expired lease not taken")
+	}
+	leases.Release("jobs", "a")
+	if held, _ := leases.Acquire("jobs", "a", time.Minute); held {
+		t.Fatal("lease released by another owner")
+	}
+	leases.Release("jobs", "b")
+	if held, _ := leases.Acquire("jobs", "a", time.Minute); !held {
+		t.Fatal("released lease not taken")
+	}
+}
+
+func TestSchedulerLeaseFailover(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileScheduleStore(filepath.Join(dir, "schedule"))
+	leases, _ := NewFileLeaseStore(dir)
+	received := make(chan string, 4)
+
+	first := New().(*EventBus)
+	first.Subscribe("topic", func(s string) { received <- "first:" + s })
+	active, err := first.NewScheduler(store, CatchUpAll, WithLease(leases, "schedule", 60*time.Millisecond))
+	if err != nil || !active.Active() {
+		t.Fatal(err)
+	}
+	second := New().(*EventBus)
+	defer second.Close()
+	second.Subscribe("topic", func(s string) { received <- "second:" + s })
+	passive, err := second.NewScheduler(store, CatchUpAll, WithLease(leases, "schedule", 60*time.Millisecond))
+	if err != nil || passive.Active() {
+		t.Fatal(err)
+	}
+
+	// scheduled by the passive one, published by the active one once renewed
+	passive.PublishAfter("topic", 10*time.Millisecond, "a")
+	select {
+	case s := <-received:
+		if s != "first:a" {
+			t.Fatal(s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event not published")
+	}
+
+	first.Close()
+	passive.PublishAfter("topic", 10*time.Millisecond, "b")
+	select {
+	case s := <-received:
+		if s != "second:b" || !passive.Active() {
+			t.Fatal(s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("passive scheduler did not take over")
+	}
+}
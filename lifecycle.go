@@ -0,0 +1,121 @@
+package EventBus
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+const (
+	// SignalTopic - topic receiving the OS signals (os.Signal argument) requested in NotifySignals
+	SignalTopic = "os:signal"
+	// LifecycleStarting - topic published once when the process starts initializing
+	LifecycleStarting = "lifecycle:starting"
+	// LifecycleReady - topic published once when the process is ready to serve
+	LifecycleReady = "lifecycle:ready"
+	// LifecycleStopping - topic published once when the process begins shutting down
+	LifecycleStopping = "lifecycle:stopping"
+)
+
+// Lifecycle - producer of process lifecycle stages and OS signals, so components can
+// coordinate startup and shutdown through the bus
+type Lifecycle struct {
+	bus       Bus
+	lock      sync.Mutex
+	published map[string]bool
+	stage     string
+	stopOn    map[os.Signal]bool
+	signals   chan os.Signal
+	done      chan struct{}
+}
+
+// NewLifecycle - create a lifecycle producer publishing to bus
+func NewLifecycle(bus Bus) *Lifecycle {
+	return &Lifecycle{bus: bus, published: make(map[string]bool), stopOn: make(map[os.Signal]bool)}
+}
+
+// Stage returns the last stage topic published, empty before Starting.
+func (lifecycle *Lifecycle) Stage() string {
+	lifecycle.lock.Lock()
+	defer lifecycle.lock.Unlock()
+	return lifecycle.stage
+}
+
+// Starting publishes LifecycleStarting.
+func (lifecycle *Lifecycle) Starting() {
+	lifecycle.enter(LifecycleStarting)
+}
+
+// Ready publishes LifecycleReady.
+func (lifecycle *Lifecycle) Ready() {
+	lifecycle.enter(LifecycleReady)
+}
+
+// Stopping publishes LifecycleStopping.
+func (lifecycle *Lifecycle) Stopping() {
+	lifecycle.enter(LifecycleStopping)
+}
+
+// every stage is published at most once, however many times it is entered
+func (lifecycle *Lifecycle) enter(stage string) {
+	lifecycle.lock.Lock()
+	if lifecycle.published[stage] {
+		lifecycle.lock.Unlock()
+		return
+	}
+	lifecycle.published[stage] = true
+	lifecycle.stage = stage
+	lifecycle.lock.Unlock()
+	lifecycle.bus.Publish(stage)
+}
+
+// NotifySignals starts publishing the given signals to SignalTopic, as signal.Notify
+// no signals stands for all of them.
+func (lifecycle *Lifecycle) NotifySignals(sigs ...os.Signal) error {
+	lifecycle.lock.Lock()
+	defer lifecycle.lock.Unlock()
+	if lifecycle.signals != nil {
+		return fmt.Errorf("Lifecycle signals %w", ErrAlreadyStarted)
+	}
+	lifecycle.signals = make(chan os.Signal, 1)
+	lifecycle.done = make(chan struct{})
+	signal.Notify(lifecycle.signals, sigs...)
+	go lifecycle.forward(lifecycle.signals, lifecycle.done)
+	return nil
+}
+
+// StopOn makes the given signals enter the stopping stage after being published, e.g. os.Interrupt.
+func (lifecycle *Lifecycle) StopOn(sigs ...os.Signal) {
+	lifecycle.lock.Lock()
+	defer lifecycle.lock.Unlock()
+	for _, sig := range sigs {
+		lifecycle.stopOn[sig] = true
+	}
+}
+
+// Stop stops publishing signals.
+func (lifecycle *Lifecycle) Stop() {
+	lifecycle.lock.Lock()
+	signals, done := lifecycle.signals, lifecycle.done
+	lifecycle.signals = nil
+	lifecycle.lock.Unlock()
+	if signals != nil {
+		signal.Stop(signals)
+		close(signals)
+		<-done
+	}
+}
+
+func (lifecycle *Lifecycle) forward(signals chan os.Signal, done chan struct{}) {
+	defer close(done)
+	for sig := range signals {
+		lifecycle.bus.Publish(SignalTopic, sig)
+		lifecycle.lock.Lock()
+		stop := lifecycle.stopOn[sig]
+		lifecycle.lock.Unlock()
+		if stop {
+			lifecycle.Stopping()
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package EventBus
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLifecycleStages(t *testing.T) {
+	bus := New()
+	var stages []string
+	for _, stage := range []string{LifecycleStarting, LifecycleReady, LifecycleStopping} {
+		stage := stage
+		bus.Subscribe(stage, func() { stages = append(stages, stage) })
+	}
+	lifecycle := NewLifecycle(bus)
+	lifecycle.Starting()
+	lifecycle.Ready()
+	lifecycle.Ready()
+	if lifecycle.Stage() != LifecycleReady || len(stages) != 2 {
+		t.Fail()
+	}
+	lifecycle.Stopping()
+	if len(stages) != 3 || stages[2] != LifecycleStopping {
+		t.Fail()
+	}
+}
+
+func TestLifecycleSignals(t *testing.T) {
+	bus := New()
+	received := make(chan os.Signal, 1)
+	stopping := make(chan bool, 1)
+	bus.Subscribe(SignalTopic, func(sig os.Signal) { received <- sig })
+	bus.Subscribe(LifecycleStopping, func() { stopping <- true })
+
+	lifecycle := NewLifecycle(bus)
+	lifecycle.StopOn(os.Interrupt)
+	if lifecycle.NotifySignals(os.Interrupt) != nil || lifecycle.NotifySignals(os.Interrupt) == nil {
+		t.Fail()
+	}
+	lifecycle.signals <- os.Interrupt
+	if <-received != os.Interrupt || !<-stopping {
+		t.Fail()
+	}
+	lifecycle.Stop()
+	if lifecycle.Stage() != LifecycleStopping {
+		t.Fail()
+	}
+}
@@ -0,0 +1,58 @@
+package EventBus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// handlerSource - handlers of the topics, looked up by subscribers
+type handlerSource interface {
+	lookup(topic string) []*eventHandler
+}
+
+// handlerMap - handlers of the topics in priority order, the slices are never modified in place
+type handlerMap map[string][]*eventHandler
+
+func (handlers handlerMap) lookup(topic string) []*eventHandler {
+	return handlers[topic]
+}
+
+// liveHandlers - copy of the handler table of the bus read by Publish without the bus lock, so
+// publishes on unrelated topics never contend. Written under the bus lock, see setHandlers.
+type liveHandlers struct {
+	topics sync.Map     // topic -> []*eventHandler
+	trace  atomic.Value // *traceRing, see EnableTrace
+}
+
+func (live *liveHandlers) lookup(topic string) []*eventHandler {
+	handlers, _ := live.topics.Load(topic)
+	subscribed, _ := handlers.([]*eventHandler)
+	return subscribed
+}
+
+func (live *liveHandlers) traceRing() *traceRing {
+	ring, _ := live.trace.Load().(*traceRing)
+	return ring
+}
+
+// setHandlers replaces the handlers of the topic, dropping its entry when there are none left.
+// The bus lock must be held, and handlers must be a new slice: Publish may still be iterating
+// the previous one.
+func (bus *EventBus) setHandlers(topic string, handlers []*eventHandler) {
+	if len(handlers) == 0 {
+		delete(bus.handlers, topic)
+		bus.live.topics.Delete(topic)
+		return
+	}
+	bus.handlers[topic] = handlers
+	bus.live.topics.Store(topic, handlers)
+}
+
+// published returns the handlers of the topic and the trace ring, from the sealed table once
+// the bus is sealed
+func (bus *EventBus) published(topic string) ([]*eventHandler, *traceRing) {
+	if table := bus.sealedTable(); table != nil {
+		return bus.subscribers(table.handlers, topic), table.trace
+	}
+	return bus.subscribers(&bus.live, topic), bus.live.traceRing()
+}
@@ -0,0 +1,69 @@
+package EventBus
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPublishUnlocked(t *testing.T) {
+	bus := New().(*EventBus)
+	var seen []string
+	// the bus lock is not held while sync handlers run, so they may subscribe and publish
+	bus.Subscribe("outer", func() {
+		seen = append(seen, "outer")
+		bus.Subscribe("inner", func() { seen = append(seen, "inner") })
+		bus.Publish("inner")
+	})
+	bus.Publish("outer")
+	if len(seen) != 2 || seen[1] != "inner" {
+		t.Fatal(seen)
+	}
+	// a once handler is claimed by a single publish
+	var once int32
+	bus.SubscribeOnce("once", func() { atomic.AddInt32(&once, 1) })
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bus.Publish("once")
+		}()
+	}
+	wg.Wait()
+	if once != 1 || bus.HasCallback("once") {
+		t.Fatal(once)
+	}
+}
+
+func TestPublishWhileSubscribing(t *testing.T) {
+	bus := New().(*EventBus)
+	var calls int64
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		topic := fmt.Sprintf("topic-%d", i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				handler := func() { atomic.AddInt64(&calls, 1) }
+				bus.Subscribe(topic, handler)
+				bus.Unsubscribe(topic, handler)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bus.Publish(topic)
+			}
+		}()
+	}
+	wg.Wait()
+	bus.Subscribe("topic-0", func() { atomic.AddInt64(&calls, 1000) })
+	before := atomic.LoadInt64(&calls)
+	bus.Publish("topic-0")
+	if atomic.LoadInt64(&calls) != before+1000 || bus.HasCallback("topic-1") {
+		t.Fatal(calls)
+	}
+}
@@ -0,0 +1,284 @@
+package EventBus
+
+import (
+	"reflect"
+	"sync"
+)
+
+// MemoryLimitTopic - topic the MemoryStats are published to when the bus goes over its limit
+// and evicting could not bring it back under, see WithMemoryLimit
+const MemoryLimitTopic = "bus:memory_limit"
+
+// EvictionPolicy - what the bus does once the memory it holds goes over its limit
+type EvictionPolicy int
+
+const (
+	// EvictOldest - drop the oldest trace records, then the oldest PublishOnce keys of any topic
+	EvictOldest EvictionPolicy = iota
+	// ReportOnly - drop nothing, only publish MemoryLimitTopic
+	ReportOnly
+)
+
+// MemoryStats - approximate bytes held by the bus, by what holds them. Spools are kept on disk
+// and not counted.
+type MemoryStats struct {
+	Trace   int64 // records kept by EnableTrace
+	Dedup   int64 // keys remembered by PublishOnce
+	Queues  int64 // arguments of the async deliveries which did not complete yet
+	States  int64 // current values of the state topics, see SetState
+	Total   int64
+	Limit   int64  // see WithMemoryLimit, zero when unbounded
+	Evicted uint64 // trace records and PublishOnce keys dropped to stay under Limit
+}
+
+// memory kinds, indexes of memoryAccount.bytes
+const (
+	memoryTrace = iota
+	memoryDedup
+	memoryQueues
+	memoryStates
+	memoryKinds
+)
+
+var (
+	traceRecordSize   = int64(reflect.TypeOf(TraceRecord{}).Size())
+	traceDeliverySize = int64(reflect.TypeOf(TraceDelivery{}).Size())
+	dedupKeySize      = int64(reflect.TypeOf(dedupKey{}).Size()) + 64 // with the map entry and order slot
+)
+
+// memoryAccount - bytes held by the bus, a nil account counts nothing
+type memoryAccount struct {
+	lock     sync.Mutex
+	bytes    [memoryKinds]int64
+	limit    int64
+	policy   EvictionPolicy
+	evicted  uint64
+	exceeded bool // over the limit after evicting, reported once until back under
+}
+
+// WithMemoryAccounting counts the approximate memory held by trace records, PublishOnce keys,
+// pending async deliveries and state topics, see MemoryStats. Values are sized by walking
+// them, which costs some time on every async delivery and state change.
+func WithMemoryAccounting() Option {
+	return func(bus *EventBus) {
+		if bus.memory == nil {
+			bus.memory = &memoryAccount{}
+		}
+	}
+}
+
+// WithMemoryLimit counts memory as WithMemoryAccounting and keeps it under limit bytes after
+// every publish according to policy. When nothing is left to evict, the bus being over the
+// limit is published to MemoryLimitTopic, once until it is back under.
+func WithMemoryLimit(limit int64, policy EvictionPolicy) Option {
+	return func(bus *EventBus) {
+		WithMemoryAccounting()(bus)
+		bus.memory.limit, bus.memory.policy = limit, policy
+	}
+}
+
+// MemoryStats returns the memory held by the bus, zero unless created WithMemoryAccounting or WithMemoryLimit.
+func (bus *EventBus) MemoryStats() MemoryStats {
+	account := bus.memory
+	if account == nil {
+		return MemoryStats{}
+	}
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	stats := MemoryStats{
+		Trace:   account.bytes[memoryTrace],
+		Dedup:   account.bytes[memoryDedup],
+		Queues:  account.bytes[memoryQueues],
+		States:  account.bytes[memoryStates],
+		Limit:   account.limit,
+		Evicted: account.evicted,
+	}
+	stats.Total = stats.Trace + stats.Dedup + stats.Queues + stats.States
+	return stats
+}
+
+func (account *memoryAccount) add(kind int, n int64) {
+	if account == nil || n == 0 {
+		return
+	}
+	account.lock.Lock()
+	account.bytes[kind] += n
+	account.lock.Unlock()
+}
+
+// sizeOf returns the approximate size of v, zero when nothing is counted
+func (account *memoryAccount) sizeOf(v interface{}) int64 {
+	if account == nil {
+		return 0
+	}
+	return approximateSize(v)
+}
+
+// hold counts the arguments of a delivery until the returned function is called
+func (account *memoryAccount) hold(kind int, args []interface{}) func() {
+	size := account.sizeOf(args)
+	account.add(kind, size)
+	return func() { account.add(kind, -size) }
+}
+
+func (account *memoryAccount) over() bool {
+	account.lock.Lock()
+	defer account.lock.Unlock()
+	var total int64
+	for _, n := range account.bytes {
+		total += n
+	}
+	return total > account.limit
+}
+
+// limitMemory applies the eviction policy when the bus is over its memory limit
+func (bus *EventBus) limitMemory() {
+	account := bus.memory
+	if account == nil || account.limit <= 0 {
+		return
+	}
+	over := account.over()
+	for over && account.policy == EvictOldest && bus.evictOldest() {
+		account.lock.Lock()
+		account.evicted++
+		account.lock.Unlock()
+		over = account.over()
+	}
+	account.lock.Lock()
+	report := over && !account.exceeded
+	account.exceeded = over
+	account.lock.Unlock()
+	if report {
+		bus.Publish(MemoryLimitTopic, bus.MemoryStats())
+	}
+}
+
+// evictOldest drops the oldest trace record, or the oldest PublishOnce key when no record is
+// left. It reports whether anything was dropped.
+func (bus *EventBus) evictOldest() bool {
+	bus.lock.Lock()
+	ring := bus.trace
+	bus.lock.Unlock()
+	if ring != nil && ring.evictOldest() {
+		return true
+	}
+
+	bus.onceLock.Lock()
+	defer bus.onceLock.Unlock()
+	var oldest *dedupSet
+	for _, set := range bus.onceKeys {
+		set.dropForgotten()
+		if len(set.order) > 0 && (oldest == nil || set.order[0].published.Before(oldest.order[0].published)) {
+			oldest = set
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	oldest.forget(oldest.order[0].key)
+	oldest.stats.Evicted++
+	return true
+}
+
+// approximateSize returns the bytes of v and of the values it references, each counted once.
+// Channels and functions count as a reference only.
+func approximateSize(v interface{}) int64 {
+	if v == nil {
+		return 0
+	}
+	value := reflect.ValueOf(v)
+	return int64(value.Type().Size()) + make(sizer).referenced(value)
+}
+
+// sizer - references already counted, by address and type
+type sizer map[copyKey]bool
+
+// referenced returns the bytes referenced by value, not counting value itself
+func (s sizer) referenced(value reflect.Value) int64 {
+	switch value.Kind() {
+	case reflect.String:
+		return int64(value.Len())
+	case reflect.Ptr:
+		if value.IsNil() || s.seen(value, 0) {
+			return 0
+		}
+		return int64(value.Elem().Type().Size()) + s.referenced(value.Elem())
+	case reflect.Interface:
+		if value.IsNil() {
+			return 0
+		}
+		return int64(value.Elem().Type().Size()) + s.referenced(value.Elem())
+	case reflect.Slice:
+		if value.IsNil() || s.seen(value, value.Len()) {
+			return 0
+		}
+		size := int64(value.Cap()) * int64(value.Type().Elem().Size())
+		if references(value.Type().Elem()) {
+			for i := 0; i < value.Len(); i++ {
+				size += s.referenced(value.Index(i))
+			}
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		if references(value.Type().Elem()) {
+			for i := 0; i < value.Len(); i++ {
+				size += s.referenced(value.Index(i))
+			}
+		}
+		return size
+	case reflect.Map:
+		if value.IsNil() || s.seen(value, 0) {
+			return 0
+		}
+		entry := int64(value.Type().Key().Size() + value.Type().Elem().Size())
+		size := int64(value.Len()) * entry
+		for _, key := range value.MapKeys() {
+			size += s.referenced(key) + s.referenced(value.MapIndex(key))
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < value.NumField(); i++ {
+			size += s.referenced(value.Field(i))
+		}
+		return size
+	}
+	return 0
+}
+
+func (s sizer) seen(value reflect.Value, length int) bool {
+	key := copyKey{value.Type(), value.Pointer(), length}
+	if s[key] {
+		return true
+	}
+	s[key] = true
+	return false
+}
+
+// references reports whether values of type t may reference other memory
+func references(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return references(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if references(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.String, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// recordSize returns the approximate size of a trace record
+func recordSize(record *TraceRecord) int64 {
+	size := traceRecordSize + int64(len(record.Topic)+len(record.Args))
+	for _, delivery := range record.Deliveries {
+		size += traceDeliverySize + int64(len(delivery.Handler))
+	}
+	return size
+}
@@ -0,0 +1,102 @@
+package EventBus
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMemoryAccounting(t *testing.T) {
+	bus := NewWithOptions(WithMemoryAccounting()).(*EventBus)
+	bus.EnableTrace(10)
+	bus.Subscribe("topic", func(s string) {})
+	bus.Publish("topic", "payload")
+	bus.PublishOnce("init", "key")
+	bus.SetState("config", []byte("0123456789"))
+
+	stats := bus.MemoryStats()
+	if stats.Trace == 0 || stats.Dedup == 0 || stats.States < 10 || stats.Queues != 0 {
+		t.Fatal(stats)
+	}
+	if stats.Total != stats.Trace+stats.Dedup+stats.States {
+		t.Fatal(stats)
+	}
+	bus.ForgetOnce("init", "key")
+	bus.SetState("config", nil)
+	bus.EnableTrace(0)
+	if stats = bus.MemoryStats(); stats.Total != 0 {
+		t.Fatal(stats)
+	}
+}
+
+func TestMemoryAccountingQueues(t *testing.T) {
+	bus := NewWithOptions(WithMemoryAccounting()).(*EventBus)
+	release := make(chan struct{})
+	bus.SubscribeAsync("topic", func(payload []byte) { <-release }, false)
+	bus.Publish("topic", make([]byte, 1000))
+	if stats := bus.MemoryStats(); stats.Queues < 1000 {
+		t.Fatal(stats)
+	}
+	close(release)
+	bus.WaitAsync()
+	if stats := bus.MemoryStats(); stats.Queues != 0 {
+		t.Fatal(stats)
+	}
+}
+
+func TestMemoryLimitEvictsOldest(t *testing.T) {
+	bus := NewWithOptions(WithMemoryLimit(2000, EvictOldest)).(*EventBus)
+	bus.EnableTrace(100)
+	for i := 0; i < 50; i++ {
+		bus.PublishOnce("init", strconv.Itoa(i))
+	}
+	stats := bus.MemoryStats()
+	if stats.Total > stats.Limit || stats.Evicted == 0 {
+		t.Fatal(stats)
+	}
+	// trace records go first, then the oldest keys
+	if records := bus.Trace(); len(records) != 0 {
+		t.Fatal(len(records))
+	}
+	if dedup := bus.DedupStats("init"); dedup.Keys == 0 || dedup.Evicted == 0 || !bus.PublishOnce("init", "0") || bus.PublishOnce("init", "49") {
+		t.Fatal(dedup)
+	}
+
+	var reported []MemoryStats
+	bus.Subscribe(MemoryLimitTopic, func(stats MemoryStats) { reported = append(reported, stats) })
+	bus.SetState("big", make([]byte, 5000))
+	bus.Publish("other")
+	if len(reported) != 1 || reported[0].States < 5000 {
+		t.Fatal(reported)
+	}
+	if stats := bus.MemoryStats(); stats.Trace != 0 || stats.Dedup != 0 {
+		t.Fatal(stats)
+	}
+}
+
+func TestMemoryLimitReportOnly(t *testing.T) {
+	bus := NewWithOptions(WithMemoryLimit(100, ReportOnly)).(*EventBus)
+	bus.EnableTrace(10)
+	reports := 0
+	bus.Subscribe(MemoryLimitTopic, func(stats MemoryStats) { reports++ })
+	for i := 0; i < 5; i++ {
+		bus.Publish("topic", "payload")
+	}
+	if reports != 1 || len(bus.Trace()) < 5 || bus.MemoryStats().Evicted != 0 {
+		t.Fatal(reports, bus.MemoryStats())
+	}
+}
+
+func TestApproximateSize(t *testing.T) {
+	type node struct {
+		name string
+		next *node
+	}
+	n := &node{name: "abc"}
+	n.next = n
+	if size := approximateSize(n); size < 3 || size > 200 {
+		t.Fatal(size)
+	}
+	if small, large := approximateSize([]int{1}), approximateSize(make([]int, 100)); large-small < 99*8 {
+		t.Fatal(small, large)
+	}
+}
@@ -0,0 +1,103 @@
+package EventBus
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// merger - buffer putting the events of several topics back in timestamp order before
+// handing them to a single handler
+type merger struct {
+	bus      *EventBus
+	handler  *eventHandler
+	window   time.Duration
+	lock     sync.Mutex
+	pending  []*envelope // sorted by publish time
+	timer    *time.Timer
+	deliver  sync.Mutex // keeps deliveries sequential
+	handlers map[string]*eventHandler
+}
+
+// SubscribeMerged subscribes fn to every topic of topics as a single stream ordered by publish
+// time: events are held for the reordering window, so one published up to window later but
+// stamped earlier is still delivered first. fn runs outside the bus lock, one event at a time,
+// and may take EventMeta to tell the topics apart. Pending events count for WaitAsync.
+// Returns error if `fn` is not a function.
+func (bus *EventBus) SubscribeMerged(topics []string, window time.Duration, fn interface{}) (stop func(), err error) {
+	if err := checkFunc(fn); err != nil {
+		return nil, err
+	}
+	m := &merger{
+		bus:      bus,
+		handler:  newEventHandler(fn, false, false, false),
+		window:   window,
+		handlers: make(map[string]*eventHandler, len(topics)),
+	}
+	for _, topic := range topics {
+		handler, err := bus.subscribeHandler(topic, m.push, false, false, false)
+		if err != nil {
+			m.stop()
+			return nil, err
+		}
+		m.handlers[topic] = handler
+	}
+	return m.stop, nil
+}
+
+func (m *merger) push(meta EventMeta, args ...interface{}) {
+	env := newEnvelope(meta.Topic, args)
+	env.meta = meta
+	m.bus.wg.Add(1)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	i := sort.Search(len(m.pending), func(i int) bool { return m.pending[i].meta.Published.After(meta.Published) })
+	m.pending = append(m.pending, nil)
+	copy(m.pending[i+1:], m.pending[i:])
+	m.pending[i] = env
+	if m.timer == nil {
+		m.timer = time.AfterFunc(m.window, m.flush)
+	}
+}
+
+// flush delivers the events which left the reordering window and waits for the next one
+func (m *merger) flush() {
+	m.deliver.Lock()
+	defer m.deliver.Unlock()
+	m.lock.Lock()
+	horizon := time.Now().Add(-m.window)
+	n := sort.Search(len(m.pending), func(i int) bool { return m.pending[i].meta.Published.After(horizon) })
+	ready := m.pending[:n]
+	m.pending = append([]*envelope(nil), m.pending[n:]...)
+	m.timer = nil
+	if len(m.pending) > 0 {
+		m.timer = time.AfterFunc(m.pending[0].meta.Published.Sub(horizon), m.flush)
+	}
+	m.lock.Unlock()
+	m.run(ready)
+}
+
+func (m *merger) run(envs []*envelope) {
+	for _, env := range envs {
+		m.bus.doPublish(m.handler, nil, env)
+		m.bus.wg.Done()
+	}
+}
+
+// stop unsubscribes from the topics and delivers the pending events at once
+func (m *merger) stop() {
+	for topic, handler := range m.handlers {
+		m.bus.removeHandlerPtr(topic, handler)
+	}
+	m.deliver.Lock()
+	defer m.deliver.Unlock()
+	m.lock.Lock()
+	ready := m.pending
+	m.pending = nil
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	m.lock.Unlock()
+	m.run(ready)
+}
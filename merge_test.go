@@ -0,0 +1,50 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeMerged(t *testing.T) {
+	bus := New().(*EventBus)
+	var got []string
+	stop, err := bus.SubscribeMerged([]string{"orders", "payments"}, 5*time.Millisecond, func(meta EventMeta, id int) {
+		got = append(got, meta.Topic)
+		if meta.Topic == "orders" {
+			bus.Publish("audit", id)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("orders", 1)
+	bus.Publish("payments", 1)
+	bus.Publish("orders", 2)
+	bus.Publish("other", 2)
+	bus.WaitAsync()
+	if len(got) != 3 || got[0] != "orders" || got[1] != "payments" || got[2] != "orders" {
+		t.Fatal(got)
+	}
+
+	bus.Publish("payments", 2)
+	stop()
+	if len(got) != 4 || bus.HasCallback("orders") || bus.HasCallback("payments") {
+		t.Fatal(got)
+	}
+	if _, err := bus.SubscribeMerged([]string{"orders"}, time.Millisecond, "String"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestMergerReorders(t *testing.T) {
+	bus := New().(*EventBus)
+	var got []int
+	m := &merger{bus: bus, handler: newEventHandler(func(n int) { got = append(got, n) }, false, false, false), window: 5 * time.Millisecond}
+	now := time.Now()
+	m.push(EventMeta{Topic: "b", Published: now.Add(time.Millisecond)}, 2)
+	m.push(EventMeta{Topic: "a", Published: now}, 1)
+	bus.WaitAsync()
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatal(got)
+	}
+}
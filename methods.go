@@ -0,0 +1,75 @@
+package EventBus
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// methodKey - key of a handler subscribed by SubscribeMethod
+type methodKey struct {
+	receiver interface{}
+	method   string
+}
+
+// SubscribeMethod subscribes the method of receiver named method to a topic, with the options
+// of SubscribeWith. A method value such as obj.Handle is a new closure on every evaluation, so
+// Unsubscribe cannot tell the receivers apart; a subscription by SubscribeMethod is tracked by
+// receiver and method name instead, for UnsubscribeMethod and UnsubscribeReceiver. The
+// receiver must be comparable, usually a pointer.
+func (bus *EventBus) SubscribeMethod(topic string, receiver interface{}, method string, opts ...SubscribeOption) error {
+	_, err := bus.subscribeMethod(topic, receiver, method, opts)
+	return err
+}
+
+func (bus *EventBus) subscribeMethod(topic string, receiver interface{}, method string, opts []SubscribeOption) (*Subscription, error) {
+	value := reflect.ValueOf(receiver)
+	if !value.IsValid() || !value.Type().Comparable() {
+		return nil, fmt.Errorf("receiver of type %T is not comparable", receiver)
+	}
+	fn := value.MethodByName(method)
+	if !fn.IsValid() {
+		return nil, fmt.Errorf("%T has no exported method %s: %w", receiver, method, ErrNotAFunction)
+	}
+	return bus.SubscribeHandle(topic, fn.Interface(), append(opts, WithKey(methodKey{receiver, method}))...)
+}
+
+// UnsubscribeMethod removes the handler subscribed by SubscribeMethod with receiver and method.
+// Returns ErrTopicNotFound if there are no callbacks subscribed to the topic,
+// *ErrHandlerNotFound if the method is not.
+func (bus *EventBus) UnsubscribeMethod(topic string, receiver interface{}, method string) error {
+	if t := reflect.TypeOf(receiver); t == nil || !t.Comparable() {
+		return &ErrHandlerNotFound{Topic: topic, Handler: fmt.Sprintf("%T.%s", receiver, method)}
+	}
+	err := bus.UnsubscribeKey(topic, methodKey{receiver, method})
+	if notFound, ok := err.(*ErrHandlerNotFound); ok {
+		notFound.Handler = fmt.Sprintf("%T.%s", receiver, method)
+	}
+	return err
+}
+
+// UnsubscribeReceiver removes the methods of receiver subscribed by SubscribeMethod, from every
+// topic, e.g. when the component it is goes away. Returns *ErrHandlerNotFound if there are none.
+func (bus *EventBus) UnsubscribeReceiver(receiver interface{}) error {
+	notFound := &ErrHandlerNotFound{Handler: fmt.Sprintf("methods of %T", receiver)}
+	if t := reflect.TypeOf(receiver); t == nil || !t.Comparable() {
+		return notFound
+	}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	removed := false
+	for topic, handlers := range bus.handlers {
+		for idx := len(handlers) - 1; idx >= 0; idx-- {
+			if key, ok := handlers[idx].key.(methodKey); ok && key.receiver == receiver {
+				bus.removeHandler(topic, idx)
+				removed = true
+			}
+		}
+	}
+	if !removed {
+		return notFound
+	}
+	return nil
+}
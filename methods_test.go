@@ -0,0 +1,62 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+type inventory struct {
+	reserved []string
+	released []string
+}
+
+func (inv *inventory) Reserve(id string) { inv.reserved = append(inv.reserved, id) }
+func (inv *inventory) Release(id string) { inv.released = append(inv.released, id) }
+
+func TestSubscribeMethod(t *testing.T) {
+	bus := New().(*EventBus)
+	first, second := &inventory{}, &inventory{}
+	for _, inv := range []*inventory{first, second} {
+		if err := bus.SubscribeMethod("order:placed", inv, "Reserve"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bus.UnsubscribeMethod("order:placed", second, "Reserve"); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("order:placed", "o-1")
+	if len(first.reserved) != 1 || len(second.reserved) != 0 {
+		t.Fatal(first.reserved, second.reserved)
+	}
+	var notFound *ErrHandlerNotFound
+	if err := bus.UnsubscribeMethod("order:placed", second, "Reserve"); !errors.As(err, &notFound) || notFound.Handler != "*EventBus.inventory.Reserve" {
+		t.Fatal(err)
+	}
+	if err := bus.SubscribeMethod("order:placed", first, "reserve"); !errors.Is(err, ErrNotAFunction) {
+		t.Fatal(err)
+	}
+	if err := bus.SubscribeMethod("order:placed", []int{}, "Len"); err == nil {
+		t.Fatal("subscribed a method of a non-comparable receiver")
+	}
+}
+
+func TestUnsubscribeReceiver(t *testing.T) {
+	bus := New().(*EventBus)
+	first, second := &inventory{}, &inventory{}
+	for _, inv := range []*inventory{first, second} {
+		bus.SubscribeMethod("order:placed", inv, "Reserve")
+		bus.SubscribeMethod("order:cancelled", inv, "Release", WithAsync(false))
+	}
+	if err := bus.UnsubscribeReceiver(first); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("order:placed", "o-1")
+	bus.Publish("order:cancelled", "o-1")
+	bus.WaitAsync()
+	if len(first.reserved)+len(first.released) != 0 || len(second.reserved) != 1 || len(second.released) != 1 {
+		t.Fatal(first, second)
+	}
+	if err := bus.UnsubscribeReceiver(first); err == nil {
+		t.Fatal("receiver unsubscribed twice")
+	}
+}
@@ -0,0 +1,157 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricKind - how a MetricRule records the events of its topic
+type MetricKind int
+
+const (
+	// MetricCounter - add the field to a counter, or 1 per event without a field
+	MetricCounter MetricKind = iota
+	// MetricHistogram - observe the field in a histogram
+	MetricHistogram
+)
+
+// MetricSink - metrics backend the values derived from events are recorded in, typically a
+// thin adapter over Prometheus vectors or expvar maps
+type MetricSink interface {
+	Add(name string, labels map[string]string, delta float64)
+	Observe(name string, labels map[string]string, value float64)
+}
+
+// MetricRule - metric derived from the events of a topic, see MapMetrics. Fields are dotted
+// paths into the arguments of the event, resolved in the first argument unless they start with
+// the index of another one, e.g. "Amount", "Customer.Tier" or "1.Duration". Struct fields,
+// map keys and pointers are followed, durations are recorded in seconds.
+type MetricRule struct {
+	Topic  string
+	Kind   MetricKind
+	Name   string
+	Field  string            // value recorded, empty to count events
+	Labels map[string]string // label name to the field giving its value
+}
+
+// MapMetrics subscribes a handler per rule recording the events of its topic in sink, so business
+// metrics come from configuration rather than a bespoke subscriber per metric. Events lacking a
+// field of their rule, or carrying a non numeric value, are not recorded. stop unsubscribes
+// every rule.
+func (bus *EventBus) MapMetrics(sink MetricSink, rules ...MetricRule) (stop func(), err error) {
+	for _, rule := range rules {
+		if rule.Topic == "" || rule.Name == "" {
+			return nil, errors.New("metric rule without topic or name")
+		}
+		if rule.Kind == MetricHistogram && rule.Field == "" {
+			return nil, fmt.Errorf("histogram %s observes no field", rule.Name)
+		}
+	}
+	handlers := make(map[*eventHandler]string, len(rules))
+	stop = func() {
+		for handler, topic := range handlers {
+			bus.removeHandlerPtr(topic, handler)
+		}
+	}
+	for _, rule := range rules {
+		rule := rule
+		handler, err := bus.subscribeHandler(rule.Topic, func(args ...interface{}) { rule.record(sink, args) }, false, false, false)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		handlers[handler] = rule.Topic
+	}
+	return stop, nil
+}
+
+// record records the event described by args in sink
+func (rule *MetricRule) record(sink MetricSink, args []interface{}) {
+	value := 1.0
+	if rule.Field != "" {
+		field, ok := fieldOf(args, rule.Field)
+		if !ok {
+			return
+		}
+		if value, ok = numberOf(field); !ok {
+			return
+		}
+	}
+	var labels map[string]string
+	if len(rule.Labels) > 0 {
+		labels = make(map[string]string, len(rule.Labels))
+		for label, path := range rule.Labels {
+			field, ok := fieldOf(args, path)
+			if !ok {
+				return
+			}
+			labels[label] = fmt.Sprint(field)
+		}
+	}
+	if rule.Kind == MetricHistogram {
+		sink.Observe(rule.Name, labels, value)
+	} else {
+		sink.Add(rule.Name, labels, value)
+	}
+}
+
+// fieldOf resolves a dotted path in the arguments of an event, the first one unless the path
+// starts with an argument index
+func fieldOf(args []interface{}, path string) (interface{}, bool) {
+	segments := strings.Split(path, ".")
+	arg := 0
+	if n, err := strconv.Atoi(segments[0]); err == nil {
+		arg, segments = n, segments[1:]
+	}
+	if arg < 0 || arg >= len(args) {
+		return nil, false
+	}
+	value := reflect.ValueOf(args[arg])
+	for _, segment := range segments {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil, false
+			}
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			value = value.FieldByName(segment)
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return nil, false
+			}
+			value = value.MapIndex(reflect.ValueOf(segment).Convert(value.Type().Key()))
+		default:
+			return nil, false
+		}
+		if !value.IsValid() || !value.CanInterface() {
+			return nil, false
+		}
+	}
+	if !value.IsValid() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+// numberOf converts a numeric field to float64, durations to seconds
+func numberOf(field interface{}) (float64, bool) {
+	if d, ok := field.(time.Duration); ok {
+		return d.Seconds(), true
+	}
+	value := reflect.ValueOf(field)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
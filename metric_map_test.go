@@ -0,0 +1,83 @@
+package EventBus
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSink - MetricSink keeping the recorded values by name and labels
+type fakeSink struct {
+	lock   sync.Mutex
+	values map[string][]float64
+}
+
+func (sink *fakeSink) key(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	return name + fmt.Sprint(labels)
+}
+
+func (sink *fakeSink) Add(name string, labels map[string]string, delta float64) {
+	sink.Observe(name, labels, delta)
+}
+
+func (sink *fakeSink) Observe(name string, labels map[string]string, value float64) {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+	if sink.values == nil {
+		sink.values = make(map[string][]float64)
+	}
+	key := sink.key(name, labels)
+	sink.values[key] = append(sink.values[key], value)
+}
+
+type metricOrder struct {
+	Amount   float64
+	Customer *struct{ Tier string }
+	Extra    map[string]interface{}
+}
+
+func TestMapMetrics(t *testing.T) {
+	bus := New().(*EventBus)
+	sink := &fakeSink{}
+	stop, err := bus.MapMetrics(sink,
+		MetricRule{Topic: "order:placed", Name: "orders_total", Labels: map[string]string{"tier": "Customer.Tier"}},
+		MetricRule{Topic: "order:placed", Name: "revenue", Field: "Amount"},
+		MetricRule{Topic: "order:placed", Kind: MetricHistogram, Name: "items", Field: "Extra.items"},
+		MetricRule{Topic: "order:shipped", Kind: MetricHistogram, Name: "shipping_seconds", Field: "1"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gold := &struct{ Tier string }{"gold"}
+	bus.Publish("order:placed", metricOrder{Amount: 10, Customer: gold, Extra: map[string]interface{}{"items": 3}})
+	bus.Publish("order:placed", metricOrder{Amount: 5})
+	bus.Publish("order:shipped", "o-1", 1500*time.Millisecond)
+	want := map[string][]float64{
+		"orders_totalmap[tier:gold]": {1},
+		"revenue":                    {10, 5},
+		"items":                      {3},
+		"shipping_seconds":           {1.5},
+	}
+	if fmt.Sprint(sink.values) != fmt.Sprint(want) {
+		t.Fatal(sink.values)
+	}
+
+	stop()
+	if bus.HasCallback("order:placed") || bus.HasCallback("order:shipped") {
+		t.Fatal("rules still subscribed")
+	}
+}
+
+func TestMapMetricsInvalidRule(t *testing.T) {
+	bus := New().(*EventBus)
+	if _, err := bus.MapMetrics(&fakeSink{}, MetricRule{Topic: "t", Kind: MetricHistogram, Name: "h"}); err == nil {
+		t.Fatal("histogram without field accepted")
+	}
+	if _, err := bus.MapMetrics(&fakeSink{}, MetricRule{Name: "c"}); err == nil {
+		t.Fatal("rule without topic accepted")
+	}
+}
@@ -0,0 +1,29 @@
+package EventBus
+
+// Middleware - wraps every publish: it may observe or replace the arguments, then calls next to
+// deliver the event, or returns without calling it to drop the event
+type Middleware func(topic string, args []interface{}, next func())
+
+// Use adds a middleware around every publish, e.g. for logging, tracing, metrics or enforcing
+// authorization, without wrapping each handler. Middlewares run on the publishing goroutine, in
+// the order they were added, the first one outermost.
+func (bus *EventBus) Use(mw Middleware) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	chain := bus.middlewares()
+	bus.middleware.Store(append(chain[:len(chain):len(chain)], mw))
+}
+
+func (bus *EventBus) middlewares() []Middleware {
+	chain, _ := bus.middleware.Load().([]Middleware)
+	return chain
+}
+
+// intercept runs the chain of middlewares, then deliver unless a middleware dropped the event
+func intercept(chain []Middleware, topic string, args []interface{}, deliver func()) {
+	if len(chain) == 0 {
+		deliver()
+		return
+	}
+	chain[0](topic, args, func() { intercept(chain[1:], topic, args, deliver) })
+}
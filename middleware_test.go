@@ -0,0 +1,40 @@
+package EventBus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUse(t *testing.T) {
+	bus := New().(*EventBus)
+	var calls []string
+	bus.Use(func(topic string, args []interface{}, next func()) {
+		calls = append(calls, "log:"+topic)
+		next()
+		calls = append(calls, "logged:"+topic)
+	})
+	bus.Use(func(topic string, args []interface{}, next func()) {
+		if strings.HasPrefix(topic, "admin:") {
+			return
+		}
+		if len(args) > 0 {
+			args[0] = strings.ToUpper(args[0].(string))
+		}
+		next()
+	})
+	var got []string
+	bus.Subscribe("greet", func(name string) { got = append(got, name) })
+	bus.Subscribe("admin:reset", func() { t.Fatal("dropped event delivered") })
+
+	names := []interface{}{"ada"}
+	if delivered := bus.PublishEx("greet", names...); delivered != 1 {
+		t.Fatal(delivered)
+	}
+	bus.Publish("admin:reset")
+	if len(got) != 1 || got[0] != "ADA" || names[0] != "ada" {
+		t.Fatal(got, names)
+	}
+	if strings.Join(calls, " ") != "log:greet logged:greet log:admin:reset logged:admin:reset" {
+		t.Fatal(calls)
+	}
+}
@@ -0,0 +1,189 @@
+package EventBus
+
+import (
+	"log"
+	"os"
+	"reflect"
+)
+
+// MutationDetectionEnv - environment variable turning WithMutationDetection on for every bus
+// built with the race detector, e.g. EVENTBUS_DETECT_MUTATIONS=1 go test -race ./...
+const MutationDetectionEnv = "EVENTBUS_DETECT_MUTATIONS"
+
+// detectMutations reports whether new buses log the handlers mutating their arguments
+func detectMutations() bool {
+	return raceEnabled && os.Getenv(MutationDetectionEnv) != ""
+}
+
+// MutationReport - a handler changed a published argument shared with the other handlers
+type MutationReport struct {
+	Topic   string
+	Handler string
+	Arg     int // position of the argument among the published ones
+}
+
+// mutationCheck - snapshots of the published arguments a handler could change in place
+type mutationCheck struct {
+	snapshots map[int]interface{}
+}
+
+// WithMutationDetection snapshots the published arguments before every handler and compares
+// them once it returned, reporting the handlers which changed them in place. It is a debug mode
+// to find the source of data races between handlers, which WithDeepCopy would hide; without
+// report the mutations are logged. Changes hidden in unexported fields go unnoticed, and
+// arguments holding synchronization primitives, e.g. a *sync.WaitGroup, are meant to be
+// changed by handlers and are not checked. Buses built with the race detector (go test -race)
+// log mutations when the MutationDetectionEnv environment variable is set.
+func WithMutationDetection(report func(MutationReport)) Option {
+	if report == nil {
+		report = func(mutation MutationReport) {
+			log.Printf("EventBus: handler %s mutated argument %d of topic %s", mutation.Handler, mutation.Arg, mutation.Topic)
+		}
+	}
+	return func(bus *EventBus) {
+		bus.mutations = report
+	}
+}
+
+func newMutationCheck(args []interface{}) *mutationCheck {
+	check := &mutationCheck{make(map[int]interface{})}
+	for i, arg := range args {
+		if typ := reflect.TypeOf(arg); mutable(typ) && !synchronizing(typ, make(map[reflect.Type]bool)) {
+			check.snapshots[i] = DeepCopy(arg)
+		}
+	}
+	return check
+}
+
+// mutable reports whether a handler can change a value of the type in place,
+// functions and channels cannot be compared and are left out
+func mutable(typ reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	case reflect.Array:
+		return mutable(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if mutable(typ.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// synchronizing reports whether a value of the type holds or points to a type of the sync or
+// sync/atomic packages, which handlers share on purpose and which must not be copied
+func synchronizing(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[typ] {
+		return false
+	}
+	visited[typ] = true
+	switch typ.PkgPath() {
+	case "sync", "sync/atomic":
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return synchronizing(typ.Elem(), visited)
+	case reflect.Map:
+		return synchronizing(typ.Key(), visited) || synchronizing(typ.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if synchronizing(typ.Field(i).Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (check *mutationCheck) verify(report func(MutationReport), handler *eventHandler, env *envelope) {
+	for i, snapshot := range check.snapshots {
+		if !sameValue(reflect.ValueOf(snapshot), reflect.ValueOf(env.args[i]), make(map[visit]bool)) {
+			report(MutationReport{env.meta.Topic, handler.name(), i})
+		}
+	}
+}
+
+// visit - pair of references being compared, to stop on cycles
+type visit struct {
+	typ         reflect.Type
+	left, right uintptr
+}
+
+// sameValue compares a snapshot with the current value like reflect.DeepEqual, except that
+// functions and channels, which cannot be snapshotted, always compare equal
+func sameValue(left, right reflect.Value, visited map[visit]bool) bool {
+	if !left.IsValid() || !right.IsValid() {
+		return left.IsValid() == right.IsValid()
+	}
+	if left.Type() != right.Type() {
+		return false
+	}
+	switch left.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if left.IsNil() || right.IsNil() {
+			return left.IsNil() == right.IsNil()
+		}
+		key := visit{left.Type(), left.Pointer(), right.Pointer()}
+		if visited[key] {
+			return true
+		}
+		visited[key] = true
+	}
+	switch left.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if left.Kind() == reflect.Interface && (left.IsNil() || right.IsNil()) {
+			return left.IsNil() == right.IsNil()
+		}
+		return sameValue(left.Elem(), right.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		if left.Len() != right.Len() {
+			return false
+		}
+		for i := 0; i < left.Len(); i++ {
+			if !sameValue(left.Index(i), right.Index(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if left.Len() != right.Len() {
+			return false
+		}
+		for _, key := range left.MapKeys() {
+			value := right.MapIndex(key)
+			if !value.IsValid() || !sameValue(left.MapIndex(key), value, visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < left.NumField(); i++ {
+			if !sameValue(left.Field(i), right.Field(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return left.Bool() == right.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return left.Int() == right.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return left.Uint() == right.Uint()
+	case reflect.Float32, reflect.Float64:
+		return left.Float() == right.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return left.Complex() == right.Complex()
+	case reflect.String:
+		return left.String() == right.String()
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package EventBus
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func mutatingHandler(n int, items []int, tags map[string]int, fn func()) {
+	items[0] = 1
+}
+
+func TestMutationDetection(t *testing.T) {
+	var reports []MutationReport
+	bus := NewWithOptions(WithMutationDetection(func(report MutationReport) {
+		reports = append(reports, report)
+	})).(*EventBus)
+	bus.Subscribe("topic", func(n int, items []int, tags map[string]int, fn func()) { n++ })
+	bus.Subscribe("topic", mutatingHandler)
+	bus.Subscribe("topic", func(n int, items []int, tags map[string]int, fn func()) { tags["a"] = 1 })
+	bus.Subscribe("topic", func(n int, items []int, tags map[string]int, fn func()) { fn() })
+	bus.Publish("topic", 1, []int{0}, map[string]int{}, func() {})
+	if len(reports) != 2 || reports[0].Arg != 1 || reports[0].Handler != runtime.FuncForPC(reflect.ValueOf(mutatingHandler).Pointer()).Name() ||
+		reports[1].Arg != 2 || reports[1].Topic != "topic" {
+		t.Fatal(reports)
+	}
+}
+
+type mutationRequest struct {
+	ID   int
+	Done func()
+	Next *mutationRequest
+}
+
+func TestMutationDetectionCyclesAndFuncs(t *testing.T) {
+	var reports []MutationReport
+	bus := NewWithOptions(WithMutationDetection(func(report MutationReport) {
+		reports = append(reports, report)
+	})).(*EventBus)
+	bus.Subscribe("topic", func(request *mutationRequest) { request.Done() })
+	request := &mutationRequest{ID: 1, Done: func() {}}
+	request.Next = request
+	bus.Publish("topic", request)
+	if len(reports) != 0 {
+		t.Fatal(reports)
+	}
+	bus.Subscribe("topic", func(request *mutationRequest) { request.Next.ID = 2 })
+	bus.Publish("topic", request)
+	if len(reports) != 1 || reports[0].Arg != 0 {
+		t.Fatal(reports)
+	}
+}
+
+type mutationCounter struct {
+	lock  sync.Mutex
+	count int
+}
+
+func TestMutationDetectionSkipsSync(t *testing.T) {
+	var reports []MutationReport
+	bus := NewWithOptions(WithMutationDetection(func(report MutationReport) {
+		reports = append(reports, report)
+	})).(*EventBus)
+	bus.Subscribe("topic", func(wg *sync.WaitGroup, counter *mutationCounter) {
+		wg.Done()
+		counter.lock.Lock()
+		counter.count++
+		counter.lock.Unlock()
+	})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	bus.Publish("topic", &wg, &mutationCounter{})
+	if len(reports) != 0 {
+		t.Fatal(reports)
+	}
+}
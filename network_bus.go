@@ -1,7 +1,6 @@
 package EventBus
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -61,13 +60,17 @@ func (networkBus *NetworkBus) Start() error {
 		service.wg.Add(1)
 		go http.Serve(l, nil)
 	} else {
-		err = errors.New("Server bus already started")
+		err = fmt.Errorf("Server bus %w", ErrAlreadyStarted)
 	}
 	return err
 }
 
-// Stop - signal for the service to stop serving
+// Stop - signal for the service to stop serving, and stop watching the servers subscribed at
 func (networkBus *NetworkBus) Stop() {
+	client := networkBus.Client
+	client.lock.Lock()
+	client.stopWatching()
+	client.lock.Unlock()
 	service := networkBus.service
 	if service.started {
 		service.wg.Done()
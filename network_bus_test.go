@@ -2,6 +2,7 @@ package EventBus
 
 import (
 	"testing"
+	"time"
 )
 
 func TestNewServer(t *testing.T) {
@@ -47,7 +48,7 @@ func TestPushEvent(t *testing.T) {
 	eventArgs := make([]interface{}, 1)
 	eventArgs[0] = 10
 
-	clientArg := &ClientArg{eventArgs, "topic"}
+	clientArg := &ClientArg{Args: eventArgs, Topic: "topic"}
 	reply := new(bool)
 
 	fn := func(a int) {
@@ -110,3 +111,34 @@ func TestNetworkBus(t *testing.T) {
 	networkBusA.Stop()
 	networkBusB.Stop()
 }
+
+func TestOutboxPriorityLanes(t *testing.T) {
+	started, blocked := make(chan struct{}), make(chan struct{})
+	sent := make(chan string, 4)
+	box := &outbox{send: func(event *remoteEvent) error {
+		if event.Arg.Topic == "first" {
+			close(started)
+			<-blocked
+		}
+		sent <- event.Arg.Topic
+		return nil
+	}}
+	box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: "first"}, time.Now()})
+	<-started
+	box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: "bulk-1"}, time.Now()})
+	box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: "bulk-2"}, time.Now()})
+	box.push(PriorityHigh, &remoteEvent{PublishService, &ClientArg{Topic: "cancel"}, time.Now()})
+	close(blocked)
+	order := []string{<-sent, <-sent, <-sent, <-sent}
+	if order[0] != "first" || order[1] != "cancel" || order[2] != "bulk-1" || order[3] != "bulk-2" {
+		t.Fatal(order)
+	}
+}
+
+func TestTopicPriority(t *testing.T) {
+	server := NewServer(":2040", "/_server_bus_c", New())
+	server.SetTopicPriority("cancel", PriorityHigh)
+	if server.TopicPriority("cancel") != PriorityHigh || server.TopicPriority("bulk") != PriorityNormal {
+		t.Fail()
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !race
+// +build !race
+
+package EventBus
+
+// raceEnabled - built with the race detector, buses may report handlers mutating their
+// arguments, see MutationDetectionEnv
+const raceEnabled = false
@@ -0,0 +1,127 @@
+package EventBus
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Option - setting applied to a bus created by NewWithOptions
+type Option func(bus *EventBus)
+
+// WithValidation makes Publish check the arguments against the signature of every handler
+// before delivering to any of them, panicking with a descriptive error on a mismatch instead
+// of failing inside reflection halfway through the fan-out.
+func WithValidation() Option {
+	return func(bus *EventBus) {
+		bus.validate = true
+	}
+}
+
+// WithInlineAsync runs async handlers on the publishing goroutine, in subscription order,
+// right after the synchronous ones and once the bus lock is released. Delivery becomes
+// deterministic, which makes tests reproducible.
+func WithInlineAsync() Option {
+	return func(bus *EventBus) {
+		bus.inlineAsync = true
+	}
+}
+
+// WithAsyncWorkers runs async handlers on a fixed pool of n goroutines instead of starting
+// a goroutine per delivery. When the pool is saturated a goroutine is started anyway, so
+// publishing never waits for the pool. The pool is stopped by Close.
+func WithAsyncWorkers(n int) Option {
+	return func(bus *EventBus) {
+		if n > 0 {
+			bus.workers.Store(newWorkerPool(n, n))
+		}
+	}
+}
+
+// WithIDGenerator identifies every published event with an ID from generate, available to
+// handlers through EventMeta. The same generator backs NewID.
+func WithIDGenerator(generate IDGenerator) Option {
+	return func(bus *EventBus) {
+		bus.ids = generate
+	}
+}
+
+// NewWithOptions returns new EventBus with empty handlers and the given options applied.
+func NewWithOptions(opts ...Option) Bus {
+	bus := New().(*EventBus)
+	for _, opt := range opts {
+		opt(bus)
+	}
+	return bus
+}
+
+// NewStrict returns a bus validating published arguments against handler signatures.
+func NewStrict() Bus {
+	return NewWithOptions(WithValidation())
+}
+
+// NewHighThroughput returns a bus running async handlers on a pool sized to GOMAXPROCS.
+func NewHighThroughput() Bus {
+	return NewWithOptions(WithAsyncWorkers(runtime.GOMAXPROCS(0)))
+}
+
+// NewDeterministicTest returns a bus for tests: async handlers run inline, in subscription
+// order, and published arguments are validated.
+func NewDeterministicTest() Bus {
+	return NewWithOptions(WithInlineAsync(), WithValidation())
+}
+
+// workerPool - fixed set of goroutines running async deliveries
+type workerPool struct {
+	lock       sync.RWMutex // held for reading while handing a task over, so stop never closes tasks under a sender
+	stopped    bool
+	tasks      chan func()
+	size       int
+	overflowed uint64 // tasks run on a new goroutine, the pool being saturated
+}
+
+func newWorkerPool(n, queue int) *workerPool {
+	pool := &workerPool{tasks: make(chan func(), queue), size: n}
+	for i := 0; i < n; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+// run hands the task to an idle worker, a nil, stopped or saturated pool runs it on a new goroutine
+func (pool *workerPool) run(task func()) {
+	if pool == nil {
+		go task()
+		return
+	}
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+	if pool.stopped {
+		go task()
+		return
+	}
+	select {
+	case pool.tasks <- task:
+	default:
+		atomic.AddUint64(&pool.overflowed, 1)
+		go task()
+	}
+}
+
+func (pool *workerPool) work() {
+	for task := range pool.tasks {
+		task()
+	}
+}
+
+func (pool *workerPool) stop() {
+	if pool == nil {
+		return
+	}
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	if !pool.stopped {
+		pool.stopped = true
+		close(pool.tasks)
+	}
+}
@@ -0,0 +1,71 @@
+package EventBus
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	bus := NewStrict()
+	called := false
+	bus.Subscribe("topic", func(a int) { called = true })
+	bus.Subscribe("topic", func(s string) {})
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(error).Error(), "string is not assignable to int") && !strings.Contains(r.(error).Error(), "int is not assignable to string") {
+			t.Fatal(r)
+		}
+		if called {
+			t.Fail()
+		}
+	}()
+	bus.Publish("topic", 1)
+}
+
+func TestValidationAccepts(t *testing.T) {
+	bus := NewStrict()
+	count := 0
+	bus.Subscribe("topic", func(a int, err error, rest ...interface{}) { count++ })
+	bus.Publish("topic", 1, nil)
+	bus.Publish("topic", 1, nil, "a", 2)
+	if count != 2 {
+		t.Fail()
+	}
+}
+
+func TestDeterministicTest(t *testing.T) {
+	bus := NewDeterministicTest()
+	var order []int
+	bus.SubscribeAsync("topic", func(a int) {
+		order = append(order, a)
+		if a == 1 {
+			bus.Publish("topic", 2)
+		}
+	}, true)
+	bus.Subscribe("topic", func(a int) { order = append(order, -a) })
+	bus.Publish("topic", 1)
+	// sync handlers first, async ones right after on the same goroutine
+	if len(order) != 4 || order[0] != -1 || order[1] != 1 || order[2] != -2 || order[3] != 2 {
+		t.Fatal(order)
+	}
+}
+
+func TestHighThroughput(t *testing.T) {
+	bus := NewHighThroughput()
+	var count int32
+	bus.SubscribeAsync("topic", func() { atomic.AddInt32(&count, 1) }, false)
+	bus.SubscribeAsync("topic", func() { atomic.AddInt32(&count, 1) }, true)
+	for i := 0; i < 100; i++ {
+		bus.Publish("topic")
+	}
+	bus.(*EventBus).Close()
+	if count != 200 {
+		t.Fail()
+	}
+	bus.Publish("topic")
+	bus.WaitAsync()
+	if count != 202 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,85 @@
+package EventBus
+
+import (
+	"sync"
+	"time"
+)
+
+// WithOrderedAsync queues the deliveries of every async handler before Publish returns and runs
+// them one at a time, in publish order, so a subscriber never observes two sequential publishes
+// out of order. Async handlers no longer run concurrently with themselves, as if transactional;
+// different handlers still run concurrently.
+func WithOrderedAsync() Option {
+	return func(bus *EventBus) {
+		bus.ordered = true
+	}
+}
+
+// handlerQueue - deliveries waiting for an async handler, drained by a single goroutine at a time
+type handlerQueue struct {
+	lock     sync.Mutex
+	tasks    []func()
+	enqueued []time.Time // when the tasks were queued
+	highest  int         // most tasks waiting at once, since the last ResetStats
+	running  bool
+	pending  sync.WaitGroup // deliveries queued or running
+}
+
+// push queues the delivery, starting a drain on the worker pool unless one is running already
+func (queue *handlerQueue) push(workers *workerPool, task func()) {
+	queue.pending.Add(1)
+	queue.lock.Lock()
+	queue.tasks = append(queue.tasks, task)
+	queue.enqueued = append(queue.enqueued, time.Now())
+	if len(queue.tasks) > queue.highest {
+		queue.highest = len(queue.tasks)
+	}
+	if queue.running {
+		queue.lock.Unlock()
+		return
+	}
+	queue.running = true
+	queue.lock.Unlock()
+	workers.run(queue.drain)
+}
+
+func (queue *handlerQueue) drain() {
+	for {
+		queue.lock.Lock()
+		if len(queue.tasks) == 0 {
+			queue.running = false
+			queue.lock.Unlock()
+			return
+		}
+		task := queue.tasks[0]
+		queue.tasks[0] = nil
+		queue.tasks = queue.tasks[1:]
+		queue.enqueued = queue.enqueued[1:]
+		queue.lock.Unlock()
+		task()
+		queue.pending.Done()
+	}
+}
+
+// stats returns the backlog of the queue, the deliveries waiting and not running yet
+func (queue *handlerQueue) stats() QueueStats {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	stats := QueueStats{Depth: len(queue.tasks), HighWater: queue.highest}
+	if len(queue.enqueued) > 0 {
+		stats.Lag = time.Since(queue.enqueued[0])
+	}
+	return stats
+}
+
+// resetHighWater restarts the high-water mark from the current backlog
+func (queue *handlerQueue) resetHighWater() {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	queue.highest = len(queue.tasks)
+}
+
+// wait returns once the deliveries queued are done, no more may be pushed meanwhile
+func (queue *handlerQueue) wait() {
+	queue.pending.Wait()
+}
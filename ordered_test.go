@@ -0,0 +1,33 @@
+package EventBus
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestOrderedAsync(t *testing.T) {
+	bus := NewWithOptions(WithOrderedAsync(), WithAsyncWorkers(4)).(*EventBus)
+	var lock sync.Mutex
+	var received []int
+	bus.SubscribeAsync("topic", func(n int) {
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, n)
+	}, false)
+	for i := 0; i < 1000; i++ {
+		bus.Publish("topic", i)
+	}
+	bus.WaitAsync()
+	if len(received) != 1000 {
+		t.Fatal(len(received))
+	}
+	for i, n := range received {
+		if n != i {
+			t.Fatalf("event %d received at position %d", n, i)
+		}
+	}
+	if bus.Barrier("topic") != nil {
+		t.Fail()
+	}
+	bus.Close()
+}
@@ -0,0 +1,201 @@
+package EventBus
+
+import (
+	"sync"
+	"time"
+)
+
+// BridgeStatusTopic - topic a Server publishes a BridgeStatus to whenever a client becomes
+// unreachable or reachable again
+const BridgeStatusTopic = "bridge:status"
+
+// BridgeStatus - state of the connection from a Server to one of its clients
+type BridgeStatus struct {
+	Client     string
+	Connected  bool          // whether the last event was received by the client
+	Reconnects int           // how many times the client came back after being unreachable
+	Queued     int           // events waiting in memory
+	Spooled    int           // events waiting on disk
+	SpoolSize  int64         // size of the spool file in bytes
+	Dropped    int           // events lost, the client being unreachable without room to spool them
+	Lag        time.Duration // age of the oldest event still waiting
+	LastDelay  time.Duration // time the last received event waited before being sent
+}
+
+// outbox - events waiting to be sent to a remote client, one lane per priority. With a spool,
+// events the client could not receive are kept on disk and everything published meanwhile
+// queues behind them until they are forwarded.
+type outbox struct {
+	lock         sync.Mutex
+	client       string
+	lanes        [2][]*remoteEvent
+	sending      bool
+	retrying     bool
+	send         func(event *remoteEvent) error
+	spool        *spool
+	retry        time.Duration
+	onChange     func(status BridgeStatus)
+	disconnected bool
+	reconnects   int
+	dropped      int
+	lastDelay    time.Duration
+}
+
+// push queues the event, starting a sender unless one is already draining the outbox
+func (box *outbox) push(priority Priority, event *remoteEvent) {
+	lane := 1
+	if priority >= PriorityHigh {
+		lane = 0
+	}
+	box.lock.Lock()
+	if box.retrying {
+		box.store(event)
+		box.lock.Unlock()
+		return
+	}
+	box.lanes[lane] = append(box.lanes[lane], event)
+	if box.sending {
+		box.lock.Unlock()
+		return
+	}
+	box.sending = true
+	box.lock.Unlock()
+	go box.drain()
+}
+
+// resume starts forwarding again after the client was unreachable
+func (box *outbox) resume() {
+	box.lock.Lock()
+	box.retrying = false
+	if box.sending {
+		box.lock.Unlock()
+		return
+	}
+	box.sending = true
+	box.lock.Unlock()
+	go box.drain()
+}
+
+func (box *outbox) drain() {
+	for {
+		if !box.flush() {
+			return
+		}
+		box.lock.Lock()
+		var event *remoteEvent
+		for lane := range box.lanes {
+			if len(box.lanes[lane]) > 0 {
+				event = box.lanes[lane][0]
+				box.lanes[lane] = box.lanes[lane][1:]
+				break
+			}
+		}
+		if event == nil {
+			box.sending = false
+			box.lock.Unlock()
+			return
+		}
+		box.lock.Unlock()
+		if !box.deliver(event) {
+			box.lock.Lock()
+			box.store(event)
+			box.lock.Unlock()
+		}
+	}
+}
+
+// flush forwards the spooled events, returning false when the client is still unreachable:
+// the remaining and the queued events are then spooled and a retry is scheduled
+func (box *outbox) flush() bool {
+	box.lock.Lock()
+	if box.spool == nil || box.spool.count == 0 {
+		box.lock.Unlock()
+		return true
+	}
+	events, _ := box.spool.load()
+	box.lock.Unlock()
+	for i, event := range events {
+		if box.deliver(event) {
+			continue
+		}
+		box.lock.Lock()
+		box.spool.reset(nil)
+		box.store(events[i:]...)
+		for lane := range box.lanes {
+			box.store(box.lanes[lane]...)
+			box.lanes[lane] = nil
+		}
+		box.retrying, box.sending = true, false
+		box.lock.Unlock()
+		time.AfterFunc(box.retry, box.resume)
+		return false
+	}
+	box.lock.Lock()
+	box.spool.reset(nil)
+	box.lock.Unlock()
+	return true
+}
+
+// deliver sends the event, reporting whether the client received it
+func (box *outbox) deliver(event *remoteEvent) bool {
+	err := box.send(event)
+	box.lock.Lock()
+	changed := box.disconnected != (err != nil)
+	if err == nil {
+		box.lastDelay = time.Since(event.Queued)
+		if box.disconnected {
+			box.reconnects++
+		}
+	}
+	box.disconnected = err != nil
+	var status BridgeStatus
+	if changed {
+		status = box.statusLocked()
+	}
+	box.lock.Unlock()
+	if changed && box.onChange != nil {
+		box.onChange(status)
+	}
+	return err == nil
+}
+
+// store spools events the client could not receive, counting those which do not fit
+func (box *outbox) store(events ...*remoteEvent) {
+	if box.spool == nil {
+		box.dropped += len(events)
+		return
+	}
+	count := box.spool.count
+	box.spool.append(events...)
+	box.dropped += len(events) - (box.spool.count - count)
+}
+
+func (box *outbox) status() BridgeStatus {
+	box.lock.Lock()
+	defer box.lock.Unlock()
+	return box.statusLocked()
+}
+
+func (box *outbox) statusLocked() BridgeStatus {
+	status := BridgeStatus{
+		Client:     box.client,
+		Connected:  !box.disconnected,
+		Reconnects: box.reconnects,
+		Queued:     len(box.lanes[0]) + len(box.lanes[1]),
+		Dropped:    box.dropped,
+		LastDelay:  box.lastDelay,
+	}
+	var oldest time.Time
+	if box.spool != nil && box.spool.count > 0 {
+		status.Spooled, status.SpoolSize, oldest = box.spool.count, box.spool.size, box.spool.oldest
+	}
+	for _, lane := range box.lanes {
+		if len(lane) > 0 && (oldest.IsZero() || lane[0].Queued.Before(oldest)) {
+			oldest = lane[0].Queued
+		}
+	}
+	if !oldest.IsZero() {
+		status.Lag = time.Since(oldest)
+	}
+	return status
+}
@@ -0,0 +1,58 @@
+package EventBus
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBridgeStatus(t *testing.T) {
+	var lock sync.Mutex
+	reachable := false
+	changes := make(chan BridgeStatus, 4)
+	box := &outbox{client: "client", onChange: func(status BridgeStatus) { changes <- status }, send: func(event *remoteEvent) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if !reachable {
+			return errors.New("unreachable")
+		}
+		return nil
+	}}
+	box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: "topic"}, time.Now()})
+	if status := <-changes; status.Connected || status.Client != "client" {
+		t.Fatal(status)
+	}
+	time.Sleep(time.Millisecond)
+	if status := box.status(); status.Dropped != 1 || status.Queued != 0 || status.Lag != 0 {
+		t.Fatal(status)
+	}
+
+	lock.Lock()
+	reachable = true
+	lock.Unlock()
+	box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: "topic"}, time.Now().Add(-time.Second)})
+	if status := <-changes; !status.Connected || status.Reconnects != 1 || status.LastDelay < time.Second {
+		t.Fatal(status)
+	}
+}
+
+func TestServerBridgeStatus(t *testing.T) {
+	server := NewServer(":2045", "/_server_bus_d", New())
+	published := make(chan BridgeStatus, 1)
+	server.EventBus().Subscribe(BridgeStatusTopic, func(status BridgeStatus) { published <- status })
+	reply := new(bool)
+	server.service.Register(&SubscribeArg{"localhost:1", "/_unreachable_", PublishService, Subscribe, "topic"}, reply)
+	server.EventBus().Publish("topic", 1)
+	select {
+	case status := <-published:
+		if status.Connected || status.Client != "localhost:1/_unreachable_" {
+			t.Fatal(status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bridge status was not published")
+	}
+	if statuses := server.BridgeStatus(); len(statuses) != 1 || statuses[0].Connected {
+		t.Fatal(statuses)
+	}
+}
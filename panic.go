@@ -0,0 +1,31 @@
+package EventBus
+
+// PanicHandler - receives the panics of handlers recovered by the bus, with the topic of the
+// event and the handler function
+type PanicHandler func(topic string, handler interface{}, recovered interface{})
+
+// SetPanicHandler makes the bus recover the panics of every handler and pass them to handle,
+// once the bus lock is released, so it may publish. The delivery goes on with the remaining
+// handlers whatever their failure policy, and the panic is also published to HandlerFailedTopic
+// when the handler has a policy. A nil handle lets panics reach the publisher again. Handlers
+// called by helpers such as SubscribeMerged, SubscribeShadow or SubscribeSplit are not covered.
+func (bus *EventBus) SetPanicHandler(handle func(topic string, handler interface{}, recovered interface{})) {
+	bus.panics.Store(PanicHandler(handle))
+}
+
+// doPublishRecovering is doPublish recovering a panic of the handler when the bus has a PanicHandler
+func (bus *EventBus) doPublishRecovering(handler *eventHandler, ticket *traceTicket, env *envelope) (failure *HandlerFailure) {
+	if handle, _ := bus.panics.Load().(PanicHandler); handle == nil {
+		return bus.doPublish(handler, ticket, env)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			failure = handler.panicked(env, r)
+			failure.quiet = bus.failurePolicy(handler) == 0
+			if !handler.async {
+				collectError(env, failure.Err)
+			}
+		}
+	}()
+	return bus.doPublish(handler, ticket, env)
+}
@@ -0,0 +1,55 @@
+package EventBus
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSetPanicHandler(t *testing.T) {
+	bus := NewWithOptions(WithFailurePolicy(FailFast)).(*EventBus)
+	var lock sync.Mutex
+	var panics []string
+	bus.SetPanicHandler(func(topic string, handler interface{}, recovered interface{}) {
+		if _, ok := handler.(func(int)); !ok {
+			t.Errorf("handler %T", handler)
+		}
+		bus.Publish("panics:seen", topic) // the bus is unlocked
+		lock.Lock()
+		panics = append(panics, fmt.Sprint(topic, ":", recovered))
+		lock.Unlock()
+	})
+	var failures []HandlerFailure
+	bus.Subscribe(HandlerFailedTopic, func(failure HandlerFailure) { failures = append(failures, failure) })
+	reached := 0
+	bus.Subscribe("order", func(n int) { panic("sync") })
+	bus.Subscribe("order", func(n int) { reached++ })
+	bus.SubscribeAsync("order", func(n int) { panic("async") }, false)
+	bus.Publish("order", 1)
+	bus.WaitAsync()
+	if reached != 1 || len(panics) != 2 || panics[0] != "order:sync" || len(failures) != 2 || failures[0].Recovered != "sync" {
+		t.Fatal(reached, panics, failures)
+	}
+
+	bus.SetPanicHandler(nil)
+	defer func() {
+		if recover() != "sync" {
+			t.Fatal("panic recovered without a panic handler")
+		}
+	}()
+	bus.Publish("order", 2)
+}
+
+func TestSetPanicHandlerWithoutPolicy(t *testing.T) {
+	bus := New().(*EventBus)
+	recovered := make(chan interface{}, 1)
+	bus.SetPanicHandler(func(topic string, handler interface{}, r interface{}) { recovered <- r })
+	bus.Subscribe(HandlerFailedTopic, func(HandlerFailure) { t.Fatal("failure published without a policy") })
+	bus.Subscribe("topic", func() { panic("boom") })
+	if errs := bus.PublishWithResult("topic"); len(errs) != 1 {
+		t.Fatal(errs)
+	}
+	if r := <-recovered; r != "boom" {
+		t.Fatal(r)
+	}
+}
@@ -0,0 +1,281 @@
+package EventBus
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// ClientPingService - Client service method answering the health checks of a Federation
+	ClientPingService = "ClientService.Ping"
+)
+
+// PartitionTopic - topic a Federation publishes a PartitionChange to, on the local bus of its
+// PartitionPolicy, whenever a node becomes unreachable or reachable again
+const PartitionTopic = "cluster:partition"
+
+// PartitionSeqHeader - header numbering, per node, the events of a Federation kept during a
+// partition, so their owner can reconcile them with its own state once they are sent to it
+const PartitionSeqHeader = "Partition-Seq"
+
+// PartitionMode - what a Federation does with the events owned by an unreachable node
+type PartitionMode int
+
+const (
+	// ContinueLocally - the events are published to the local bus, numbered, and sent to their
+	// owner in order once the partition heals
+	ContinueLocally PartitionMode = iota
+	// PauseTopics - the events are held, and sent to their owner in order once the partition heals
+	PauseTopics
+)
+
+// PartitionPolicy - how a Federation detects that nodes are unreachable and what it does with
+// the events of the cluster-scoped topics they own meanwhile. Events of other topics are sent
+// to their owner as usual.
+type PartitionPolicy struct {
+	Mode       PartitionMode
+	Local      BusPublisher  // bus of this process, receiving PartitionTopic and the events kept locally
+	Topics     []string      // cluster-scoped topics, every topic when empty
+	Heartbeat  time.Duration // interval between checks of the nodes, a second when zero
+	MaxPending int           // events kept per unreachable node, the oldest dropped beyond, zero for no bound
+}
+
+// PartitionChange - change of the reachability of a node of a Federation
+type PartitionChange struct {
+	Node        string
+	Partitioned bool  // whether the node is unreachable
+	Err         error // why the node is unreachable
+	Pending     int   // events sent to the node as the partition healed
+	Dropped     int   // events lost during the partition, beyond MaxPending
+}
+
+// pinger - shard reporting whether it is reachable, e.g. RemoteShard
+type pinger interface {
+	Ping() error
+}
+
+// partitionState - events kept for an unreachable node
+type partitionState struct {
+	pending []*pendingEvent // from head on, oldest first
+	head    int
+	seq     uint64
+	dropped int
+	healing bool // the node is reachable again, the events are being sent to it
+}
+
+type pendingEvent struct {
+	topic   string
+	headers Headers
+	args    []interface{}
+}
+
+// SetPartitionPolicy - set how unreachable nodes are detected and handled, and start checking
+// the nodes whose shard reports its reachability, e.g. RemoteShard. Local buses are never
+// partitioned.
+func (federation *Federation) SetPartitionPolicy(policy PartitionPolicy) {
+	if policy.Heartbeat <= 0 {
+		policy.Heartbeat = time.Second
+	}
+	federation.lock.Lock()
+	defer federation.lock.Unlock()
+	federation.policy = &policy
+	federation.scoped = make(map[string]bool, len(policy.Topics))
+	for _, topic := range policy.Topics {
+		federation.scoped[topic] = true
+	}
+	if federation.partitioned == nil {
+		federation.partitioned = make(map[string]*partitionState)
+	}
+	if federation.done != nil {
+		close(federation.done)
+	}
+	federation.done = make(chan struct{})
+	go federation.watch(policy.Heartbeat, federation.done)
+}
+
+// Stop stops checking the nodes, events owned by unreachable nodes are kept until they are
+// checked again
+func (federation *Federation) Stop() {
+	federation.lock.Lock()
+	defer federation.lock.Unlock()
+	if federation.done != nil {
+		close(federation.done)
+		federation.done = nil
+	}
+}
+
+// Partitioned returns the names of the unreachable nodes
+func (federation *Federation) Partitioned() []string {
+	federation.lock.RLock()
+	defer federation.lock.RUnlock()
+	var nodes []string
+	for _, member := range federation.members {
+		if state := federation.partitioned[member.node]; state != nil && !state.healing {
+			nodes = append(nodes, member.node)
+		}
+	}
+	return nodes
+}
+
+// keeps reports whether events of the topic owned by the node are kept, the lock must be held
+func (federation *Federation) keeps(node, topic string) bool {
+	if federation.policy == nil || federation.partitioned[node] == nil {
+		return false
+	}
+	return len(federation.scoped) == 0 || federation.scoped[topic]
+}
+
+// keep holds or publishes locally an event owned by an unreachable node, it is sent to its
+// shard directly when the partition healed meanwhile, and queued behind the kept events while
+// they are being sent
+func (federation *Federation) keep(node string, shard BusPublisher, topic string, args []interface{}) {
+	federation.lock.Lock()
+	if !federation.keeps(node, topic) {
+		federation.lock.Unlock()
+		shard.Publish(topic, args...)
+		return
+	}
+	policy, state := federation.policy, federation.partitioned[node]
+	if state.healing {
+		state.pending = append(state.pending, &pendingEvent{topic: topic, args: args})
+		federation.lock.Unlock()
+		return
+	}
+	state.seq++
+	event := &pendingEvent{topic, Headers{PartitionSeqHeader: strconv.FormatUint(state.seq, 10)}, args}
+	state.pending = append(state.pending, event)
+	if policy.MaxPending > 0 && len(state.pending)-state.head > policy.MaxPending {
+		state.pending[state.head] = nil
+		state.head++
+		state.dropped++
+		if state.head > len(state.pending)/2 {
+			state.pending = append(state.pending[:0], state.pending[state.head:]...)
+			state.head = 0
+		}
+	}
+	federation.lock.Unlock()
+	if policy.Mode == ContinueLocally && policy.Local != nil {
+		publishWithHeaders(policy.Local, topic, event.headers, args)
+	}
+}
+
+// publishWithHeaders publishes the event with its headers when the publisher takes them
+func publishWithHeaders(publisher BusPublisher, topic string, headers Headers, args []interface{}) {
+	if headers == nil {
+		publisher.Publish(topic, args...)
+		return
+	}
+	if bus, ok := publisher.(interface {
+		PublishWithHeaders(topic string, headers Headers, args ...interface{})
+	}); ok {
+		bus.PublishWithHeaders(topic, headers, args...)
+		return
+	}
+	publisher.Publish(topic, args...)
+}
+
+// watch checks the nodes every heartbeat until done is closed
+func (federation *Federation) watch(heartbeat time.Duration, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(heartbeat):
+		}
+		federation.check()
+	}
+}
+
+// check pings the nodes, reporting those which became unreachable and sending to those
+// reachable again the events kept for them
+func (federation *Federation) check() {
+	federation.lock.RLock()
+	members := append([]federationMember(nil), federation.members...)
+	federation.lock.RUnlock()
+	for _, member := range members {
+		node, ok := member.shard.(pinger)
+		if !ok {
+			continue
+		}
+		if change := federation.changed(member, node.Ping()); change != nil {
+			if !change.Partitioned {
+				change.Pending, change.Dropped = federation.heal(member)
+			}
+			federation.report(*change)
+		}
+	}
+}
+
+// changed records the reachability of a member, returning the change if any. A node reachable
+// again is healing until heal sent it the events kept for it.
+func (federation *Federation) changed(member federationMember, err error) *PartitionChange {
+	federation.lock.Lock()
+	defer federation.lock.Unlock()
+	state, partitioned := federation.partitioned[member.node]
+	switch {
+	case err != nil && !partitioned:
+		federation.partitioned[member.node] = &partitionState{}
+		return &PartitionChange{Node: member.node, Partitioned: true, Err: err}
+	case err == nil && partitioned && !state.healing:
+		state.healing = true
+		return &PartitionChange{Node: member.node}
+	}
+	return nil
+}
+
+// heal sends a healing node the events kept for it, returning how many were sent and dropped.
+// They are sent without the lock, so handlers may publish to the federation meanwhile: the
+// events published during the replay are queued behind it, and the node is no longer
+// partitioned once none is left.
+func (federation *Federation) heal(member federationMember) (pending, dropped int) {
+	federation.lock.Lock()
+	state := federation.partitioned[member.node]
+	if state == nil {
+		federation.lock.Unlock()
+		return 0, 0
+	}
+	pending, dropped = len(state.pending)-state.head, state.dropped
+	for {
+		events := state.pending[state.head:]
+		state.pending, state.head = nil, 0
+		if len(events) == 0 {
+			if federation.partitioned[member.node] == state {
+				delete(federation.partitioned, member.node)
+			}
+			federation.lock.Unlock()
+			return pending, dropped
+		}
+		federation.lock.Unlock()
+		for _, event := range events {
+			publishWithHeaders(member.shard, event.topic, event.headers, event.args)
+		}
+		federation.lock.Lock()
+	}
+}
+
+// report publishes the change to the local bus of the policy
+func (federation *Federation) report(change PartitionChange) {
+	federation.lock.RLock()
+	policy := federation.policy
+	federation.lock.RUnlock()
+	if policy != nil && policy.Local != nil {
+		policy.Local.Publish(PartitionTopic, change)
+	}
+}
+
+// Ping - reports the client is reachable
+func (service *ClientService) Ping(arg bool, reply *bool) error {
+	*reply = true
+	return nil
+}
+
+// Ping checks the remote process is reachable
+func (shard *RemoteShard) Ping() error {
+	var reply bool
+	return shard.server.call(ClientPingService, true, &reply)
+}
+
+// PublishWithHeaders sends the event with its headers to the remote bus
+func (shard *RemoteShard) PublishWithHeaders(topic string, headers Headers, args ...interface{}) {
+	shard.box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Topic: topic, Args: args, Headers: headers}, time.Now()})
+}
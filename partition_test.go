@@ -0,0 +1,187 @@
+package EventBus
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyShard - shard reachable unless down
+type flakyShard struct {
+	*EventBus
+	down int32
+}
+
+func (shard *flakyShard) Ping() error {
+	if atomic.LoadInt32(&shard.down) == 1 {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+func waitPartition(t *testing.T, changes chan PartitionChange, partitioned bool) PartitionChange {
+	select {
+	case change := <-changes:
+		if change.Node != "remote" || change.Partitioned != partitioned {
+			t.Fatal(change)
+		}
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("partition change not reported")
+	}
+	return PartitionChange{}
+}
+
+func TestPartitionContinueLocally(t *testing.T) {
+	local, remote := New().(*EventBus), &flakyShard{EventBus: New().(*EventBus)}
+	federation := NewConsistentFederation(16)
+	federation.Join("remote", remote)
+	changes := make(chan PartitionChange, 2)
+	local.Subscribe(PartitionTopic, func(change PartitionChange) { changes <- change })
+	federation.SetPartitionPolicy(PartitionPolicy{Mode: ContinueLocally, Local: local, Topics: []string{"cart"}, Heartbeat: 10 * time.Millisecond})
+	defer federation.Stop()
+
+	var lock sync.Mutex
+	var handled, reconciled []string
+	local.Subscribe("cart", func(meta EventMeta, item string) {
+		lock.Lock()
+		defer lock.Unlock()
+		handled = append(handled, meta.Headers[PartitionSeqHeader]+":"+item)
+	})
+	remote.Subscribe("cart", func(meta EventMeta, item string) {
+		lock.Lock()
+		defer lock.Unlock()
+		reconciled = append(reconciled, meta.Headers[PartitionSeqHeader]+":"+item)
+	})
+	var unscoped int32
+	remote.Subscribe("log", func() { atomic.AddInt32(&unscoped, 1) })
+
+	atomic.StoreInt32(&remote.down, 1)
+	if change := waitPartition(t, changes, true); change.Err == nil {
+		t.Fatal(change)
+	}
+	if nodes := federation.Partitioned(); len(nodes) != 1 || nodes[0] != "remote" {
+		t.Fatal(nodes)
+	}
+	federation.Publish("cart", "apple")
+	federation.Publish("cart", "pear")
+	federation.Publish("log")
+	lock.Lock()
+	if len(handled) != 2 || handled[0] != "1:apple" || handled[1] != "2:pear" || len(reconciled) != 0 {
+		t.Fatal(handled, reconciled)
+	}
+	lock.Unlock()
+	if atomic.LoadInt32(&unscoped) != 1 {
+		t.Fatal("topic outside the policy kept")
+	}
+
+	atomic.StoreInt32(&remote.down, 0)
+	if change := waitPartition(t, changes, false); change.Pending != 2 {
+		t.Fatal(change)
+	}
+	federation.Publish("cart", "plum")
+	lock.Lock()
+	defer lock.Unlock()
+	if len(reconciled) != 3 || reconciled[0] != "1:apple" || reconciled[1] != "2:pear" || reconciled[2] != ":plum" {
+		t.Fatal(reconciled)
+	}
+}
+
+func TestPartitionPauseTopics(t *testing.T) {
+	local, remote := New().(*EventBus), &flakyShard{EventBus: New().(*EventBus)}
+	changes := make(chan PartitionChange, 2)
+	local.Subscribe(PartitionTopic, func(change PartitionChange) { changes <- change })
+	var locally int32
+	local.Subscribe("cart", func(int) { atomic.AddInt32(&locally, 1) })
+	var lock sync.Mutex
+	var received []int
+	remote.Subscribe("cart", func(n int) {
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, n)
+	})
+	federation := NewConsistentFederation(16)
+	federation.Join("remote", remote)
+	federation.SetPartitionPolicy(PartitionPolicy{Mode: PauseTopics, Local: local, Heartbeat: 10 * time.Millisecond, MaxPending: 2})
+	defer federation.Stop()
+
+	atomic.StoreInt32(&remote.down, 1)
+	waitPartition(t, changes, true)
+	for n := 1; n <= 3; n++ {
+		federation.Publish("cart", n)
+	}
+	lock.Lock()
+	if len(received) != 0 || atomic.LoadInt32(&locally) != 0 {
+		t.Fatal(received, locally)
+	}
+	lock.Unlock()
+
+	atomic.StoreInt32(&remote.down, 0)
+	if change := waitPartition(t, changes, false); change.Pending != 2 || change.Dropped != 1 {
+		t.Fatal(change)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(received) != 2 || received[0] != 2 || received[1] != 3 {
+		t.Fatal(received)
+	}
+}
+
+func TestRemoteShardPing(t *testing.T) {
+	shard := NewRemoteShard("localhost:2140", "/_client_bus_ping")
+	if err := shard.Ping(); err == nil {
+		t.Fatal("client not started yet")
+	}
+	client := NewClient("localhost:2140", "/_client_bus_ping", New())
+	if err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Stop()
+	if err := shard.Ping(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPartitionReplayPublishes(t *testing.T) {
+	local, remote := New().(*EventBus), &flakyShard{EventBus: New().(*EventBus)}
+	changes := make(chan PartitionChange, 2)
+	local.Subscribe(PartitionTopic, func(change PartitionChange) { changes <- change })
+	federation := NewConsistentFederation(16)
+	federation.Join("remote", remote)
+	federation.SetPartitionPolicy(PartitionPolicy{Mode: PauseTopics, Local: local, Heartbeat: 10 * time.Millisecond})
+	defer federation.Stop()
+
+	var lock sync.Mutex
+	var received []string
+	// a handler of a replayed event publishes to the federation, its event follows the replay
+	remote.Subscribe("order", func(n string) {
+		lock.Lock()
+		received = append(received, "order:"+n)
+		lock.Unlock()
+		federation.Publish("invoice", n)
+	})
+	remote.Subscribe("invoice", func(n string) {
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, "invoice:"+n)
+	})
+
+	atomic.StoreInt32(&remote.down, 1)
+	waitPartition(t, changes, true)
+	federation.Publish("order", "1")
+	federation.Publish("order", "2")
+	atomic.StoreInt32(&remote.down, 0)
+	if change := waitPartition(t, changes, false); change.Pending != 2 {
+		t.Fatal(change)
+	}
+	if nodes := federation.Partitioned(); len(nodes) != 0 {
+		t.Fatal(nodes)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(received) != 4 || received[0] != "order:1" || received[1] != "order:2" || received[2] != "invoice:1" || received[3] != "invoice:2" {
+		t.Fatal(received)
+	}
+}
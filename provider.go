@@ -0,0 +1,27 @@
+package EventBus
+
+import (
+	"context"
+)
+
+// NewEventBus returns a new EventBus with empty handlers. Unlike New it returns the concrete
+// type, which makes it usable directly as a dependency injection provider (fx.Provide, wire.Build).
+func NewEventBus() *EventBus {
+	return New().(*EventBus)
+}
+
+// Shutdown closes the bus like Close, giving up when ctx is done first.
+// Its signature fits lifecycle hooks such as fx.Hook's OnStop.
+func (bus *EventBus) Shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		bus.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,24 @@
+package EventBus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	bus := NewEventBus()
+	release := make(chan bool)
+	bus.SubscribeAsync("topic", func() { <-release }, false)
+	bus.Publish("topic")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if bus.Shutdown(ctx) != context.DeadlineExceeded {
+		t.Fail()
+	}
+	close(release)
+	if bus.Shutdown(context.Background()) != nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,154 @@
+package EventBus
+
+import (
+	"time"
+)
+
+// DedupWindow - bounds of the keys PublishOnce remembers for a topic, zero fields are unbounded
+type DedupWindow struct {
+	MaxKeys int           // oldest keys are forgotten beyond this count
+	TTL     time.Duration // keys are forgotten once older than this
+}
+
+// DedupStats - counters of the PublishOnce keys of a topic
+type DedupStats struct {
+	Keys    int    // keys currently remembered
+	Hits    uint64 // PublishOnce calls suppressed as duplicates
+	Evicted uint64 // keys forgotten by the window
+}
+
+// dedupSet - keys published to a topic through PublishOnce, oldest first in order
+type dedupSet struct {
+	window DedupWindow
+	keys   map[string]*dedupKey
+	order  []*dedupKey
+	stats  DedupStats
+	memory *memoryAccount // counts the bytes of the keys, nil when not counted
+}
+
+type dedupKey struct {
+	key       string
+	published time.Time
+	done      chan struct{}
+}
+
+// evict forgets the keys which left the window
+func (set *dedupSet) evict(now time.Time) {
+	for len(set.order) > 0 {
+		oldest := set.order[0]
+		if set.keys[oldest.key] == oldest {
+			expired := set.window.TTL > 0 && now.Sub(oldest.published) > set.window.TTL
+			if !expired && (set.window.MaxKeys <= 0 || len(set.keys) <= set.window.MaxKeys) {
+				return
+			}
+			set.forget(oldest.key)
+			set.stats.Evicted++
+		}
+		set.order[0] = nil
+		set.order = set.order[1:]
+	}
+}
+
+// dropForgotten removes the keys forgotten by ForgetOnce from the head of order
+func (set *dedupSet) dropForgotten() {
+	for len(set.order) > 0 && set.keys[set.order[0].key] != set.order[0] {
+		set.order[0] = nil
+		set.order = set.order[1:]
+	}
+}
+
+// compact removes the keys forgotten by ForgetOnce from order once they are the majority, order
+// holds every remembered key besides them
+func (set *dedupSet) compact() {
+	if len(set.order) <= 2*len(set.keys) {
+		return
+	}
+	order := make([]*dedupKey, 0, len(set.keys))
+	for _, entry := range set.order {
+		if set.keys[entry.key] == entry {
+			order = append(order, entry)
+		}
+	}
+	set.order = order
+}
+
+func (set *dedupSet) forget(key string) {
+	if _, ok := set.keys[key]; ok {
+		delete(set.keys, key)
+		set.memory.add(memoryDedup, -(dedupKeySize + int64(len(key))))
+	}
+}
+
+// onceSet returns the keys of the topic, bus.onceLock must be held
+func (bus *EventBus) onceSet(topic string) *dedupSet {
+	if bus.onceKeys == nil {
+		bus.onceKeys = make(map[string]*dedupSet)
+	}
+	set, ok := bus.onceKeys[topic]
+	if !ok {
+		set = &dedupSet{keys: make(map[string]*dedupKey), memory: bus.memory}
+		bus.onceKeys[topic] = set
+	}
+	return set
+}
+
+// SetDedupWindow bounds the keys PublishOnce remembers for the topic, by count and by age,
+// so topics needing long windows and those needing short ones can share the bus.
+func (bus *EventBus) SetDedupWindow(topic string, window DedupWindow) {
+	bus.onceLock.Lock()
+	defer bus.onceLock.Unlock()
+	set := bus.onceSet(topic)
+	set.window = window
+	set.evict(time.Now())
+}
+
+// DedupStats returns the counters of the PublishOnce keys of the topic.
+func (bus *EventBus) DedupStats(topic string) DedupStats {
+	bus.onceLock.Lock()
+	defer bus.onceLock.Unlock()
+	set, ok := bus.onceKeys[topic]
+	if !ok {
+		return DedupStats{}
+	}
+	set.evict(time.Now())
+	stats := set.stats
+	stats.Keys = len(set.keys)
+	return stats
+}
+
+// PublishOnce publishes the event unless an event with the same key was already published
+// to the topic through PublishOnce, so racing goroutines deliver it exactly once (e.g. cache
+// fill or initialization events). Callers which lose the race wait until the winner's Publish
+// returned. It reports whether this call published the event.
+func (bus *EventBus) PublishOnce(topic, key string, args ...interface{}) bool {
+	now := time.Now()
+	bus.onceLock.Lock()
+	set := bus.onceSet(topic)
+	set.evict(now)
+	if seen, ok := set.keys[key]; ok {
+		set.stats.Hits++
+		bus.onceLock.Unlock()
+		<-seen.done
+		return false
+	}
+	entry := &dedupKey{key, now, make(chan struct{})}
+	set.keys[key] = entry
+	set.order = append(set.order, entry)
+	set.memory.add(memoryDedup, dedupKeySize+int64(len(key)))
+	set.evict(now)
+	bus.onceLock.Unlock()
+
+	defer close(entry.done)
+	bus.Publish(topic, args...)
+	return true
+}
+
+// ForgetOnce lets the next PublishOnce of key on the topic publish again.
+func (bus *EventBus) ForgetOnce(topic, key string) {
+	bus.onceLock.Lock()
+	defer bus.onceLock.Unlock()
+	if set, ok := bus.onceKeys[topic]; ok {
+		set.forget(key)
+		set.compact()
+	}
+}
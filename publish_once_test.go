@@ -0,0 +1,80 @@
+package EventBus
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPublishOnce(t *testing.T) {
+	bus := New().(*EventBus)
+	var count int32
+	bus.Subscribe("cache:fill", func(key string) { atomic.AddInt32(&count, 1) })
+
+	var wg sync.WaitGroup
+	var winners int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if bus.PublishOnce("cache:fill", "user:1", "user:1") {
+				atomic.AddInt32(&winners, 1)
+			}
+			if atomic.LoadInt32(&count) != 1 {
+				t.Error("PublishOnce returned before delivery")
+			}
+		}()
+	}
+	wg.Wait()
+	if count != 1 || winners != 1 {
+		t.Fail()
+	}
+
+	if !bus.PublishOnce("cache:fill", "user:2", "user:2") || count != 2 {
+		t.Fail()
+	}
+	bus.ForgetOnce("cache:fill", "user:1")
+	if !bus.PublishOnce("cache:fill", "user:1", "user:1") || count != 3 {
+		t.Fail()
+	}
+
+	// keys forgotten in an unbounded window do not pile up
+	for i := 0; i < 100; i++ {
+		bus.PublishOnce("cache:fill", "user:3", "user:3")
+		bus.ForgetOnce("cache:fill", "user:3")
+	}
+	if set := bus.onceKeys["cache:fill"]; len(set.keys) != 2 || len(set.order) > 4 {
+		t.Fatal(len(set.keys), len(set.order))
+	}
+	if bus.PublishOnce("cache:fill", "user:2", "user:2") {
+		t.Fail()
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.SetDedupWindow("by-count", DedupWindow{MaxKeys: 2})
+	bus.SetDedupWindow("by-age", DedupWindow{TTL: time.Millisecond})
+	bus.PublishOnce("by-count", "a")
+	bus.PublishOnce("by-count", "b")
+	if bus.PublishOnce("by-count", "b") {
+		t.Fail()
+	}
+	bus.PublishOnce("by-count", "c")
+	if !bus.PublishOnce("by-count", "a") {
+		t.Fail()
+	}
+	if stats := bus.DedupStats("by-count"); stats.Keys != 2 || stats.Hits != 1 || stats.Evicted != 2 {
+		t.Fatal(stats)
+	}
+
+	bus.PublishOnce("by-age", "a")
+	time.Sleep(3 * time.Millisecond)
+	if !bus.PublishOnce("by-age", "a") || bus.DedupStats("by-age").Evicted != 1 {
+		t.Fail()
+	}
+	if (bus.DedupStats("unknown") != DedupStats{}) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,222 @@
+package EventBus
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// PingService - Server service method reporting the incarnation of the server
+	PingService = "ServerService.Ping"
+)
+
+// ConnectionState - state of the connection of a Client to a server it subscribed at
+type ConnectionState int
+
+const (
+	// Connected - the subscriptions of the client are registered at the server
+	Connected ConnectionState = iota
+	// Disconnected - the server is unreachable, the client tries again with backoff
+	Disconnected
+	// Reconnected - the server is reachable again, or was restarted, and the subscriptions of the
+	// client were registered again
+	Reconnected
+)
+
+func (state ConnectionState) String() string {
+	switch state {
+	case Connected:
+		return "connected"
+	case Disconnected:
+		return "disconnected"
+	default:
+		return "reconnected"
+	}
+}
+
+// ConnectionChange - change of the state of the connection of a Client to a server
+type ConnectionChange struct {
+	Server  string // address and path of the server
+	State   ConnectionState
+	Err     error // why the server is disconnected
+	Attempt int   // failed attempts since the server was last connected
+}
+
+// ReconnectPolicy - how a Client watches the servers it subscribed at and connects again
+type ReconnectPolicy struct {
+	Heartbeat  time.Duration // interval between checks of a connected server
+	Backoff    time.Duration // delay before the first attempt to connect again, doubled for every further attempt
+	MaxBackoff time.Duration // upper bound of the delay, zero for no bound
+}
+
+// DefaultReconnectPolicy - policy of a Client until SetReconnectPolicy
+var DefaultReconnectPolicy = ReconnectPolicy{Heartbeat: 5 * time.Second, Backoff: 100 * time.Millisecond, MaxBackoff: 30 * time.Second}
+
+// serverConn - subscriptions of a client at a server and the state of its connection
+type serverConn struct {
+	server        remoteServer
+	subscriptions map[string]*SubscribeArg // by topic
+	connected     bool
+	incarnation   string // of the server the subscriptions are registered at
+	watched       bool   // a goroutine watches the connection
+}
+
+// SetReconnectPolicy - set how the servers subscribed at are watched and connected again
+func (client *Client) SetReconnectPolicy(policy ReconnectPolicy) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	client.policy = policy
+}
+
+// OnConnectionStateChange - set the function called whenever a server subscribed at becomes
+// unreachable, after every failed attempt to connect again, and once the server is reachable
+// again with the subscriptions registered again. It is called from the goroutine watching the
+// server, or subscribing.
+func (client *Client) OnConnectionStateChange(fn func(change ConnectionChange)) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	client.onChange = fn
+}
+
+// Ping - reports the incarnation of the server, which changes when it restarts
+func (service *ServerService) Ping(arg bool, incarnation *string) error {
+	*incarnation = service.server.incarnation
+	return nil
+}
+
+// connect registers a subscription at the server and keeps it, so it is registered again
+// whenever the server comes back from a failure or a restart. A subscription failing to
+// register now is registered once the server is reachable.
+func (client *Client) connect(server remoteServer, arg *SubscribeArg) error {
+	var incarnation string
+	err := server.call(PingService, true, &incarnation)
+	if err == nil {
+		err = server.register(arg)
+	}
+	client.lock.Lock()
+	conn, ok := client.conns[server]
+	if !ok {
+		conn = &serverConn{server: server, subscriptions: make(map[string]*SubscribeArg)}
+		client.conns[server] = conn
+	}
+	conn.subscriptions[arg.Topic] = arg
+	var change *ConnectionChange
+	switch {
+	case err != nil && (conn.connected || !ok):
+		conn.connected = false
+		change = &ConnectionChange{Server: server.String(), State: Disconnected, Err: err}
+	case err == nil && !ok:
+		conn.connected, conn.incarnation = true, incarnation
+		change = &ConnectionChange{Server: server.String(), State: Connected}
+	}
+	if !conn.watched {
+		conn.watched = true
+		go client.watch(conn, client.done)
+	}
+	client.lock.Unlock()
+	client.changed(change)
+	return err
+}
+
+// changed calls the OnConnectionStateChange function with the change, if any
+func (client *Client) changed(change *ConnectionChange) {
+	client.lock.Lock()
+	fn := client.onChange
+	client.lock.Unlock()
+	if change != nil && fn != nil {
+		fn(*change)
+	}
+}
+
+// watch checks the server every heartbeat while connected, and tries to connect again with
+// backoff once it is not, registering the subscriptions again. It returns once done is closed.
+func (client *Client) watch(conn *serverConn, done chan struct{}) {
+	attempt := 0
+	for {
+		client.lock.Lock()
+		policy, connected := client.policy, conn.connected
+		client.lock.Unlock()
+		wait := policy.Heartbeat
+		if !connected {
+			wait = policy.Backoff
+			for i := 1; i < attempt && (policy.MaxBackoff <= 0 || wait < policy.MaxBackoff); i++ {
+				wait *= 2
+			}
+			if policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+				wait = policy.MaxBackoff
+			}
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(wait):
+		}
+		reconnected, err := client.check(conn)
+		if err != nil {
+			attempt++
+			client.lock.Lock()
+			conn.connected = false
+			client.lock.Unlock()
+			client.changed(&ConnectionChange{Server: conn.server.String(), State: Disconnected, Err: err, Attempt: attempt})
+			continue
+		}
+		attempt = 0
+		if reconnected {
+			client.changed(&ConnectionChange{Server: conn.server.String(), State: Reconnected})
+		}
+	}
+}
+
+// check pings the server and registers the subscriptions again when it was disconnected or
+// restarted since they were registered, reporting whether they were
+func (client *Client) check(conn *serverConn) (reconnected bool, err error) {
+	var incarnation string
+	if err := conn.server.call(PingService, true, &incarnation); err != nil {
+		return false, err
+	}
+	client.lock.Lock()
+	current := conn.connected && conn.incarnation == incarnation
+	subscriptions := make([]*SubscribeArg, 0, len(conn.subscriptions))
+	for _, arg := range conn.subscriptions {
+		subscriptions = append(subscriptions, arg)
+	}
+	client.lock.Unlock()
+	if current {
+		return false, nil
+	}
+	for _, arg := range subscriptions {
+		if err := conn.server.register(arg); err != nil {
+			return false, err
+		}
+	}
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	conn.connected, conn.incarnation = true, incarnation
+	return true, nil
+}
+
+// stopWatching stops the goroutines watching the servers, they start again on the next
+// subscription at their server. The lock must be held.
+func (client *Client) stopWatching() {
+	close(client.done)
+	client.done = make(chan struct{})
+	for _, conn := range client.conns {
+		conn.watched = false
+	}
+}
+
+// register subscribes the client at the server
+func (server remoteServer) register(arg *SubscribeArg) error {
+	var reply bool
+	if err := server.call(RegisterService, arg, &reply); err != nil {
+		return err
+	}
+	if !reply {
+		return fmt.Errorf("server %s refused topic %s", server, arg.Topic)
+	}
+	return nil
+}
+
+func (server remoteServer) String() string {
+	return server.address + server.path
+}
@@ -0,0 +1,91 @@
+package EventBus
+
+import (
+	"net"
+	"net/http"
+	"net/rpc"
+	"testing"
+	"time"
+)
+
+// serveServer serves the server at addr until the returned function is called
+func serveServer(t *testing.T, server *Server, addr string) (stop func()) {
+	rpcServer := rpc.NewServer()
+	rpcServer.RegisterName("ServerService", server.service)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := &http.Server{Handler: rpcServer}
+	go httpServer.Serve(l)
+	return func() { httpServer.Close() }
+}
+
+func awaitState(t *testing.T, changes chan ConnectionChange, state ConnectionState) ConnectionChange {
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case change := <-changes:
+			if change.State == state {
+				return change
+			}
+		case <-deadline:
+			t.Fatal("no change to", state)
+		}
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	const addr, path = "localhost:2125", "/_server_bus_reconnect"
+	client := NewClient("localhost:2120", "/_client_bus_reconnect", New())
+	client.Start()
+	defer client.Stop()
+	client.SetReconnectPolicy(ReconnectPolicy{Heartbeat: 20 * time.Millisecond, Backoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond})
+	changes := make(chan ConnectionChange, 100)
+	client.OnConnectionStateChange(func(change ConnectionChange) { changes <- change })
+	received := make(chan int, 10)
+	client.Subscribe("topic", func(n int) { received <- n }, addr, path)
+	if change := awaitState(t, changes, Disconnected); change.Err == nil || change.Server != addr+path {
+		t.Fatal(change)
+	}
+
+	// the server starts after the subscription
+	server := NewServer(addr, path, New())
+	stop := serveServer(t, server, addr)
+	awaitState(t, changes, Reconnected)
+	server.EventBus().Publish("topic", 1)
+	if n := <-received; n != 1 {
+		t.Fatal(n)
+	}
+
+	// the server restarts, forgetting the subscription
+	stop()
+	restarted := NewServer(addr, path, New())
+	defer serveServer(t, restarted, addr)()
+	awaitState(t, changes, Reconnected)
+	if !restarted.EventBus().HasCallback("topic") {
+		t.Fatal("subscription not registered again")
+	}
+	restarted.EventBus().Publish("topic", 2)
+	select {
+	case n := <-received:
+		if n != 2 {
+			t.Fatal(n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received after restart")
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	server := NewServer("localhost:2130", "/_server_bus_twice", New())
+	reply := new(bool)
+	for _, client := range []string{"a:1", "b:1", "a:1"} {
+		if err := server.service.Register(&SubscribeArg{client, "/", PublishService, Subscribe, "topic"}, reply); err != nil || !*reply {
+			t.Fatal(err)
+		}
+	}
+	if n := len(server.EventBus().(*EventBus).handlers["topic"]); n != 2 {
+		t.Fatal(n)
+	}
+}
@@ -0,0 +1,76 @@
+package EventBus
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// HandlerTopics - implemented by components declaring which of their methods handle which topic
+type HandlerTopics interface {
+	// Topics maps topic names to the names of the methods subscribed to them
+	Topics() map[string]string
+}
+
+// registration - handler subscribed on behalf of an object
+type registration struct {
+	topic   string
+	handler *eventHandler
+}
+
+// RegisterHandlers subscribes the methods of obj listed by its Topics method.
+// Returns error if obj was already registered, is not comparable or lacks a listed method;
+// nothing is subscribed in that case.
+func (bus *EventBus) RegisterHandlers(obj HandlerTopics) error {
+	if !reflect.TypeOf(obj).Comparable() {
+		return fmt.Errorf("%T is not comparable, register a pointer to it", obj)
+	}
+	bus.lock.Lock()
+	_, registered := bus.objects[obj]
+	bus.lock.Unlock()
+	if registered {
+		return fmt.Errorf("%T is already registered", obj)
+	}
+	value := reflect.ValueOf(obj)
+	fns := make(map[string]interface{})
+	for topic, name := range obj.Topics() {
+		method := value.MethodByName(name)
+		if !method.IsValid() {
+			return fmt.Errorf("%T has no method %s for topic %s", obj, name, topic)
+		}
+		fns[topic] = method.Interface()
+	}
+	var registrations []registration
+	for topic, fn := range fns {
+		handler, err := bus.subscribeHandler(topic, fn, false, false, false)
+		if err != nil {
+			return err
+		}
+		registrations = append(registrations, registration{topic, handler})
+	}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.objects == nil {
+		bus.objects = make(map[interface{}][]registration)
+	}
+	bus.objects[obj] = registrations
+	return nil
+}
+
+// UnregisterHandlers unsubscribes every method subscribed by RegisterHandlers for obj.
+// Returns error if obj is not registered, ErrSealed on a sealed bus.
+func (bus *EventBus) UnregisterHandlers(obj HandlerTopics) error {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	registrations, ok := bus.objects[obj]
+	if !ok {
+		return fmt.Errorf("%T is not registered", obj)
+	}
+	delete(bus.objects, obj)
+	for _, r := range registrations {
+		bus.removeHandler(r.topic, bus.findHandlerPtrIdx(r.topic, r.handler))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+type orderComponent struct {
+	created   []int
+	cancelled int
+}
+
+func (c *orderComponent) Topics() map[string]string {
+	return map[string]string{
+		"order:created":   "OnCreated",
+		"order:cancelled": "OnCancelled",
+	}
+}
+
+func (c *orderComponent) OnCreated(id int) { c.created = append(c.created, id) }
+
+func (c *orderComponent) OnCancelled() { c.cancelled++ }
+
+type brokenComponent struct{}
+
+func (brokenComponent) Topics() map[string]string { return map[string]string{"topic": "Missing"} }
+
+func TestRegisterHandlers(t *testing.T) {
+	bus := New().(*EventBus)
+	first, second := &orderComponent{}, &orderComponent{}
+	if bus.RegisterHandlers(first) != nil || bus.RegisterHandlers(second) != nil {
+		t.Fatal()
+	}
+	if bus.RegisterHandlers(first) == nil {
+		t.Fail()
+	}
+	bus.Publish("order:created", 1)
+	bus.Publish("order:cancelled")
+
+	if bus.UnregisterHandlers(first) != nil || bus.UnregisterHandlers(first) == nil {
+		t.Fail()
+	}
+	bus.Publish("order:created", 2)
+	if len(first.created) != 1 || first.cancelled != 1 {
+		t.Fail()
+	}
+	// the methods of the second object, bound to another receiver, stay subscribed
+	if len(second.created) != 2 || second.created[1] != 2 {
+		t.Fail()
+	}
+}
+
+func TestRegisterHandlersMissingMethod(t *testing.T) {
+	bus := New().(*EventBus)
+	if bus.RegisterHandlers(brokenComponent{}) == nil || bus.HasCallback("topic") {
+		t.Fail()
+	}
+}
@@ -0,0 +1,44 @@
+package EventBus
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Replace swaps the implementation of the handler old subscribed to the topic for fn, keeping
+// its place and every subscription option (once, async, transactional, priority, flag, tags...).
+// Events published before the swap go to old, the following ones to fn, none is missed in
+// between. Deliveries already running finish with old, those of a transactional handler still
+// waiting run before the first one to fn.
+// Returns error if `fn` is not a function, takes an *Event besides published arguments, or old
+// is not subscribed to the topic, a once handler being delivered its event included.
+func (bus *EventBus) Replace(topic string, old, fn interface{}) error {
+	if err := checkFunc(fn); err != nil {
+		return err
+	}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	idx := bus.findHandlerIdx(topic, reflect.ValueOf(old))
+	if idx < 0 {
+		return handlerNotFound(topic, old)
+	}
+	if current := bus.handlers[topic][idx]; current.flagOnce && atomic.LoadInt32(&current.claimed) != 0 {
+		// delivered by a Publish which did not remove it yet, it would miss the replacement
+		bus.removeHandler(topic, idx)
+		return handlerNotFound(topic, old)
+	}
+	// every option and the state of the handler carry over, its deliveries queue behind those of old
+	handler := *bus.handlers[topic][idx]
+	handler.callBack = reflect.ValueOf(fn)
+	handler.sources = paramSources(handler.callBack)
+	if err := handler.checkEventParams(); err != nil {
+		return err
+	}
+	handlers := append([]*eventHandler(nil), bus.handlers[topic]...)
+	handlers[idx] = &handler
+	bus.setHandlers(topic, handlers)
+	return nil
+}
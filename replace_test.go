@@ -0,0 +1,121 @@
+package EventBus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplace(t *testing.T) {
+	bus := New().(*EventBus)
+	var got []string
+	first := func(v string) { got = append(got, "first:"+v) }
+	bus.Subscribe("topic", first)
+	bus.Subscribe("topic", func(v string) { got = append(got, "last:"+v) })
+	bus.Publish("topic", "a")
+	if err := bus.Replace("topic", first, func(v string) { got = append(got, "second:"+v) }); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic", "b")
+	if len(got) != 4 || got[0] != "first:a" || got[2] != "second:b" || got[3] != "last:b" {
+		t.Fatal(got)
+	}
+	if bus.Replace("topic", first, first) == nil || bus.Replace("topic", first, "String") == nil {
+		t.Fail()
+	}
+}
+
+func TestReplaceWhilePublishing(t *testing.T) {
+	bus := New().(*EventBus)
+	var lock sync.Mutex
+	count := 0
+	handler := func() {
+		lock.Lock()
+		count++
+		lock.Unlock()
+	}
+	bus.SubscribeAsync("topic", handler, true)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			bus.Publish("topic")
+		}
+	}()
+	bus.Replace("topic", handler, func() {
+		lock.Lock()
+		count++
+		lock.Unlock()
+	})
+	wg.Wait()
+	bus.WaitAsync()
+	if count != 100 {
+		t.Fatal(count)
+	}
+}
+
+func TestReplaceKeepsOptions(t *testing.T) {
+	bus := NewWithOptions(WithFlagProvider(FlagProviderFunc(func(flag string) bool { return false }), time.Hour)).(*EventBus)
+	calls := 0
+	old := func() { calls++ }
+	bus.SubscribeWith("topic", old, WithEnabledWhen("off"), WithTags("billing"), WithPriority(5))
+	if err := bus.Replace("topic", old, func() { calls += 10 }); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic")
+	handler := bus.handlers["topic"][0]
+	if calls != 0 || handler.flag != "off" || !handler.hasTag("billing") || handler.priority != 5 {
+		t.Fatal(calls, handler.flag)
+	}
+
+	// deliveries to the replacement queue behind those still waiting for old
+	var order []string
+	release := make(chan struct{})
+	slow := func(v string) {
+		<-release
+		order = append(order, "old:"+v)
+	}
+	bus.SubscribeAsync("tx", slow, true)
+	bus.Publish("tx", "a")
+	bus.Replace("tx", slow, func(v string) { order = append(order, "new:"+v) })
+	bus.Publish("tx", "b")
+	close(release)
+	bus.WaitAsync()
+	if len(order) != 2 || order[0] != "old:a" || order[1] != "new:b" {
+		t.Fatal(order)
+	}
+}
+
+func TestReplaceClaimedOnce(t *testing.T) {
+	bus := New().(*EventBus)
+	old := func() {}
+	bus.SubscribeOnce("topic", old)
+	// claimed by a Publish which did not remove it yet
+	bus.handlers["topic"][0].claim()
+	if bus.Replace("topic", old, func() { t.Fatal("replacement of a delivered once handler called") }) == nil {
+		t.Fatal("claimed once handler replaced")
+	}
+	if bus.HasCallback("topic") {
+		t.Fatal("claimed once handler kept")
+	}
+	bus.Publish("topic")
+
+	// racing with Publish, the once handler runs once and none stays behind
+	for i := 0; i < 100; i++ {
+		calls := 0
+		first := func() { calls++ }
+		bus.SubscribeOnce("race", first)
+		done := make(chan struct{})
+		go func() {
+			bus.Publish("race")
+			close(done)
+		}()
+		bus.Replace("race", first, func() { calls++ })
+		<-done
+		bus.Publish("race")
+		if calls != 1 || bus.HasCallback("race") {
+			t.Fatal(calls, bus.HasCallback("race"))
+		}
+	}
+}
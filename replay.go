@@ -0,0 +1,68 @@
+package EventBus
+
+import (
+	"encoding/gob"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedEvent - a published event captured by a Recorder
+type RecordedEvent struct {
+	Offset time.Duration // time elapsed since the recording started
+	Topic  string
+	Args   []interface{}
+}
+
+// Recorder - bus wrapper writing every published event to a stream before passing it on.
+// Arguments are gob encoded, so non-basic argument types must be registered with gob.Register.
+type Recorder struct {
+	Bus
+	lock    sync.Mutex
+	encoder *gob.Encoder
+	started time.Time
+	err     error
+}
+
+// NewRecorder - create a recorder writing the events published through it to w
+func NewRecorder(bus Bus, w io.Writer) *Recorder {
+	return &Recorder{Bus: bus, encoder: gob.NewEncoder(w), started: time.Now()}
+}
+
+// Publish records the event and publishes it on the wrapped bus.
+func (recorder *Recorder) Publish(topic string, args ...interface{}) {
+	recorder.lock.Lock()
+	if recorder.err == nil {
+		recorder.err = recorder.encoder.Encode(&RecordedEvent{time.Since(recorder.started), topic, args})
+	}
+	recorder.lock.Unlock()
+	recorder.Bus.Publish(topic, args...)
+}
+
+// Err returns the first error met while writing the recording, recording stops after it.
+func (recorder *Recorder) Err() error {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	return recorder.err
+}
+
+// Replay publishes the events recorded in r on bus in their original order.
+// Speed scales the original timing: 1 replays in real time, 2 twice as fast
+// and 0 or less publishes every event without waiting.
+func Replay(r io.Reader, bus Bus, speed float64) error {
+	decoder := gob.NewDecoder(r)
+	started := time.Now()
+	for {
+		event := new(RecordedEvent)
+		if err := decoder.Decode(event); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if speed > 0 {
+			due := started.Add(time.Duration(float64(event.Offset) / speed))
+			time.Sleep(time.Until(due))
+		}
+		bus.Publish(event.Topic, event.Args...)
+	}
+}
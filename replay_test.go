@@ -0,0 +1,51 @@
+package EventBus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	buf := new(bytes.Buffer)
+	recorder := NewRecorder(New(), buf)
+	recorder.Publish("topic", 1, "a")
+	time.Sleep(20 * time.Millisecond)
+	recorder.Publish("other", 2, "b")
+	if recorder.Err() != nil {
+		t.Fatal(recorder.Err())
+	}
+
+	var got []int
+	bus := New()
+	bus.Subscribe("topic", func(a int, s string) { got = append(got, a) })
+	bus.Subscribe("other", func(a int, s string) { got = append(got, a) })
+
+	started := time.Now()
+	if err := Replay(bytes.NewReader(buf.Bytes()), bus, 1); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(started) < 20*time.Millisecond {
+		t.Fail()
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fail()
+	}
+}
+
+func TestReplayAccelerated(t *testing.T) {
+	buf := new(bytes.Buffer)
+	recorder := NewRecorder(New(), buf)
+	recorder.Publish("topic")
+	time.Sleep(50 * time.Millisecond)
+	recorder.Publish("topic")
+
+	count := 0
+	bus := New()
+	bus.Subscribe("topic", func() { count++ })
+	started := time.Now()
+	Replay(buf, bus, 0)
+	if time.Since(started) > 40*time.Millisecond || count != 2 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,42 @@
+package EventBus
+
+import (
+	"fmt"
+)
+
+// UnsubscribeTopic removes every handler of the topic at once, whatever subscribed them.
+// Returns ErrTopicNotFound if there are no callbacks subscribed to the topic.
+func (bus *EventBus) UnsubscribeTopic(topic string) error {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	if len(bus.handlers[topic]) == 0 {
+		return fmt.Errorf("topic %s: %w", topic, ErrTopicNotFound)
+	}
+	bus.removeTopicHandlers(topic)
+	return nil
+}
+
+// Reset removes the handlers of every topic, leaving the bus as created: options, statistics
+// and state topics are kept. Async deliveries already started still complete.
+func (bus *EventBus) Reset() error {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	for topic := range bus.handlers {
+		bus.removeTopicHandlers(topic)
+	}
+	bus.objects = nil
+	return nil
+}
+
+// removeTopicHandlers removes the handlers of the topic, the bus lock must be held
+func (bus *EventBus) removeTopicHandlers(topic string) {
+	for idx := len(bus.handlers[topic]) - 1; idx >= 0; idx-- {
+		bus.removeHandler(topic, idx)
+	}
+}
@@ -0,0 +1,51 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnsubscribeTopic(t *testing.T) {
+	bus := New().(*EventBus)
+	calls := 0
+	bus.Subscribe("order:placed", func() { calls++ })
+	bus.SubscribeAsync("order:placed", func() { calls++ }, true)
+	bus.Subscribe("order:shipped", func() { calls++ })
+	if err := bus.UnsubscribeTopic("order:placed"); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("order:placed")
+	bus.Publish("order:shipped")
+	bus.WaitAsync()
+	if calls != 1 || bus.HasCallback("order:placed") {
+		t.Fatal(calls)
+	}
+	if err := bus.UnsubscribeTopic("order:placed"); !errors.Is(err, ErrTopicNotFound) {
+		t.Fatal(err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	bus := New().(*EventBus)
+	calls := 0
+	for _, topic := range []string{"a", "b", "c"} {
+		bus.Subscribe(topic, func() { calls++ })
+	}
+	if err := bus.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range []string{"a", "b", "c"} {
+		if bus.HasCallback(topic) {
+			t.Fatal(topic)
+		}
+		bus.Publish(topic)
+	}
+	if calls != 0 {
+		t.Fatal(calls)
+	}
+	bus.Subscribe("a", func() { calls++ })
+	bus.Publish("a")
+	if calls != 1 {
+		t.Fatal(calls)
+	}
+}
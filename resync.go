@@ -0,0 +1,131 @@
+package EventBus
+
+import (
+	"net/rpc"
+	"sort"
+)
+
+const (
+	// ResendService - Server service method sending retained events again
+	ResendService = "ServerService.Resend"
+)
+
+// GapTopic - topic a Client publishes a Gap to when events it missed could not be sent again
+const GapTopic = "bridge:gap"
+
+// Gap - events of a remote topic a client missed for good, from From to To inclusive
+type Gap struct {
+	Topic string
+	From  uint64
+	To    uint64
+}
+
+// ResendArg - object to hold the range of events a client missed
+type ResendArg struct {
+	Topic string
+	From  uint64
+	To    uint64
+}
+
+// ResendReply - retained events of the requested range, in sequence order
+type ResendReply struct {
+	Events []*ClientArg
+}
+
+// remoteServer - server a client subscribed to a topic at
+type remoteServer struct {
+	address string
+	path    string
+}
+
+// SetRetention - keep the last n events of every topic sent to clients, so a client detecting
+// it missed events can have them sent again. Events older than that are reported as a Gap.
+func (server *Server) SetRetention(n int) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.retention = n
+	for topic, events := range server.retained {
+		server.retained[topic] = trimRetained(events, n)
+	}
+}
+
+// retain keeps the event for clients requesting it again, every client subscribed to the topic
+// passes the same event so only the first one is kept
+func (server *Server) retain(event *ClientArg) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if server.retention <= 0 {
+		return
+	}
+	events := server.retained[event.Topic]
+	if len(events) > 0 && events[len(events)-1].Seq >= event.Seq {
+		return
+	}
+	server.retained[event.Topic] = trimRetained(append(events, event), server.retention)
+}
+
+func trimRetained(events []*ClientArg, n int) []*ClientArg {
+	if len(events) <= n {
+		return events
+	}
+	return append([]*ClientArg(nil), events[len(events)-n:]...)
+}
+
+// Resend - sends back the retained events of a topic in the requested range
+func (service *ServerService) Resend(arg *ResendArg, reply *ResendReply) error {
+	server := service.server
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	events := server.retained[arg.Topic]
+	i := sort.Search(len(events), func(i int) bool { return events[i].Seq >= arg.From })
+	for ; i < len(events) && events[i].Seq <= arg.To; i++ {
+		reply.Events = append(reply.Events, events[i])
+	}
+	return nil
+}
+
+// resync checks the event follows the last one received for its topic. When events were missed
+// they are requested from the server and published first, those the server no longer retains
+// are reported on GapTopic.
+func (client *Client) resync(arg *ClientArg) {
+	client.lock.Lock()
+	server, ok := client.servers[arg.Topic]
+	last := client.seqs[arg.Topic]
+	if ok && arg.Seq > last {
+		client.seqs[arg.Topic] = arg.Seq
+	}
+	client.lock.Unlock()
+	if !ok || arg.Seq == 0 || last == 0 || arg.Seq <= last+1 {
+		return
+	}
+
+	from, to := last+1, arg.Seq-1
+	reply := new(ResendReply)
+	if err := server.call(ResendService, &ResendArg{arg.Topic, from, to}, reply); err != nil {
+		reply.Events = nil
+	}
+	next := from
+	for _, event := range reply.Events {
+		if event.Seq > next {
+			client.eventBus.Publish(GapTopic, Gap{arg.Topic, next, event.Seq - 1})
+		}
+		if err := client.claim(event); err == nil {
+			client.publish(event)
+		} else {
+			client.eventBus.Publish(GapTopic, Gap{arg.Topic, event.Seq, event.Seq})
+		}
+		next = event.Seq + 1
+	}
+	if next <= to {
+		client.eventBus.Publish(GapTopic, Gap{arg.Topic, next, to})
+	}
+}
+
+func (server remoteServer) call(serviceMethod string, arg, reply interface{}) error {
+	rpcClient, err := rpc.DialHTTPPath("tcp", server.address, server.path)
+	if err != nil {
+		return err
+	}
+	defer rpcClient.Close()
+	return rpcClient.Call(serviceMethod, arg, reply)
+}
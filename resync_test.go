@@ -0,0 +1,64 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResyncMissedEvents(t *testing.T) {
+	server := NewServer(":2050", "/_server_bus_resync", New())
+	server.SetRetention(2)
+	server.Start()
+	defer server.Stop()
+
+	// the client service is not started, so the events published by the server are missed
+	client := NewClient(":2055", "/_client_bus_resync", New())
+	var received []interface{}
+	client.Subscribe("topic", func(n int) { received = append(received, n) }, ":2050", "/_server_bus_resync")
+	client.EventBus().Subscribe(GapTopic, func(gap Gap) { received = append(received, gap) })
+	for i := 1; i <= 4; i++ {
+		server.EventBus().Publish("topic", i)
+	}
+
+	reply := new(bool)
+	client.service.PushEvent(&ClientArg{Args: []interface{}{1}, Topic: "topic", Seq: 1}, reply)
+	client.service.PushEvent(&ClientArg{Args: []interface{}{5}, Topic: "topic", Seq: 5}, reply)
+	expected := []interface{}{1, Gap{"topic", 2, 2}, 3, 4, 5}
+	if len(received) != len(expected) {
+		t.Fatal(received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Fatal(received)
+		}
+	}
+}
+
+func TestResyncUnreachableServer(t *testing.T) {
+	client := NewClient(":2060", "/_client_bus_unreachable", New())
+	client.servers["topic"] = remoteServer{"localhost:1", "/_unreachable_"}
+	var gaps []Gap
+	client.EventBus().Subscribe(GapTopic, func(gap Gap) { gaps = append(gaps, gap) })
+	reply := new(bool)
+	client.service.PushEvent(&ClientArg{Topic: "topic", Seq: 3}, reply)
+	client.service.PushEvent(&ClientArg{Topic: "topic", Seq: 4}, reply)
+	client.service.PushEvent(&ClientArg{Topic: "topic", Seq: 7}, reply)
+	if len(gaps) != 1 || gaps[0] != (Gap{"topic", 5, 6}) {
+		t.Fatal(gaps)
+	}
+}
+
+func TestRetentionTrimmed(t *testing.T) {
+	server := NewServer(":2065", "/_server_bus_retention", New())
+	server.SetRetention(3)
+	for i := 1; i <= 5; i++ {
+		server.retain(&ClientArg{Topic: "topic", Seq: uint64(i), Args: []interface{}{time.Duration(i)}})
+		server.retain(&ClientArg{Topic: "topic", Seq: uint64(i)})
+	}
+	server.SetRetention(2)
+	reply := new(ResendReply)
+	server.service.Resend(&ResendArg{"topic", 1, 5}, reply)
+	if len(reply.Events) != 2 || reply.Events[0].Seq != 4 || reply.Events[1].Args[0] != time.Duration(5) {
+		t.Fatal(reply.Events)
+	}
+}
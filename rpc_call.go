@@ -0,0 +1,53 @@
+package EventBus
+
+import (
+	"time"
+)
+
+const (
+	// RPCCallStartTopic - topic a CallObserver publishes an RPCCall to when a call begins
+	RPCCallStartTopic = "rpc:call:start"
+	// RPCCallFinishTopic - topic a CallObserver publishes an RPCCall to once a call ended
+	RPCCallFinishTopic = "rpc:call:finish"
+)
+
+// RPCCall - remote procedure call observed by a CallObserver, Code, Status, Error and Duration are
+// set once it ended
+type RPCCall struct {
+	Method   string // full method name, e.g. /orders.Orders/Create
+	Stream   bool
+	Started  time.Time
+	Code     int    // status code, 0 being OK with gRPC
+	Status   string // name of the status code
+	Error    string // message of the error the call ended with, empty on success
+	Duration time.Duration
+}
+
+// CallObserver - publishes the lifecycle of remote procedure calls to a bus, the transport
+// independent part of the interceptors of the grpcbus package
+type CallObserver struct {
+	Bus         *EventBus
+	StartTopic  string // RPCCallStartTopic when empty
+	FinishTopic string // RPCCallFinishTopic when empty
+}
+
+// Begin publishes the start of a call with the given headers, see CorrelationIDHeader, and returns
+// the function publishing its end, called with the status code the call ended with.
+func (observer CallObserver) Begin(method string, stream bool, headers Headers) (finish func(code int, status string, err error)) {
+	start, end := observer.StartTopic, observer.FinishTopic
+	if start == "" {
+		start = RPCCallStartTopic
+	}
+	if end == "" {
+		end = RPCCallFinishTopic
+	}
+	call := RPCCall{Method: method, Stream: stream, Started: time.Now()}
+	observer.Bus.PublishWithHeaders(start, headers, call)
+	return func(code int, status string, err error) {
+		call.Code, call.Status, call.Duration = code, status, time.Since(call.Started)
+		if err != nil {
+			call.Error = err.Error()
+		}
+		observer.Bus.PublishWithHeaders(end, headers, call)
+	}
+}
@@ -0,0 +1,28 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCallObserver(t *testing.T) {
+	bus := New().(*EventBus)
+	var calls []RPCCall
+	bus.Subscribe(RPCCallStartTopic, func(call RPCCall) { calls = append(calls, call) })
+	bus.Subscribe("audit:rpc", func(meta EventMeta, call RPCCall) {
+		if meta.Headers[CorrelationIDHeader] != "req-1" {
+			t.Fatal(meta.Headers)
+		}
+		calls = append(calls, call)
+	})
+
+	observer := CallObserver{Bus: bus, FinishTopic: "audit:rpc"}
+	finish := observer.Begin("/orders.Orders/Create", false, Headers{CorrelationIDHeader: "req-1"})
+	finish(5, "NotFound", errors.New("no such order"))
+	if len(calls) != 2 || calls[0].Method != "/orders.Orders/Create" || calls[0].Code != 0 {
+		t.Fatal(calls)
+	}
+	if ended := calls[1]; ended.Code != 5 || ended.Status != "NotFound" || ended.Error != "no such order" || ended.Duration <= 0 {
+		t.Fatal(ended)
+	}
+}
@@ -0,0 +1,98 @@
+package EventBus
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// fileScheduleStore - ScheduleStore rewriting a single gob encoded file on every change, under
+// a lock file so processes sharing it do not lose each other's changes
+type fileScheduleStore struct {
+	lock sync.Mutex
+	path string
+}
+
+// NewFileScheduleStore returns a ScheduleStore keeping the events in the file at path. Arguments
+// follow the same gob registration rules as the rpc transport. Suits a moderate number of
+// pending events, every change rewrites the whole file.
+func NewFileScheduleStore(path string) ScheduleStore {
+	return &fileScheduleStore{path: path}
+}
+
+func (store *fileScheduleStore) Load() ([]ScheduledEvent, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return store.load()
+}
+
+func (store *fileScheduleStore) Save(event ScheduledEvent) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	unlock, err := lockFile(store.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	events, err := store.load()
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		if events[i].ID == event.ID {
+			events[i] = event
+			return store.write(events)
+		}
+	}
+	return store.write(append(events, event))
+}
+
+func (store *fileScheduleStore) Delete(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	unlock, err := lockFile(store.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	events, err := store.load()
+	if err != nil {
+		return err
+	}
+	kept := events[:0]
+	for _, event := range events {
+		if event.ID != id {
+			kept = append(kept, event)
+		}
+	}
+	if len(kept) == len(events) {
+		return nil
+	}
+	return store.write(kept)
+}
+
+func (store *fileScheduleStore) load() ([]ScheduledEvent, error) {
+	data, err := ioutil.ReadFile(store.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var events []ScheduledEvent
+	return events, gob.NewDecoder(bytes.NewReader(data)).Decode(&events)
+}
+
+// write replaces the file through a rename, so a crash leaves either version complete
+func (store *fileScheduleStore) write(events []ScheduledEvent) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(events); err != nil {
+		return err
+	}
+	tmp := store.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, store.path)
+}
@@ -0,0 +1,311 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSchedulerStopped - the scheduler was stopped, by Stop or by closing its bus
+var ErrSchedulerStopped = errors.New("scheduler is stopped")
+
+// ScheduledEvent - publish planned for a later time
+type ScheduledEvent struct {
+	ID    string
+	Topic string
+	Args  []interface{}
+	At    time.Time
+	Cron  string // spec of a recurring event, At being its next occurrence, see PublishCron
+	Zone  string // name of the time zone Cron is evaluated in
+}
+
+// ScheduleStore - storage keeping scheduled events across restarts, a scheduled event is saved
+// when planned and deleted once published or cancelled
+type ScheduleStore interface {
+	Save(event ScheduledEvent) error
+	Delete(id string) error
+	Load() ([]ScheduledEvent, error)
+}
+
+// CatchUp - what a new scheduler does with stored events which became due while no process was running
+type CatchUp int
+
+const (
+	// CatchUpAll - publish every overdue event, in schedule order
+	CatchUpAll CatchUp = iota
+	// CatchUpLatest - publish only the latest overdue event of every topic, dropping the others
+	CatchUpLatest
+	// CatchUpNone - drop overdue events
+	CatchUpNone
+)
+
+// Scheduler - publishes events to its bus at a later time, keeping them in a ScheduleStore
+// so they survive restarts
+type Scheduler struct {
+	bus      *EventBus
+	store    ScheduleStore
+	calendar Calendar // days skipped by recurring events, none when nil
+	jitter   time.Duration
+	random   *rand.Rand // draws the jitter, used with the lock held
+	catchUp  CatchUp
+	lock     sync.Mutex
+	timers   map[string]*time.Timer // armed events by ID
+	stopped  bool
+	leases   LeaseStore // shared with the other instances using the store, see WithLease
+	lease    string
+	ttl      time.Duration
+	owner    string        // identifies the scheduler to the lease store
+	active   bool          // holds the lease, always true without one
+	done     chan struct{} // closed by Stop, ends renewing the lease
+}
+
+// SchedulerOption - setting of a scheduler created by NewScheduler
+type SchedulerOption func(s *Scheduler)
+
+// WithCalendar makes recurring events skip the days of calendar, e.g. public holidays
+func WithCalendar(calendar Calendar) SchedulerOption {
+	return func(s *Scheduler) {
+		s.calendar = calendar
+	}
+}
+
+// WithJitter delays every publish by a random duration up to max, so instances sharing a schedule
+// do not all hit shared downstreams at the same moment. Overdue events published when the
+// scheduler starts are not delayed.
+func WithJitter(max time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.jitter = max
+		s.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+}
+
+// NewScheduler returns a scheduler publishing to the bus, picking up the events left in store by
+// a previous run. Overdue ones are handled by the catchUp policy before NewScheduler returns, so
+// subscribe to their topics first, recurring ones are then armed for their next occurrence.
+// A nil store keeps events in memory only. The scheduler is stopped by Stop or Close.
+func (bus *EventBus) NewScheduler(store ScheduleStore, catchUp CatchUp, opts ...SchedulerOption) (*Scheduler, error) {
+	if atomic.LoadInt32(&bus.closed) != 0 {
+		return nil, ErrBusClosed
+	}
+	s := &Scheduler{bus: bus, store: store, catchUp: catchUp, timers: make(map[string]*time.Timer)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	active := true
+	if s.leases != nil {
+		var err error
+		s.owner = bus.NewID()
+		if active, err = s.leases.Acquire(s.lease, s.owner, s.ttl); err != nil {
+			return nil, err
+		}
+		s.active, s.done = active, make(chan struct{})
+	}
+	if active {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	bus.lock.Lock()
+	bus.schedulers[s] = true
+	bus.lock.Unlock()
+	if s.leases != nil {
+		go s.renew()
+	}
+	return s, nil
+}
+
+// load arms the stored events, handling the overdue ones by the catch-up policy
+func (s *Scheduler) load() error {
+	var pending []ScheduledEvent
+	if s.store != nil {
+		var err error
+		if pending, err = s.store.Load(); err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].At.Before(pending[j].At) })
+
+	now := time.Now()
+	var overdue []ScheduledEvent
+	for _, event := range pending {
+		if event.At.After(now) {
+			s.arm(event)
+		} else {
+			overdue = append(overdue, event)
+		}
+	}
+	latest := make(map[string]int, len(overdue))
+	for i, event := range overdue {
+		latest[event.Topic] = i
+	}
+	for i, event := range overdue {
+		if s.catchUp == CatchUpAll || s.catchUp == CatchUpLatest && latest[event.Topic] == i {
+			s.bus.Publish(event.Topic, event.Args...)
+		}
+		s.lock.Lock()
+		s.advanceLocked(event)
+		s.lock.Unlock()
+	}
+	return nil
+}
+
+// PublishAt publishes args to topic at the given time and returns the ID of the scheduled event,
+// which Cancel takes. The arguments of a stored event must be encodable by the store.
+func (s *Scheduler) PublishAt(topic string, at time.Time, args ...interface{}) (string, error) {
+	event := ScheduledEvent{ID: s.bus.NewID(), Topic: topic, Args: args, At: at}
+	return event.ID, s.schedule(event)
+}
+
+// PublishAfter publishes args to topic once delay elapsed, see PublishAt
+func (s *Scheduler) PublishAfter(topic string, delay time.Duration, args ...interface{}) (string, error) {
+	return s.PublishAt(topic, time.Now().Add(delay), args...)
+}
+
+// PublishCron publishes args to topic at every time matching the cron spec in loc, time.Local
+// when nil, skipping the days of the scheduler's calendar. See ParseCron for the spec and
+// PublishAt for the returned ID.
+func (s *Scheduler) PublishCron(topic, spec string, loc *time.Location, args ...interface{}) (string, error) {
+	schedule, err := ParseCron(spec, loc)
+	if err != nil {
+		return "", err
+	}
+	event := ScheduledEvent{ID: s.bus.NewID(), Topic: topic, Args: args, Cron: spec, Zone: schedule.Location().String()}
+	if event.At = schedule.Skipping(s.calendar).Next(time.Now()); event.At.IsZero() {
+		return "", fmt.Errorf("cron spec %q never matches", spec)
+	}
+	return event.ID, s.schedule(event)
+}
+
+// Cancel drops a scheduled event which was not published yet, or every later occurrence of a recurring one
+func (s *Scheduler) Cancel(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if timer, ok := s.timers[id]; ok {
+		timer.Stop()
+		delete(s.timers, id)
+	}
+	if s.store != nil {
+		return s.store.Delete(id)
+	}
+	return nil
+}
+
+// Stop stops publishing, the stored events stay for the next scheduler using the store
+func (s *Scheduler) Stop() {
+	s.bus.lock.Lock()
+	delete(s.bus.schedulers, s)
+	s.bus.lock.Unlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	s.disarmLocked()
+	if s.leases != nil {
+		close(s.done)
+		if s.active {
+			s.leases.Release(s.lease, s.owner)
+		}
+		s.active = false
+	}
+}
+
+func (s *Scheduler) disarmLocked() {
+	for id, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, id)
+	}
+}
+
+// schedule saves the event and arms it
+func (s *Scheduler) schedule(event ScheduledEvent) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stopped {
+		return ErrSchedulerStopped
+	}
+	if s.store != nil {
+		if err := s.store.Save(event); err != nil {
+			return err
+		}
+	}
+	if s.leases == nil || s.active {
+		s.armLocked(event)
+	}
+	return nil
+}
+
+func (s *Scheduler) arm(event ScheduledEvent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.stopped {
+		s.armLocked(event)
+	}
+}
+
+func (s *Scheduler) armLocked(event ScheduledEvent) {
+	delay := time.Until(event.At)
+	if s.jitter > 0 {
+		delay += time.Duration(s.random.Int63n(int64(s.jitter)))
+	}
+	s.timers[event.ID] = time.AfterFunc(delay, func() { s.fire(event) })
+}
+
+// fire publishes the event unless it was cancelled or the scheduler stopped meanwhile. The event
+// is advanced in the store once published, so a crash in between publishes it again.
+func (s *Scheduler) fire(event ScheduledEvent) {
+	s.lock.Lock()
+	_, armed := s.timers[event.ID]
+	s.lock.Unlock()
+	if !armed {
+		return
+	}
+	s.bus.Publish(event.Topic, event.Args...)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, armed = s.timers[event.ID]
+	delete(s.timers, event.ID)
+	if armed || s.stopped || event.Cron == "" {
+		s.advanceLocked(event)
+	}
+}
+
+// advanceLocked is done with the current occurrence of the event: a recurring event is saved
+// and armed for its next occurrence, any other is deleted from the store
+func (s *Scheduler) advanceLocked(event ScheduledEvent) {
+	if event.Cron != "" {
+		if schedule, err := s.cron(event); err == nil {
+			if event.At = schedule.Next(time.Now()); !event.At.IsZero() {
+				if s.store != nil {
+					s.store.Save(event)
+				}
+				if !s.stopped {
+					s.armLocked(event)
+				}
+				return
+			}
+		}
+	}
+	if s.store != nil {
+		s.store.Delete(event.ID)
+	}
+}
+
+// cron returns the schedule of a recurring event
+func (s *Scheduler) cron(event ScheduledEvent) (*CronSchedule, error) {
+	loc, err := time.LoadLocation(event.Zone)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := ParseCron(event.Cron, loc)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Skipping(s.calendar), nil
+}
@@ -0,0 +1,107 @@
+package EventBus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSchedulerPublishAfter(t *testing.T) {
+	bus := New().(*EventBus)
+	received := make(chan int, 2)
+	bus.Subscribe("topic", func(n int) { received <- n })
+	s, err := bus.NewScheduler(nil, CatchUpAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PublishAfter("topic", 5*time.Millisecond, 1)
+	id, _ := s.PublishAfter("topic", 5*time.Millisecond, 2)
+	s.Cancel(id)
+	select {
+	case n := <-received:
+		if n != 1 {
+			t.Fatal(n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("scheduled event was not published")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(received) != 0 {
+		t.Fatal("cancelled event was published")
+	}
+	bus.Close()
+	if _, err := s.PublishAfter("topic", time.Millisecond, 3); err != ErrSchedulerStopped {
+		t.Fatal(err)
+	}
+}
+
+func TestSchedulerSurvivesRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileScheduleStore(filepath.Join(dir, "schedule"))
+
+	first := New().(*EventBus)
+	s, _ := first.NewScheduler(store, CatchUpAll)
+	s.PublishAt("later", time.Now().Add(time.Hour), "later")
+	s.PublishAfter("due", 20*time.Millisecond, 1)
+	s.PublishAfter("due", 20*time.Millisecond, 2)
+	first.Close()
+	time.Sleep(30 * time.Millisecond)
+
+	second := New().(*EventBus)
+	var received []int
+	second.Subscribe("due", func(n int) { received = append(received, n) })
+	s, err = second.NewScheduler(store, CatchUpLatest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	if len(received) != 1 || received[0] != 2 {
+		t.Fatal(received)
+	}
+	if pending, _ := store.Load(); len(pending) != 1 || pending[0].Topic != "later" {
+		t.Fatal(pending)
+	}
+}
+
+func TestSchedulerCatchUpNone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewFileScheduleStore(filepath.Join(dir, "schedule"))
+	store.Save(ScheduledEvent{ID: "a", Topic: "topic", At: time.Now().Add(-time.Minute)})
+	bus := New().(*EventBus)
+	bus.Subscribe("topic", func() { t.Fail() })
+	if _, err := bus.NewScheduler(store, CatchUpNone); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := store.Load(); len(pending) != 0 {
+		t.Fatal(pending)
+	}
+	bus.Close()
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	bus := New().(*EventBus)
+	defer bus.Close()
+	published := make(chan time.Time, 1)
+	bus.Subscribe("topic", func() { published <- time.Now() })
+	s, _ := bus.NewScheduler(nil, CatchUpAll, WithJitter(20*time.Millisecond))
+	at := time.Now().Add(5 * time.Millisecond)
+	s.PublishAt("topic", at)
+	select {
+	case fired := <-published:
+		if fired.Before(at) || fired.After(at.Add(time.Second)) {
+			t.Fatal(fired.Sub(at))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("scheduled event was not published")
+	}
+}
@@ -0,0 +1,49 @@
+package EventBus
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+var scopeType = reflect.TypeOf((*Scope)(nil))
+
+// Scope - launches goroutines tracked by the bus: they count for WaitAsync, Close and Shutdown
+// wait for them and their context is cancelled once the bus closes. Handlers get the scope by
+// declaring a *Scope parameter, so background work they spawn does not escape shutdown.
+type Scope struct {
+	bus    *EventBus
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Scope returns the scope of the bus, the one injected into handlers
+func (bus *EventBus) Scope() *Scope {
+	return &bus.scope
+}
+
+// Context returns the context of the scope, cancelled when the bus closes
+func (scope *Scope) Context() context.Context {
+	scope.once.Do(func() {
+		scope.ctx, scope.cancel = context.WithCancel(context.Background())
+	})
+	return scope.ctx
+}
+
+// Go runs fn on a goroutine of its own with the context of the scope. It counts for WaitAsync
+// until fn returns, fn should return soon after the context is cancelled.
+func (scope *Scope) Go(fn func(ctx context.Context)) {
+	ctx := scope.Context()
+	scope.bus.wg.Add(1)
+	go func() {
+		defer scope.bus.wg.Done()
+		fn(ctx)
+	}()
+}
+
+// close cancels the context of the scope
+func (scope *Scope) close() {
+	scope.Context()
+	scope.cancel()
+}
@@ -0,0 +1,52 @@
+package EventBus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScope(t *testing.T) {
+	bus := New().(*EventBus)
+	var finished int32
+	release := make(chan struct{})
+	bus.Subscribe("job", func(scope *Scope, n int) {
+		scope.Go(func(ctx context.Context) {
+			<-release
+			atomic.AddInt32(&finished, int32(n))
+		})
+	})
+	bus.Publish("job", 2)
+	waited := make(chan struct{})
+	go func() {
+		bus.WaitAsync()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("WaitAsync returned before the scope goroutine")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-waited
+	if atomic.LoadInt32(&finished) != 2 {
+		t.Fatal(finished)
+	}
+}
+
+func TestScopeCancelledOnClose(t *testing.T) {
+	bus := New().(*EventBus)
+	var cancelled int32
+	bus.SubscribeAsync("job", func(scope *Scope) {
+		scope.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			atomic.StoreInt32(&cancelled, 1)
+		})
+	}, false)
+	bus.Publish("job")
+	bus.Close()
+	if atomic.LoadInt32(&cancelled) != 1 || bus.Scope().Context().Err() == nil {
+		t.Fatal("scope goroutine not cancelled and waited for by Close")
+	}
+}
@@ -0,0 +1,44 @@
+package EventBus
+
+import (
+	"errors"
+)
+
+// ErrSealed - returned when subscribing or unsubscribing on a sealed bus
+var ErrSealed = errors.New("bus is sealed")
+
+// sealedTable - immutable handler table of a sealed bus
+type sealedTable struct {
+	handlers handlerMap
+	trace    *traceRing
+}
+
+func (bus *EventBus) sealedTable() *sealedTable {
+	table, _ := bus.sealed.Load().(*sealedTable)
+	return table
+}
+
+// Seal freezes the handler table: Subscribe and Unsubscribe return ErrSealed from now on,
+// and helpers can no longer remove their handlers. Publish then dispatches from the frozen
+// table, where once handlers stay but are skipped once claimed. Meant for services whose wiring
+// is static after initialization.
+func (bus *EventBus) Seal() {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return
+	}
+	table := &sealedTable{
+		handlers: make(map[string][]*eventHandler, len(bus.handlers)),
+		trace:    bus.trace,
+	}
+	for topic, handlers := range bus.handlers {
+		table.handlers[topic] = append([]*eventHandler(nil), handlers...)
+	}
+	bus.sealed.Store(table)
+}
+
+// Sealed reports whether Seal was called.
+func (bus *EventBus) Sealed() bool {
+	return bus.sealedTable() != nil
+}
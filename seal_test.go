@@ -0,0 +1,101 @@
+package EventBus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSeal(t *testing.T) {
+	bus := New().(*EventBus)
+	count, once := 0, 0
+	handler := func() { count++ }
+	bus.Subscribe("topic", handler)
+	bus.SubscribeOnce("once", func() { once++ })
+	bus.Seal()
+	if !bus.Sealed() {
+		t.Fail()
+	}
+	if bus.Subscribe("topic", handler) != ErrSealed || bus.Unsubscribe("topic", handler) != ErrSealed {
+		t.Fail()
+	}
+	bus.Publish("topic")
+	bus.Publish("once")
+	bus.Publish("once")
+	bus.Publish("nothing")
+	if count != 1 || once != 1 || bus.HasCallback("once") {
+		t.Fail()
+	}
+}
+
+func TestSealedConcurrentPublish(t *testing.T) {
+	bus := New().(*EventBus)
+	var lock sync.Mutex
+	count := 0
+	bus.SubscribeAsync("topic", func() {
+		lock.Lock()
+		count++
+		lock.Unlock()
+	}, true)
+	bus.Subscribe("topic", func() {
+		// sync handlers may publish on a sealed bus, the lock is not held
+		bus.Publish("nested")
+	})
+	bus.Seal()
+	bus.EnableTrace(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bus.Publish("topic")
+		}()
+	}
+	wg.Wait()
+	bus.WaitAsync()
+	if count != 10 || len(bus.Trace()) != 10 {
+		t.Fatal(count, len(bus.Trace()))
+	}
+}
+
+func TestSealedHelpers(t *testing.T) {
+	bus := New().(*EventBus)
+	component := &orderComponent{}
+	bus.RegisterHandlers(component)
+	bus.Seal()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := bus.WaitForEvent(ctx, "topic"); err != ErrSealed {
+		t.Fatal(err)
+	}
+	if bus.WaitUntil(ctx, "topic", func(args ...interface{}) bool { return true }) != ErrSealed {
+		t.Fail()
+	}
+	if bus.UnregisterHandlers(component) != ErrSealed {
+		t.Fail()
+	}
+	bus.Publish("order:cancelled")
+	if component.cancelled != 1 {
+		t.Fail()
+	}
+}
+
+func TestSealedPublishConcurrentWithClose(t *testing.T) {
+	bus := NewWithOptions(WithAsyncWorkers(2)).(*EventBus)
+	bus.SubscribeAsync("topic", func() {}, false)
+	bus.Seal()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bus.Publish("topic")
+			}
+		}()
+	}
+	bus.Close()
+	wg.Wait()
+	bus.WaitAsync()
+}
@@ -1,12 +1,14 @@
 package EventBus
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/rpc"
+	"os"
+	"sort"
 	"sync"
+	"time"
 )
 
 // SubscribeType - how the client intends to subscribe
@@ -24,6 +26,16 @@ const (
 	RegisterService = "ServerService.Register"
 )
 
+// Priority - lane remote events of a topic are sent through
+type Priority int
+
+const (
+	// PriorityNormal - events are sent in publish order
+	PriorityNormal Priority = iota
+	// PriorityHigh - events overtake queued normal priority events, e.g. cancellations
+	PriorityHigh
+)
+
 // SubscribeArg - object to hold subscribe arguments from remote event handlers
 type SubscribeArg struct {
 	ClientAddr    string
@@ -35,11 +47,22 @@ type SubscribeArg struct {
 
 // Server - object capable of being subscribed to by remote handlers
 type Server struct {
-	eventBus    Bus
-	address     string
-	path        string
-	subscribers map[string][]*SubscribeArg
-	service     *ServerService
+	eventBus      Bus
+	address       string
+	path          string
+	subscribers   map[string][]*SubscribeArg
+	service       *ServerService
+	lock          sync.Mutex
+	priorities    map[string]Priority
+	outboxes      map[string]*outbox
+	spoolDir      string
+	spoolSize     int64
+	spoolRetry    time.Duration
+	retention     int                     // events kept per topic for Resend, see SetRetention
+	retained      map[string][]*ClientArg // last events sent per topic, by sequence
+	blobs         BlobStore               // large payloads are sent by reference through it, see SetClaimCheck
+	blobThreshold int
+	incarnation   string // identifies this run of the server to clients, see Ping
 }
 
 // NewServer - create a new Server at the address and path
@@ -49,7 +72,11 @@ func NewServer(address, path string, eventBus Bus) *Server {
 	server.address = address
 	server.path = path
 	server.subscribers = make(map[string][]*SubscribeArg)
+	server.priorities = make(map[string]Priority)
+	server.outboxes = make(map[string]*outbox)
+	server.retained = make(map[string][]*ClientArg)
 	server.service = &ServerService{server, &sync.WaitGroup{}, false}
+	server.incarnation = TimeOrderedID()
 	return server
 }
 
@@ -58,22 +85,102 @@ func (server *Server) EventBus() Bus {
 	return server.eventBus
 }
 
-func (server *Server) rpcCallback(subscribeArg *SubscribeArg) func(args ...interface{}) {
-	return func(args ...interface{}) {
-		client, connErr := rpc.DialHTTPPath("tcp", subscribeArg.ClientAddr, subscribeArg.ClientPath)
-		defer client.Close()
-		if connErr != nil {
-			fmt.Errorf("dialing: %v", connErr)
+// SetTopicPriority - set the lane events of the topic are sent to remote clients through
+func (server *Server) SetTopicPriority(topic string, priority Priority) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.priorities[topic] = priority
+}
+
+// TopicPriority - returns the lane events of the topic are sent through
+func (server *Server) TopicPriority(topic string) Priority {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	return server.priorities[topic]
+}
+
+// EnableSpool - keep the events of unreachable clients in files of dir, up to maxSize bytes per
+// client, and forward them in order once the client is back, trying again every retry interval.
+// It applies to the clients registering afterwards.
+func (server *Server) EnableSpool(dir string, maxSize int64, retry time.Duration) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.spoolDir, server.spoolSize, server.spoolRetry = dir, maxSize, retry
+	return nil
+}
+
+// every client has a single outbox shared by its topics, so high priority events
+// of one topic can overtake queued events of the others
+func (server *Server) outbox(subscribeArg *SubscribeArg) (*outbox, error) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	key := subscribeArg.ClientAddr + subscribeArg.ClientPath
+	if box, ok := server.outboxes[key]; ok {
+		return box, nil
+	}
+	box := &outbox{client: key, send: func(event *remoteEvent) error {
+		return sendEvent(subscribeArg.ClientAddr, subscribeArg.ClientPath, event)
+	}, onChange: func(status BridgeStatus) {
+		server.eventBus.Publish(BridgeStatusTopic, status)
+	}}
+	if server.spoolDir != "" {
+		sp, err := openSpool(server.spoolDir, key, server.spoolSize)
+		if err != nil {
+			return nil, err
 		}
+		box.spool, box.retry = sp, server.spoolRetry
+	}
+	server.outboxes[key] = box
+	if box.spool != nil && box.spool.count > 0 {
+		box.resume()
+	}
+	return box, nil
+}
+
+// BridgeStatus - returns the status of the connection to every registered client, sorted by client
+func (server *Server) BridgeStatus() []BridgeStatus {
+	server.lock.Lock()
+	boxes := make([]*outbox, 0, len(server.outboxes))
+	for _, box := range server.outboxes {
+		boxes = append(boxes, box)
+	}
+	server.lock.Unlock()
+	statuses := make([]BridgeStatus, 0, len(boxes))
+	for _, box := range boxes {
+		statuses = append(statuses, box.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Client < statuses[j].Client })
+	return statuses
+}
+
+func sendEvent(clientAddr, clientPath string, event *remoteEvent) error {
+	client, err := rpc.DialHTTPPath("tcp", clientAddr, clientPath)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var reply bool
+	return client.Call(event.ServiceMethod, event.Arg, &reply)
+}
+
+func (server *Server) rpcCallback(subscribeArg *SubscribeArg) (func(meta EventMeta, args ...interface{}), error) {
+	box, err := server.outbox(subscribeArg)
+	if err != nil {
+		return nil, err
+	}
+	return func(meta EventMeta, args ...interface{}) {
 		clientArg := new(ClientArg)
 		clientArg.Topic = subscribeArg.Topic
 		clientArg.Args = args
-		var reply bool
-		err := client.Call(subscribeArg.ServiceMethod, clientArg, &reply)
-		if err != nil {
-			fmt.Errorf("dialing: %v", err)
-		}
-	}
+		clientArg.Seq = meta.Seq
+		clientArg.Headers = meta.Headers
+		server.claimCheck(clientArg)
+		server.retain(clientArg)
+		box.push(server.TopicPriority(subscribeArg.Topic), &remoteEvent{subscribeArg.ServiceMethod, clientArg, time.Now()})
+	}, nil
 }
 
 // HasClientSubscribed - True if a client subscribed to this server with the same topic
@@ -105,7 +212,7 @@ func (server *Server) Start() error {
 		service.wg.Add(1)
 		go http.Serve(l, nil)
 	} else {
-		err = errors.New("Server bus already started")
+		err = fmt.Errorf("Server bus %w", ErrAlreadyStarted)
 	}
 	return err
 }
@@ -130,24 +237,39 @@ type ServerService struct {
 // for a remote subscribe - a given client address only needs to subscribe once
 // event will be republished in local event bus
 func (service *ServerService) Register(arg *SubscribeArg, success *bool) error {
-	subscribers := service.server.subscribers
-	if !service.server.HasClientSubscribed(arg) {
-		rpcCallback := service.server.rpcCallback(arg)
+	server := service.server
+	server.lock.Lock()
+	subscribed := server.HasClientSubscribed(arg)
+	if !subscribed {
+		server.subscribers[arg.Topic] = append(server.subscribers[arg.Topic], arg)
+	}
+	server.lock.Unlock()
+	if !subscribed {
+		rpcCallback, err := server.rpcCallback(arg)
+		if err != nil {
+			server.lock.Lock()
+			server.forget(arg)
+			server.lock.Unlock()
+			return err
+		}
 		switch arg.SubscribeType {
 		case Subscribe:
-			service.server.eventBus.Subscribe(arg.Topic, rpcCallback)
+			server.eventBus.Subscribe(arg.Topic, rpcCallback)
 		case SubscribeOnce:
-			service.server.eventBus.SubscribeOnce(arg.Topic, rpcCallback)
-		}
-		var topicSubscribers []*SubscribeArg
-		if _, ok := subscribers[arg.Topic]; ok {
-			topicSubscribers = []*SubscribeArg{arg}
-		} else {
-			topicSubscribers = subscribers[arg.Topic]
-			topicSubscribers = append(topicSubscribers, arg)
+			server.eventBus.SubscribeOnce(arg.Topic, rpcCallback)
 		}
-		subscribers[arg.Topic] = topicSubscribers
 	}
 	*success = true
 	return nil
 }
+
+// forget removes a registration which failed, the lock must be held
+func (server *Server) forget(arg *SubscribeArg) {
+	subscribers := server.subscribers[arg.Topic]
+	for i, subscriber := range subscribers {
+		if *subscriber == *arg {
+			server.subscribers[arg.Topic] = append(subscribers[:i:i], subscribers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package EventBus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ShadowStats - outcome of the deliveries to a shadow handler
+type ShadowStats struct {
+	Topic        string
+	Handler      string
+	Deliveries   uint64
+	Panics       uint64
+	LastPanic    string
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// shadow - handler receiving copies of the events of a topic on the side
+type shadow struct {
+	bus     *EventBus
+	handler *eventHandler
+	lock    sync.Mutex
+	stats   ShadowStats
+}
+
+// SubscribeShadow subscribes fn to a topic in shadow mode, to try a new handler against real
+// traffic: it runs on its own goroutine, its panics are recovered and, like its latency, only
+// recorded in ShadowStats. Shadow deliveries are not traced and WaitAsync does not wait for them.
+// Returns error if `fn` is not a function.
+func (bus *EventBus) SubscribeShadow(topic string, fn interface{}) error {
+	_, err := bus.subscribeShadow(topic, fn)
+	return err
+}
+
+func (bus *EventBus) subscribeShadow(topic string, fn interface{}) (*Subscription, error) {
+	if err := checkFunc(fn); err != nil {
+		return nil, err
+	}
+	s := &shadow{bus: bus, handler: newEventHandler(fn, false, false, false)}
+	s.stats.Topic, s.stats.Handler = topic, s.handler.name()
+	sub, err := bus.subscribe(topic, s.mirror, newEventHandler(s.mirror, false, false, false))
+	if err != nil {
+		return nil, err
+	}
+	bus.lock.Lock()
+	bus.shadows = append(bus.shadows, s)
+	bus.lock.Unlock()
+	return sub, nil
+}
+
+func (s *shadow) mirror(ctx context.Context, meta EventMeta, args ...interface{}) {
+	go s.run(&envelope{ctx: ctx, meta: meta, args: args})
+}
+
+func (s *shadow) run(env *envelope) {
+	started := time.Now()
+	defer func() {
+		recovered := recover()
+		latency := time.Since(started)
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.stats.Deliveries++
+		s.stats.TotalLatency += latency
+		if latency > s.stats.MaxLatency {
+			s.stats.MaxLatency = latency
+		}
+		if recovered != nil {
+			s.stats.Panics++
+			s.stats.LastPanic = fmt.Sprint(recovered)
+		}
+	}()
+	s.bus.doPublish(s.handler, nil, env)
+}
+
+// ShadowStats returns the statistics of every shadow handler, in subscription order.
+func (bus *EventBus) ShadowStats() []ShadowStats {
+	bus.lock.Lock()
+	shadows := append([]*shadow(nil), bus.shadows...)
+	bus.lock.Unlock()
+	stats := make([]ShadowStats, 0, len(shadows))
+	for _, s := range shadows {
+		s.lock.Lock()
+		stats = append(stats, s.stats)
+		s.lock.Unlock()
+	}
+	return stats
+}
@@ -0,0 +1,33 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeShadow(t *testing.T) {
+	bus := New().(*EventBus)
+	primary := 0
+	bus.Subscribe("topic", func(n int) { primary++ })
+	if err := bus.SubscribeShadow("topic", func(n int) {
+		if n == 2 {
+			panic("candidate failed")
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic", 1)
+	bus.Publish("topic", 2)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && (len(bus.ShadowStats()) != 1 || bus.ShadowStats()[0].Deliveries != 2) {
+		time.Sleep(time.Millisecond)
+	}
+	stats := bus.ShadowStats()
+	if primary != 2 || len(stats) != 1 || stats[0].Deliveries != 2 || stats[0].Panics != 1 ||
+		stats[0].LastPanic != "candidate failed" || stats[0].Topic != "topic" {
+		t.Fatal(stats)
+	}
+	if bus.SubscribeShadow("topic", "String") == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,144 @@
+//go:build go1.21
+// +build go1.21
+
+package EventBus
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// LoggerKey - attribute naming the logger of a record, e.g. set by slog.New(handler).With(LoggerKey, "billing")
+const LoggerKey = "logger"
+
+// LogRecord - log record published by a LogHandler
+type LogRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Logger  string                 // value of the LoggerKey attribute, empty when unnamed
+	Attrs   map[string]interface{} // attributes of the record and its logger, keys qualified by their groups as "group.key"
+}
+
+// LogLevelTopic publishes records to "log:" followed by their lower case level, e.g. "log:error"
+func LogLevelTopic(record LogRecord) string {
+	return "log:" + strings.ToLower(record.Level.String())
+}
+
+// LoggerTopic publishes records to "log:" followed by their logger name and level, e.g.
+// "log:billing:error", and the records of unnamed loggers as LogLevelTopic
+func LoggerTopic(record LogRecord) string {
+	if record.Logger == "" {
+		return LogLevelTopic(record)
+	}
+	return "log:" + record.Logger + ":" + strings.ToLower(record.Level.String())
+}
+
+// LogHandler - slog.Handler publishing the records it handles as LogRecord arguments
+type LogHandler struct {
+	bus    *EventBus
+	level  slog.Leveler
+	topic  func(record LogRecord) string
+	attrs  map[string]interface{} // added by WithAttrs, never modified once set
+	logger string
+	group  string // prefix of the keys of the attributes added next, see WithGroup
+}
+
+// LogHandlerOption - setting of a handler created by NewLogHandler
+type LogHandlerOption func(handler *LogHandler)
+
+// WithLogLevel publishes only the records at level or above, slog.LevelInfo by default
+func WithLogLevel(level slog.Leveler) LogHandlerOption {
+	return func(handler *LogHandler) {
+		handler.level = level
+	}
+}
+
+// WithLogTopic publishes every record to the topic returned by topic, LogLevelTopic by default
+func WithLogTopic(topic func(record LogRecord) string) LogHandlerOption {
+	return func(handler *LogHandler) {
+		handler.topic = topic
+	}
+}
+
+// NewLogHandler returns an slog.Handler publishing log records to the bus, so components can
+// subscribe to error logs and raise alerts or remediation events in-process. Records are
+// published synchronously, sync and async handlers may log through it, but a handler of the log
+// topics logging at its own level publishes to itself again.
+func (bus *EventBus) NewLogHandler(opts ...LogHandlerOption) *LogHandler {
+	handler := &LogHandler{bus: bus, level: slog.LevelInfo, topic: LogLevelTopic}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler
+}
+
+// Enabled reports whether records of level are published
+func (handler *LogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= handler.level.Level()
+}
+
+// Handle publishes the record
+func (handler *LogHandler) Handle(_ context.Context, r slog.Record) error {
+	record := LogRecord{Time: r.Time, Level: r.Level, Message: r.Message, Logger: handler.logger}
+	record.Attrs = make(map[string]interface{}, len(handler.attrs)+r.NumAttrs())
+	for key, value := range handler.attrs {
+		record.Attrs[key] = value
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		addLogAttr(&record, record.Attrs, handler.group, attr)
+		return true
+	})
+	handler.bus.Publish(handler.topic(record), record)
+	return nil
+}
+
+// WithAttrs returns a handler adding attrs to every record
+func (handler *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	copied := *handler
+	copied.attrs = make(map[string]interface{}, len(handler.attrs)+len(attrs))
+	for key, value := range handler.attrs {
+		copied.attrs[key] = value
+	}
+	record := LogRecord{Logger: handler.logger}
+	for _, attr := range attrs {
+		addLogAttr(&record, copied.attrs, handler.group, attr)
+	}
+	copied.logger = record.Logger
+	return &copied
+}
+
+// WithGroup returns a handler qualifying the keys of the attributes added next by name
+func (handler *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+	copied := *handler
+	copied.group = handler.group + name + "."
+	return &copied
+}
+
+// addLogAttr resolves attr into attrs, flattening groups. A top level LoggerKey attribute names the
+// logger of the record instead.
+func addLogAttr(record *LogRecord, attrs map[string]interface{}, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range value.Group() {
+			addLogAttr(record, attrs, prefix, member)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+	if prefix == "" && attr.Key == LoggerKey {
+		record.Logger = value.String()
+		return
+	}
+	attrs[prefix+attr.Key] = value.Any()
+}
@@ -0,0 +1,47 @@
+//go:build go1.21
+// +build go1.21
+
+package EventBus
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestLogHandler(t *testing.T) {
+	bus := New().(*EventBus)
+	var errors []LogRecord
+	bus.Subscribe("log:error", func(record LogRecord) { errors = append(errors, record) })
+	var infos int
+	bus.Subscribe("log:info", func(record LogRecord) { infos++ })
+	logger := slog.New(bus.NewLogHandler(WithLogLevel(slog.LevelInfo)))
+
+	logger.Debug("dropped")
+	logger.Info("started", "port", 8080)
+	logger.With(LoggerKey, "billing").WithGroup("invoice").Error("charge failed", "id", 42, slog.Group("card", "last4", "4242"))
+	if infos != 1 || len(errors) != 1 {
+		t.Fatal(infos, errors)
+	}
+	record := errors[0]
+	if record.Message != "charge failed" || record.Logger != "billing" || record.Level != slog.LevelError {
+		t.Fatal(record)
+	}
+	if record.Attrs["invoice.id"] != int64(42) || record.Attrs["invoice.card.last4"] != "4242" || len(record.Attrs) != 2 {
+		t.Fatal(record.Attrs)
+	}
+}
+
+func TestLoggerTopic(t *testing.T) {
+	bus := New().(*EventBus)
+	var topics []string
+	for _, topic := range []string{"log:billing:warn", "log:warn"} {
+		topic := topic
+		bus.Subscribe(topic, func(LogRecord) { topics = append(topics, topic) })
+	}
+	logger := slog.New(bus.NewLogHandler(WithLogTopic(LoggerTopic)))
+	logger.With(LoggerKey, "billing").Warn("slow")
+	logger.Warn("slow")
+	if len(topics) != 2 || topics[0] != "log:billing:warn" || topics[1] != "log:warn" {
+		t.Fatal(topics)
+	}
+}
@@ -0,0 +1,80 @@
+package EventBus
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+// SplitVariant - handler implementation receiving a share of the events of a split subscription
+type SplitVariant struct {
+	Weight int // relative share of the events, variants weighing zero or less receive none
+	Fn     interface{}
+}
+
+// splitter - router delivering every event to one variant picked by weight
+type splitter struct {
+	bus      *EventBus
+	names    []string
+	handlers []*eventHandler
+	weights  []int
+	total    int
+	key      func(args []interface{}) string
+}
+
+// SubscribeSplit subscribes to a topic with several variants of a handler, each event being
+// delivered to exactly one of them according to their weights, e.g. to canary a new
+// implementation against the old one. With a key function, events of the same key always go
+// to the same variant. The variants run synchronously, like handlers made with Subscribe, and
+// their errors and panics are reported like theirs, see HandlerFailedTopic and SetPanicHandler.
+// Returns error if a variant is not a function or no variant has a positive weight.
+func (bus *EventBus) SubscribeSplit(topic string, variants map[string]SplitVariant, key func(args []interface{}) string) error {
+	_, err := bus.subscribeSplit(topic, variants, key)
+	return err
+}
+
+func (bus *EventBus) subscribeSplit(topic string, variants map[string]SplitVariant, key func(args []interface{}) string) (*Subscription, error) {
+	s := &splitter{bus: bus, key: key}
+	for name := range variants {
+		s.names = append(s.names, name)
+	}
+	sort.Strings(s.names)
+	for _, name := range s.names {
+		variant := variants[name]
+		if err := checkFunc(variant.Fn); err != nil {
+			return nil, fmt.Errorf("variant %s: %w", name, err)
+		}
+		weight := variant.Weight
+		if weight < 0 {
+			weight = 0
+		}
+		s.handlers = append(s.handlers, newEventHandler(variant.Fn, false, false, false))
+		s.weights = append(s.weights, weight)
+		s.total += weight
+	}
+	if s.total == 0 {
+		return nil, fmt.Errorf("topic %s: no split variant has a positive weight", topic)
+	}
+	return bus.subscribe(topic, s.route, newEventHandler(s.route, false, false, false))
+}
+
+func (s *splitter) route(ctx context.Context, meta EventMeta, args ...interface{}) {
+	var n int
+	if s.key != nil {
+		hash := fnv.New32a()
+		hash.Write([]byte(s.key(args)))
+		n = int(hash.Sum32() % uint32(s.total))
+	} else {
+		n = rand.Intn(s.total)
+	}
+	for i, weight := range s.weights {
+		if n < weight {
+			env := &envelope{ctx: ctx, meta: meta, args: args}
+			s.bus.report(s.bus.doPublishRecovering(s.handlers[i], nil, env))
+			return
+		}
+		n -= weight
+	}
+}
@@ -0,0 +1,73 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSubscribeSplit(t *testing.T) {
+	bus := New().(*EventBus)
+	counts := map[string]int{}
+	err := bus.SubscribeSplit("topic", map[string]SplitVariant{
+		"old":      {90, func(n int) { counts["old"]++ }},
+		"new":      {10, func(meta EventMeta, n int) { counts[meta.Topic]++ }},
+		"disabled": {0, func(n int) { counts["disabled"]++ }},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		bus.Publish("topic", i)
+	}
+	if counts["old"]+counts["topic"] != 1000 || counts["disabled"] != 0 || counts["old"] < 800 || counts["topic"] < 50 {
+		t.Fatal(counts)
+	}
+}
+
+func TestSubscribeSplitSticky(t *testing.T) {
+	bus := New().(*EventBus)
+	seen := map[string]string{}
+	record := func(variant string) func(user string) {
+		return func(user string) {
+			if previous, ok := seen[user]; ok && previous != variant {
+				t.Errorf("%s moved from %s to %s", user, previous, variant)
+			}
+			seen[user] = variant
+		}
+	}
+	bus.SubscribeSplit("topic", map[string]SplitVariant{
+		"a": {1, record("a")},
+		"b": {1, record("b")},
+	}, func(args []interface{}) string { return args[0].(string) })
+	for i := 0; i < 10; i++ {
+		for _, user := range []string{"ann", "bob", "cid", "dan"} {
+			bus.Publish("topic", user)
+		}
+	}
+	if bus.SubscribeSplit("topic", map[string]SplitVariant{"a": {0, record("a")}}, nil) == nil ||
+		bus.SubscribeSplit("topic", map[string]SplitVariant{"a": {1, "String"}}, nil) == nil {
+		t.Fail()
+	}
+}
+
+func TestSubscribeSplitFailure(t *testing.T) {
+	bus := NewWithOptions(WithFailurePolicy(ContinueOnFailure)).(*EventBus)
+	var failures []HandlerFailure
+	bus.Subscribe(HandlerFailedTopic, func(failure HandlerFailure) { failures = append(failures, failure) })
+	var panics []interface{}
+	bus.SetPanicHandler(func(topic string, handler interface{}, recovered interface{}) { panics = append(panics, recovered) })
+	bus.SubscribeSplit("topic", map[string]SplitVariant{
+		"failing": {1, func(n int) error {
+			if n == 1 {
+				panic("boom")
+			}
+			return errors.New("rejected")
+		}},
+	}, nil)
+	bus.Publish("topic", 1)
+	bus.Publish("topic", 2)
+	if len(panics) != 1 || panics[0] != "boom" || len(failures) != 2 || failures[0].Topic != "topic" ||
+		failures[0].Recovered != "boom" || failures[1].Err.Error() != "rejected" {
+		t.Fatal(panics, failures)
+	}
+}
@@ -0,0 +1,125 @@
+package EventBus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// ErrSpoolFull - the spool of an unreachable client reached its maximum size, newer events are dropped
+var ErrSpoolFull = errors.New("spool is full")
+
+// remoteEvent - event queued for a remote client together with the service method receiving it
+type remoteEvent struct {
+	ServiceMethod string
+	Arg           *ClientArg
+	Queued        time.Time
+}
+
+// spool - file keeping the events of an unreachable client, in order, until it is back.
+// Every record is a length prefixed gob encoding, so arguments follow the same registration
+// rules as the rpc transport.
+type spool struct {
+	path    string
+	maxSize int64
+	size    int64
+	count   int
+	oldest  time.Time // queue time of the first spooled event
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// openSpool opens the spool of a client in dir, picking up the events left by a previous run
+func openSpool(dir, client string, maxSize int64) (*spool, error) {
+	sp := &spool{path: filepath.Join(dir, unsafeFileChars.ReplaceAllString(client, "_")+".spool"), maxSize: maxSize}
+	events, err := sp.load()
+	if err != nil {
+		return nil, err
+	}
+	sp.count = len(events)
+	if len(events) > 0 {
+		sp.oldest = events[0].Queued
+	}
+	if info, err := os.Stat(sp.path); err == nil {
+		sp.size = info.Size()
+	}
+	return sp, nil
+}
+
+// load reads the spooled events, oldest first
+func (sp *spool) load() ([]*remoteEvent, error) {
+	data, err := ioutil.ReadFile(sp.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var events []*remoteEvent
+	for len(data) > 0 {
+		if len(data) < 4 || uint32(len(data)-4) < binary.BigEndian.Uint32(data) {
+			return events, errors.New("truncated spool record in " + sp.path)
+		}
+		end := 4 + binary.BigEndian.Uint32(data)
+		event := new(remoteEvent)
+		if err := gob.NewDecoder(bytes.NewReader(data[4:end])).Decode(event); err != nil {
+			return events, err
+		}
+		events = append(events, event)
+		data = data[end:]
+	}
+	return events, nil
+}
+
+// append adds events to the spool, those which do not fit are dropped and ErrSpoolFull is returned
+func (sp *spool) append(events ...*remoteEvent) error {
+	var buf bytes.Buffer
+	var record bytes.Buffer
+	stored := 0
+	var err error
+	for _, event := range events {
+		record.Reset()
+		record.Write(make([]byte, 4))
+		if err = gob.NewEncoder(&record).Encode(event); err != nil {
+			break
+		}
+		if sp.size+int64(buf.Len()+record.Len()) > sp.maxSize {
+			err = ErrSpoolFull
+			break
+		}
+		binary.BigEndian.PutUint32(record.Bytes(), uint32(record.Len()-4))
+		if sp.count == 0 && stored == 0 {
+			sp.oldest = event.Queued
+		}
+		buf.Write(record.Bytes())
+		stored++
+	}
+	if buf.Len() == 0 {
+		return err
+	}
+	file, openErr := os.OpenFile(sp.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if openErr != nil {
+		return openErr
+	}
+	defer file.Close()
+	if _, writeErr := file.Write(buf.Bytes()); writeErr != nil {
+		return writeErr
+	}
+	sp.size += int64(buf.Len())
+	sp.count += stored
+	return err
+}
+
+// reset replaces the spool contents with events
+func (sp *spool) reset(events []*remoteEvent) error {
+	if err := os.Remove(sp.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	sp.size, sp.count = 0, 0
+	return sp.append(events...)
+}
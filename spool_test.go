@@ -0,0 +1,80 @@
+package EventBus
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSpoolForwardsInOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sp, err := openSpool(dir, "localhost:2015/_client_bus_", 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lock sync.Mutex
+	reachable := false
+	var received []interface{}
+	done := make(chan struct{})
+	box := &outbox{spool: sp, retry: time.Millisecond, send: func(event *remoteEvent) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if !reachable {
+			return errors.New("unreachable")
+		}
+		received = append(received, event.Arg.Args[0])
+		if len(received) == 3 {
+			close(done)
+		}
+		return nil
+	}}
+	for i := 1; i <= 3; i++ {
+		box.push(PriorityNormal, &remoteEvent{PublishService, &ClientArg{Args: []interface{}{i}, Topic: "topic"}, time.Now()})
+	}
+	time.Sleep(10 * time.Millisecond)
+	lock.Lock()
+	reachable = true
+	lock.Unlock()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("spooled events were not forwarded")
+	}
+	if received[0] != 1 || received[1] != 2 || received[2] != 3 {
+		t.Fatal(received)
+	}
+
+	reopened, _ := openSpool(dir, "localhost:2015/_client_bus_", 1<<20)
+	if reopened.count != 0 {
+		t.Fail()
+	}
+}
+
+func TestSpoolMaxSize(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "eventbus")
+	defer os.RemoveAll(dir)
+	sp, _ := openSpool(dir, "client", 400)
+	event := &remoteEvent{PublishService, &ClientArg{Args: []interface{}{"payload"}, Topic: "topic"}, time.Now()}
+	if sp.append(event) != nil {
+		t.Fatal("first event should fit")
+	}
+	if sp.append(event, event, event) != ErrSpoolFull {
+		t.Fail()
+	}
+	reopened, _ := openSpool(dir, "client", 400)
+	if reopened.count != sp.count || reopened.size != sp.size || sp.size > 400 {
+		t.Fail()
+	}
+	events, err := reopened.load()
+	if err != nil || len(events) != sp.count || events[0].Arg.Args[0] != "payload" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,79 @@
+package EventBus
+
+import (
+	"reflect"
+	"sync"
+)
+
+// topicState - current value of a state topic, the lock is held while a change is published
+// so handlers observe the changes of a topic in order
+type topicState struct {
+	lock  sync.Mutex
+	value interface{}
+	set   bool
+	size  int64 // approximate bytes of value, see WithMemoryAccounting
+}
+
+func (bus *EventBus) stateOf(topic string) *topicState {
+	if state, ok := bus.states.Load(topic); ok {
+		return state.(*topicState)
+	}
+	state, _ := bus.states.LoadOrStore(topic, &topicState{})
+	return state.(*topicState)
+}
+
+// SetState makes value the current state of the topic and publishes it, unless it is
+// reflect.DeepEqual to the current one. It reports whether the value was published.
+// Handlers of the topic must not set its state themselves, that dead locks.
+func (bus *EventBus) SetState(topic string, value interface{}) bool {
+	return bus.SetStateFunc(topic, value, reflect.DeepEqual)
+}
+
+// SetStateFunc is SetState telling changes apart with equal instead of reflect.DeepEqual.
+func (bus *EventBus) SetStateFunc(topic string, value interface{}, equal func(current, value interface{}) bool) bool {
+	state := bus.stateOf(topic)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	if state.set && equal(state.value, value) {
+		return false
+	}
+	state.value, state.set = value, true
+	size := bus.memory.sizeOf(value)
+	bus.memory.add(memoryStates, size-state.size)
+	state.size = size
+	bus.Publish(topic, value)
+	bus.watchers.notify(topic, value)
+	return true
+}
+
+// State returns the current state of the topic, false when it was never set.
+func (bus *EventBus) State(topic string) (interface{}, bool) {
+	state, ok := bus.states.Load(topic)
+	if !ok {
+		return nil, false
+	}
+	s := state.(*topicState)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.value, s.set
+}
+
+// SubscribeState subscribes to a state topic, fn is called with the current state right away
+// when there is one and then with every change, in order.
+// Returns error if `fn` is not a function, ErrSealed on a sealed bus.
+func (bus *EventBus) SubscribeState(topic string, fn interface{}) error {
+	_, err := bus.subscribeState(topic, fn)
+	return err
+}
+
+func (bus *EventBus) subscribeState(topic string, fn interface{}) (*Subscription, error) {
+	state := bus.stateOf(topic)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	sub, err := bus.subscribe(topic, fn, newEventHandler(fn, false, false, false))
+	if err != nil || !state.set {
+		return sub, err
+	}
+	bus.doPublish(sub.handler, nil, newEnvelope(topic, []interface{}{state.value}))
+	return sub, nil
+}
@@ -0,0 +1,60 @@
+package EventBus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetState(t *testing.T) {
+	bus := New().(*EventBus)
+	var published []interface{}
+	bus.Subscribe("config", func(value interface{}) { published = append(published, value) })
+	if !bus.SetState("config", map[string]int{"workers": 4}) {
+		t.Fail()
+	}
+	if bus.SetState("config", map[string]int{"workers": 4}) {
+		t.Fail()
+	}
+	bus.SetState("config", map[string]int{"workers": 8})
+	if len(published) != 2 {
+		t.Fatal(published)
+	}
+	if value, ok := bus.State("config"); !ok || value.(map[string]int)["workers"] != 8 {
+		t.Fatal(value)
+	}
+	if _, ok := bus.State("unknown"); ok {
+		t.Fail()
+	}
+}
+
+func TestSetStateFunc(t *testing.T) {
+	bus := New().(*EventBus)
+	count := 0
+	bus.Subscribe("mode", func(mode string) { count++ })
+	equalFold := func(current, value interface{}) bool { return strings.EqualFold(current.(string), value.(string)) }
+	bus.SetStateFunc("mode", "Active", equalFold)
+	bus.SetStateFunc("mode", "ACTIVE", equalFold)
+	bus.SetStateFunc("mode", "idle", equalFold)
+	if count != 2 {
+		t.Fatal(count)
+	}
+}
+
+func TestSubscribeState(t *testing.T) {
+	bus := New().(*EventBus)
+	var received []int
+	handler := func(n int) { received = append(received, n) }
+	if err := bus.SubscribeState("level", handler); err != nil {
+		t.Fatal(err)
+	}
+	bus.SetState("level", 1)
+	var late []int
+	bus.SubscribeState("level", func(n int) { late = append(late, n) })
+	bus.SetState("level", 2)
+	if len(received) != 2 || received[1] != 2 || len(late) != 2 || late[0] != 1 || late[1] != 2 {
+		t.Fatal(received, late)
+	}
+	if bus.SubscribeState("level", 1) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,338 @@
+package EventBus
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TopicStats - publishing statistics of a topic since its creation or last ResetStats
+type TopicStats struct {
+	Topic     string
+	Published uint64    // events published
+	Delivered uint64    // deliveries to handlers
+	Since     time.Time // when counting began
+	// published events per second, exponentially weighted over the last 1, 5 and 15 minutes
+	Rate1m  float64
+	Rate5m  float64
+	Rate15m float64
+	// calls of the handlers currently subscribed, and their totals
+	Handlers []HandlerStats
+	Calls    uint64
+	Errors   uint64
+	Panics   uint64
+	Latency  time.Duration // average duration of the calls
+}
+
+// HandlerStats - calls of a handler subscribed to a topic, since its subscription or the last
+// ResetStats of the topic
+type HandlerStats struct {
+	Handler   string
+	Calls     uint64
+	Errors    uint64 // calls returning a non-nil error
+	Panics    uint64
+	Latency   time.Duration // average duration of the calls
+	Depth     int           // deliveries waiting in the queue of a transactional or ordered async handler
+	HighWater int           // most deliveries waiting at once
+	Lag       time.Duration // age of the oldest delivery waiting
+}
+
+// handlerCounters - running call statistics of a handler, updated atomically
+type handlerCounters struct {
+	calls   uint64
+	errors  uint64
+	panics  uint64
+	elapsed int64 // total duration of the calls, in nanoseconds
+}
+
+// record counts a call of the handler started at started
+func (counters *handlerCounters) record(started time.Time, failed, panicked bool) {
+	atomic.AddInt64(&counters.elapsed, int64(time.Since(started)))
+	atomic.AddUint64(&counters.calls, 1)
+	if failed {
+		atomic.AddUint64(&counters.errors, 1)
+	}
+	if panicked {
+		atomic.AddUint64(&counters.panics, 1)
+	}
+}
+
+func (counters *handlerCounters) reset() {
+	atomic.StoreUint64(&counters.calls, 0)
+	atomic.StoreUint64(&counters.errors, 0)
+	atomic.StoreUint64(&counters.panics, 0)
+	atomic.StoreInt64(&counters.elapsed, 0)
+}
+
+// average returns the average duration of calls lasting elapsed nanoseconds in total
+func average(elapsed int64, calls uint64) time.Duration {
+	if calls == 0 {
+		return 0
+	}
+	return time.Duration(elapsed / int64(calls))
+}
+
+// rateWindows - time constants of the published rates, in seconds
+var rateWindows = [3]float64{60, 300, 900}
+
+// topicCounters - running statistics of a topic. Rates decay lazily, when an event is counted
+// or the statistics are read, so nothing runs in the background.
+type topicCounters struct {
+	lock      sync.Mutex // publishes on different topics count without contending
+	published uint64
+	delivered uint64
+	since     time.Time
+	last      time.Time
+	rates     [3]float64
+}
+
+func (counters *topicCounters) decayed(now time.Time) [3]float64 {
+	var rates [3]float64
+	elapsed := now.Sub(counters.last).Seconds()
+	for i, window := range rateWindows {
+		rates[i] = counters.rates[i] * math.Exp(-elapsed/window)
+	}
+	return rates
+}
+
+func (counters *topicCounters) count(now time.Time, deliveries int) {
+	counters.rates = counters.decayed(now)
+	for i, window := range rateWindows {
+		counters.rates[i] += 1 / window
+	}
+	counters.last = now
+	counters.published++
+	counters.delivered += uint64(deliveries)
+}
+
+// topicStatsSet - statistics of every topic published to
+type topicStatsSet struct {
+	lock   sync.RWMutex // write locked to add or remove topics only
+	topics map[string]*topicCounters
+}
+
+// record counts an event published to a topic with its number of deliveries
+func (set *topicStatsSet) record(topic string, deliveries int) {
+	now := time.Now()
+	set.lock.RLock()
+	counters, ok := set.topics[topic]
+	set.lock.RUnlock()
+	if !ok {
+		set.lock.Lock()
+		if counters, ok = set.topics[topic]; !ok {
+			if set.topics == nil {
+				set.topics = make(map[string]*topicCounters)
+			}
+			counters = &topicCounters{since: now, last: now}
+			set.topics[topic] = counters
+		}
+		set.lock.Unlock()
+	}
+	counters.lock.Lock()
+	defer counters.lock.Unlock()
+	counters.count(now, deliveries)
+}
+
+// TopicStats returns the statistics of the topic, zero counters when nothing was published to it.
+func (bus *EventBus) TopicStats(topic string) TopicStats {
+	stats := TopicStats{Topic: topic}
+	bus.lock.Lock()
+	bus.handlerStats(&stats)
+	bus.lock.Unlock()
+	bus.publishStats(&stats, time.Now())
+	return stats
+}
+
+// Stats returns the statistics of every topic published to or having handlers, sorted by topic,
+// e.g. to find the slow handlers.
+func (bus *EventBus) Stats() []TopicStats {
+	bus.lock.Lock()
+	bus.stats.lock.Lock()
+	topics := make(map[string]bool, len(bus.handlers)+len(bus.stats.topics))
+	for topic := range bus.stats.topics {
+		topics[topic] = true
+	}
+	bus.stats.lock.Unlock()
+	for topic, handlers := range bus.handlers {
+		if len(handlers) > 0 {
+			topics[topic] = true
+		}
+	}
+	all := make([]TopicStats, 0, len(topics))
+	for topic := range topics {
+		stats := TopicStats{Topic: topic}
+		bus.handlerStats(&stats)
+		all = append(all, stats)
+	}
+	bus.lock.Unlock()
+	now := time.Now()
+	for i := range all {
+		bus.publishStats(&all[i], now)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Topic < all[j].Topic })
+	return all
+}
+
+// handlerStats fills in the call statistics of the handlers of the topic, the bus lock must be held
+func (bus *EventBus) handlerStats(stats *TopicStats) {
+	var elapsed int64
+	for _, handler := range bus.handlers[stats.Topic] {
+		counters := &handler.counters
+		calls, total := atomic.LoadUint64(&counters.calls), atomic.LoadInt64(&counters.elapsed)
+		handlerStats := HandlerStats{
+			Handler: handler.name(),
+			Calls:   calls,
+			Errors:  atomic.LoadUint64(&counters.errors),
+			Panics:  atomic.LoadUint64(&counters.panics),
+			Latency: average(total, calls),
+		}
+		queue := handler.queue.stats()
+		handlerStats.Depth, handlerStats.HighWater, handlerStats.Lag = queue.Depth, queue.HighWater, queue.Lag
+		stats.Handlers = append(stats.Handlers, handlerStats)
+		stats.Calls += handlerStats.Calls
+		stats.Errors += handlerStats.Errors
+		stats.Panics += handlerStats.Panics
+		elapsed += total
+	}
+	stats.Latency = average(elapsed, stats.Calls)
+}
+
+// publishStats fills in the publishing statistics of the topic
+func (bus *EventBus) publishStats(stats *TopicStats, now time.Time) {
+	bus.stats.lock.RLock()
+	counters, ok := bus.stats.topics[stats.Topic]
+	bus.stats.lock.RUnlock()
+	if !ok {
+		return
+	}
+	counters.lock.Lock()
+	defer counters.lock.Unlock()
+	rates := counters.decayed(now)
+	stats.Published, stats.Delivered, stats.Since = counters.published, counters.delivered, counters.since
+	stats.Rate1m, stats.Rate5m, stats.Rate15m = rates[0], rates[1], rates[2]
+}
+
+// ResetStats starts counting the statistics of the topic over, so dashboards can show current
+// behavior rather than aggregates since startup.
+func (bus *EventBus) ResetStats(topic string) {
+	bus.lock.Lock()
+	for _, handler := range bus.handlers[topic] {
+		handler.counters.reset()
+		handler.queue.resetHighWater()
+	}
+	bus.lock.Unlock()
+	bus.stats.lock.Lock()
+	defer bus.stats.lock.Unlock()
+	delete(bus.stats.topics, topic)
+}
+
+// Expvar returns Stats as an expvar variable, e.g. expvar.Publish("eventbus", bus.Expvar()) serves
+// them as JSON on /debug/vars.
+func (bus *EventBus) Expvar() expvar.Var {
+	return expvar.Func(func() interface{} { return bus.Stats() })
+}
+
+// topicFamilies - metrics of WriteMetrics labelled by topic
+var topicFamilies = []struct {
+	name, help string
+	value      func(stats TopicStats) uint64
+}{
+	{"published_total", "Events published to the topic.", func(stats TopicStats) uint64 { return stats.Published }},
+	{"delivered_total", "Deliveries of the events of the topic to handlers.", func(stats TopicStats) uint64 { return stats.Delivered }},
+}
+
+// handlerFamilies - metrics of WriteMetrics labelled by topic and handler
+var handlerFamilies = []struct {
+	name, kind, help string
+	value            func(stats HandlerStats) float64
+}{
+	{"handler_calls_total", "counter", "Calls of the handler.", func(stats HandlerStats) float64 { return float64(stats.Calls) }},
+	{"handler_errors_total", "counter", "Calls of the handler returning an error.", func(stats HandlerStats) float64 { return float64(stats.Errors) }},
+	{"handler_panics_total", "counter", "Calls of the handler panicking.", func(stats HandlerStats) float64 { return float64(stats.Panics) }},
+	{"handler_latency_seconds", "gauge", "Average duration of the calls of the handler.", func(stats HandlerStats) float64 { return stats.Latency.Seconds() }},
+	{"handler_queue_depth", "gauge", "Deliveries waiting in the queue of the handler.", func(stats HandlerStats) float64 { return float64(stats.Depth) }},
+	{"handler_queue_high_water", "gauge", "Most deliveries waiting at once in the queue of the handler.", func(stats HandlerStats) float64 { return float64(stats.HighWater) }},
+	{"handler_queue_lag_seconds", "gauge", "Age of the oldest delivery waiting in the queue of the handler.", func(stats HandlerStats) float64 { return stats.Lag.Seconds() }},
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteMetrics writes Stats in the Prometheus text exposition format, the metrics named
+// <namespace>_published_total, <namespace>_handler_calls_total and so on. Handlers subscribed to a
+// topic several times, e.g. closures of the same function, are reported together.
+func (bus *EventBus) WriteMetrics(w io.Writer, namespace string) error {
+	prefix := ""
+	if namespace != "" {
+		prefix = namespace + "_"
+	}
+	all := bus.Stats()
+	buf := bufio.NewWriter(w)
+	for _, family := range topicFamilies {
+		fmt.Fprintf(buf, "# HELP %s%s %s\n# TYPE %s%s counter\n", prefix, family.name, family.help, prefix, family.name)
+		for _, stats := range all {
+			fmt.Fprintf(buf, "%s%s{topic=\"%s\"} %d\n", prefix, family.name, labelEscaper.Replace(stats.Topic), family.value(stats))
+		}
+	}
+	merged := make([][]HandlerStats, len(all))
+	for i, stats := range all {
+		merged[i] = mergeHandlerStats(stats.Handlers)
+	}
+	for _, family := range handlerFamilies {
+		fmt.Fprintf(buf, "# HELP %s%s %s\n# TYPE %s%s %s\n", prefix, family.name, family.help, prefix, family.name, family.kind)
+		for i, stats := range all {
+			for _, handler := range merged[i] {
+				fmt.Fprintf(buf, "%s%s{topic=\"%s\",handler=\"%s\"} %s\n", prefix, family.name,
+					labelEscaper.Replace(stats.Topic), labelEscaper.Replace(handler.Handler),
+					strconv.FormatFloat(family.value(handler), 'g', -1, 64))
+			}
+		}
+	}
+	return buf.Flush()
+}
+
+// mergeHandlerStats sums the statistics of handlers of the same name, in order of first
+// subscription, their latency weighted by their calls
+func mergeHandlerStats(handlers []HandlerStats) []HandlerStats {
+	var merged []HandlerStats
+	index := make(map[string]int, len(handlers))
+	elapsed := make(map[string]time.Duration, len(handlers))
+	for _, handler := range handlers {
+		elapsed[handler.Handler] += handler.Latency * time.Duration(handler.Calls)
+		i, ok := index[handler.Handler]
+		if !ok {
+			index[handler.Handler] = len(merged)
+			merged = append(merged, handler)
+			continue
+		}
+		merged[i].Calls += handler.Calls
+		merged[i].Errors += handler.Errors
+		merged[i].Panics += handler.Panics
+		merged[i].Depth += handler.Depth
+		merged[i].HighWater += handler.HighWater
+		if handler.Lag > merged[i].Lag {
+			merged[i].Lag = handler.Lag
+		}
+	}
+	for i := range merged {
+		merged[i].Latency = average(int64(elapsed[merged[i].Handler]), merged[i].Calls)
+	}
+	return merged
+}
+
+// MetricsHandler returns an http.Handler serving WriteMetrics output, to be scraped by Prometheus
+// without a client library.
+func (bus *EventBus) MetricsHandler(namespace string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bus.WriteMetrics(w, namespace)
+	})
+}
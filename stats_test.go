@@ -0,0 +1,153 @@
+package EventBus
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTopicStats(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.Subscribe("topic", func() {})
+	bus.Subscribe("topic", func() {})
+	for i := 0; i < 3; i++ {
+		bus.Publish("topic")
+	}
+	bus.Publish("nobody")
+	stats := bus.TopicStats("topic")
+	if stats.Published != 3 || stats.Delivered != 6 || stats.Since.IsZero() {
+		t.Fatal(stats)
+	}
+	// three events just now weigh 3/60 events per second over a minute
+	if stats.Rate1m < 0.049 || stats.Rate1m > 0.05 || stats.Rate15m > stats.Rate5m || stats.Rate5m > stats.Rate1m {
+		t.Fatal(stats)
+	}
+	if nobody := bus.TopicStats("nobody"); nobody.Published != 1 || nobody.Delivered != 0 {
+		t.Fatal(nobody)
+	}
+
+	bus.ResetStats("topic")
+	if stats := bus.TopicStats("topic"); stats.Published != 0 || !stats.Since.IsZero() {
+		t.Fatal(stats)
+	}
+	bus.Publish("topic")
+	if stats := bus.TopicStats("topic"); stats.Published != 1 || time.Since(stats.Since) > time.Second {
+		t.Fatal(stats)
+	}
+}
+
+func TestTopicCountersDecay(t *testing.T) {
+	start := time.Now()
+	counters := &topicCounters{since: start, last: start}
+	counters.count(start, 1)
+	rates := counters.decayed(start.Add(time.Minute))
+	if rates[0] < 0.0061 || rates[0] > 0.0062 {
+		t.Fatal(rates)
+	}
+}
+
+func TestHandlerStats(t *testing.T) {
+	bus := NewWithOptions(WithFailurePolicy(ContinueOnFailure)).(*EventBus)
+	bus.Subscribe("order", func(id int) error {
+		time.Sleep(time.Millisecond)
+		if id == 2 {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+	bus.Subscribe("order", func(id int) {
+		if id == 3 {
+			panic("boom")
+		}
+	})
+	for id := 1; id <= 3; id++ {
+		bus.Publish("order", id)
+	}
+	bus.Subscribe("idle", func() {})
+
+	// failures are published to HandlerFailedTopic too
+	all := bus.Stats()
+	if len(all) != 3 || all[0].Topic != HandlerFailedTopic || all[1].Topic != "idle" || all[2].Topic != "order" {
+		t.Fatal(all)
+	}
+	stats := all[2]
+	if stats.Published != 3 || stats.Calls != 6 || stats.Errors != 1 || stats.Panics != 1 || len(stats.Handlers) != 2 {
+		t.Fatal(stats)
+	}
+	slow, panicky := stats.Handlers[0], stats.Handlers[1]
+	if slow.Calls != 3 || slow.Errors != 1 || slow.Latency < time.Millisecond || panicky.Panics != 1 {
+		t.Fatal(stats.Handlers)
+	}
+	if stats.Latency <= 0 || stats.Latency > slow.Latency {
+		t.Fatal(stats.Latency)
+	}
+
+	bus.ResetStats("order")
+	if stats := bus.TopicStats("order"); stats.Calls != 0 || stats.Handlers[0].Latency != 0 {
+		t.Fatal(stats)
+	}
+	var decoded []TopicStats
+	if err := json.Unmarshal([]byte(bus.Expvar().String()), &decoded); err != nil || len(decoded) != 3 {
+		t.Fatal(err, decoded)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	bus := NewWithOptions(WithFailurePolicy(ContinueOnFailure)).(*EventBus)
+	reject := func(id string) error { return errors.New("out of stock") }
+	bus.Subscribe("order:placed", reject)
+	bus.Subscribe("order:placed", reject)
+	bus.Publish("order:placed", "o-1")
+	bus.Publish("order:placed", "o-2")
+
+	recorder := httptest.NewRecorder()
+	bus.MetricsHandler("eventbus").ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	name := bus.handlers["order:placed"][0].name()
+	for _, line := range []string{
+		"# TYPE eventbus_published_total counter",
+		`eventbus_published_total{topic="order:placed"} 2`,
+		`eventbus_delivered_total{topic="order:placed"} 4`,
+		// both subscriptions of the same function are reported together
+		`eventbus_handler_calls_total{topic="order:placed",handler="` + name + `"} 4`,
+		`eventbus_handler_errors_total{topic="order:placed",handler="` + name + `"} 4`,
+		"# TYPE eventbus_handler_latency_seconds gauge",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatal(line, "\n", body)
+		}
+	}
+	if strings.Count(body, "eventbus_handler_calls_total{") != 1 {
+		t.Fatal(body)
+	}
+}
+
+func TestHandlerQueueStats(t *testing.T) {
+	bus := New().(*EventBus)
+	release := make(chan struct{})
+	bus.SubscribeAsync("topic", func(int) { <-release }, true)
+	for i := 0; i < 4; i++ {
+		bus.Publish("topic", i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	stats := bus.TopicStats("topic").Handlers[0]
+	// the first delivery is running, the others wait
+	if stats.Depth != 3 || stats.HighWater < 3 || stats.Lag < 20*time.Millisecond {
+		t.Fatal(stats)
+	}
+	var b strings.Builder
+	bus.WriteMetrics(&b, "eventbus")
+	name := bus.handlers["topic"][0].name()
+	if !strings.Contains(b.String(), `eventbus_handler_queue_depth{topic="topic",handler="`+name+`"} 3`+"\n") {
+		t.Fatal(b.String())
+	}
+	close(release)
+	bus.WaitAsync()
+	bus.ResetStats("topic")
+	if stats := bus.TopicStats("topic").Handlers[0]; stats.Depth != 0 || stats.HighWater != 0 || stats.Lag != 0 {
+		t.Fatal(stats)
+	}
+}
@@ -0,0 +1,76 @@
+package EventBus
+
+import (
+	"context"
+	"sync"
+)
+
+// stickyTopic - last event published to a topic by PublishSticky, the lock is held while it
+// is published or delivered to a new handler so every handler gets the events in order
+type stickyTopic struct {
+	lock sync.Mutex
+	args []interface{}
+	set  bool
+}
+
+// PublishSticky publishes args to topic and retains them, so every handler subscribed to the
+// topic later on, by any Subscribe method, receives them right away, like a BehaviorSubject.
+// Only the last sticky event of a topic is retained, until ClearSticky; handlers of entity
+// wildcards do not receive it. Its handlers may subscribe, unsubscribe and publish, but not
+// publish sticky events to the topic nor subscribe to it: those wait for the delivery in progress.
+func (bus *EventBus) PublishSticky(topic string, args ...interface{}) {
+	value, _ := bus.stickies.LoadOrStore(topic, &stickyTopic{})
+	sticky := value.(*stickyTopic)
+	sticky.lock.Lock()
+	defer sticky.lock.Unlock()
+	sticky.args, sticky.set = args, true
+	bus.Publish(topic, args...)
+}
+
+// ClearSticky forgets the sticky event of the topic, later subscribers no longer receive it
+func (bus *EventBus) ClearSticky(topic string) {
+	if value, ok := bus.stickies.Load(topic); ok {
+		sticky := value.(*stickyTopic)
+		sticky.lock.Lock()
+		defer sticky.lock.Unlock()
+		sticky.args, sticky.set = nil, false
+	}
+}
+
+// subscribeSticky subscribes the handler and delivers the sticky event of the topic to it
+func (bus *EventBus) subscribeSticky(sticky *stickyTopic, topic string, fn interface{}, handler *eventHandler) error {
+	sticky.lock.Lock()
+	defer sticky.lock.Unlock()
+	if err := bus.addHandler(topic, fn, handler); err != nil || !sticky.set {
+		return err
+	}
+
+	// the bus lock is not held while the handler runs, as in Publish
+	if !handler.claim() {
+		return nil // delivered by a concurrent Publish
+	}
+	if handler.flagOnce {
+		bus.lock.Lock()
+		bus.removeOnce(topic, handler)
+		bus.lock.Unlock()
+	}
+	ring := bus.live.traceRing()
+	record := ring.begin(topic, sticky.args)
+	env := bus.newEnvelope(context.Background(), topic, nil, sticky.args)
+	run, failure := bus.deliver(handler, ring, record, env)
+	env.progress.done(env.seq)
+	ring.end(record)
+	if handler.flagOnce {
+		bus.lock.Lock()
+		bus.reclaimDropped()
+		bus.lock.Unlock()
+	}
+
+	if run != nil {
+		run()
+	}
+	if failure != nil {
+		bus.report(failure)
+	}
+	return nil
+}
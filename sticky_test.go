@@ -0,0 +1,51 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestPublishSticky(t *testing.T) {
+	bus := New().(*EventBus)
+	var early []string
+	bus.Subscribe("config", func(v string) { early = append(early, v) })
+	bus.PublishSticky("config", "v1")
+	bus.PublishSticky("config", "v2")
+
+	var late []string
+	bus.Subscribe("config", func(v string) { late = append(late, v) })
+	lateAsync := make(chan string, 1)
+	bus.SubscribeOnceAsync("config", func(v string) { lateAsync <- v })
+	bus.WaitAsync()
+	if len(early) != 2 || len(late) != 1 || late[0] != "v2" || <-lateAsync != "v2" {
+		t.Fatal(early, late)
+	}
+
+	bus.Publish("config", "v3") // not sticky
+	if len(late) != 2 || len(bus.handlers["config"]) != 2 {
+		t.Fatal(late, len(bus.handlers["config"]))
+	}
+
+	bus.ClearSticky("config")
+	var cleared []string
+	bus.Subscribe("config", func(v string) { cleared = append(cleared, v) })
+	if len(cleared) != 0 {
+		t.Fatal(cleared)
+	}
+}
+
+func TestStickyHandlerUsesBus(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.PublishSticky("config", "v1")
+	var seen []string
+	// the sticky event is delivered without the bus lock held
+	bus.Subscribe("config", func(v string) {
+		if bus.HasCallback("config") {
+			seen = append(seen, v)
+		}
+		bus.SubscribeOnce("reload", func() { seen = append(seen, "reload") })
+		bus.Publish("reload")
+	})
+	if len(seen) != 2 || seen[0] != "v1" || seen[1] != "reload" || bus.HasCallback("reload") {
+		t.Fatal(seen)
+	}
+}
@@ -0,0 +1,119 @@
+package EventBus
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// StressConfig - shape of a randomized concurrent run performed by Stress
+type StressConfig struct {
+	Topics     int   // number of topics operated on
+	Workers    int   // number of goroutines issuing operations concurrently
+	Operations int   // number of operations issued by every worker
+	Seed       int64 // seed of the per-worker random sources
+}
+
+// stressTopic - handlers and counters of a single topic in a stress run
+type stressTopic struct {
+	name      string
+	published int64
+	stable    []int64 // invocation counts of handlers that are never unsubscribed
+	async     []int64 // invocation counts of async handlers that are never unsubscribed
+	lock      sync.Mutex
+	once      []*int64 // invocation counts of every once handler subscribed during the run
+}
+
+func (topic *stressTopic) addOnce() *int64 {
+	counter := new(int64)
+	topic.lock.Lock()
+	topic.once = append(topic.once, counter)
+	topic.lock.Unlock()
+	return counter
+}
+
+// Stress runs random concurrent Subscribe, SubscribeOnce, SubscribeOnceAsync, Unsubscribe
+// and Publish operations against the bus returned by newBus and checks that:
+// handlers which are never unsubscribed see every event, once handlers fire exactly once
+// and no operation panics. The first violated invariant is returned as an error.
+func Stress(newBus func() Bus, config StressConfig) error {
+	bus := newBus()
+	topics := make([]*stressTopic, config.Topics)
+	for i := range topics {
+		topic := &stressTopic{name: fmt.Sprintf("stress:%d", i), stable: make([]int64, 2), async: make([]int64, 2)}
+		for j := range topic.stable {
+			counter := &topic.stable[j]
+			bus.Subscribe(topic.name, func() { atomic.AddInt64(counter, 1) })
+		}
+		for j := range topic.async {
+			counter := &topic.async[j]
+			bus.SubscribeAsync(topic.name, func() { atomic.AddInt64(counter, 1) }, j == 0)
+		}
+		topics[i] = topic
+	}
+
+	churn := func() {}
+	errs := make(chan error, config.Workers)
+	var wg sync.WaitGroup
+	for w := 0; w < config.Workers; w++ {
+		wg.Add(1)
+		go func(r *rand.Rand) {
+			defer wg.Done()
+			defer func() {
+				if p := recover(); p != nil {
+					errs <- fmt.Errorf("panic: %v", p)
+				}
+			}()
+			for i := 0; i < config.Operations; i++ {
+				topic := topics[r.Intn(len(topics))]
+				switch r.Intn(5) {
+				case 0:
+					bus.Subscribe(topic.name, churn)
+				case 1:
+					bus.Unsubscribe(topic.name, churn)
+				case 2:
+					counter := topic.addOnce()
+					bus.SubscribeOnce(topic.name, func() { atomic.AddInt64(counter, 1) })
+				case 3:
+					counter := topic.addOnce()
+					bus.SubscribeOnceAsync(topic.name, func() { atomic.AddInt64(counter, 1) })
+				default:
+					atomic.AddInt64(&topic.published, 1)
+					bus.Publish(topic.name)
+				}
+			}
+		}(rand.New(rand.NewSource(config.Seed + int64(w))))
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	// one last event per topic fires every once handler still subscribed
+	for _, topic := range topics {
+		topic.published++
+		bus.Publish(topic.name)
+	}
+	bus.WaitAsync()
+
+	for _, topic := range topics {
+		for i := range topic.stable {
+			if got := atomic.LoadInt64(&topic.stable[i]); got != topic.published {
+				return fmt.Errorf("%s: handler %d received %d of %d events", topic.name, i, got, topic.published)
+			}
+		}
+		for i := range topic.async {
+			if got := atomic.LoadInt64(&topic.async[i]); got != topic.published {
+				return fmt.Errorf("%s: async handler %d received %d of %d events", topic.name, i, got, topic.published)
+			}
+		}
+		for i, counter := range topic.once {
+			if got := atomic.LoadInt64(counter); got != 1 {
+				return fmt.Errorf("%s: once handler %d fired %d times", topic.name, i, got)
+			}
+		}
+	}
+	return nil
+}
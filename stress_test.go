@@ -0,0 +1,15 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestStress(t *testing.T) {
+	config := StressConfig{Topics: 3, Workers: 8, Operations: 500, Seed: 1}
+	if testing.Short() {
+		config.Operations = 50
+	}
+	if err := Stress(New, config); err != nil {
+		t.Fatal(err)
+	}
+}
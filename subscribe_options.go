@@ -0,0 +1,66 @@
+package EventBus
+
+import (
+	"sort"
+)
+
+// SubscribeOption - setting of a single subscription made with SubscribeWith
+type SubscribeOption func(handler *eventHandler)
+
+// WithOnce removes the handler after its first execution, like SubscribeOnce.
+func WithOnce() SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.flagOnce = true
+	}
+}
+
+// WithAsync runs the handler asynchronously, like SubscribeAsync.
+func WithAsync(transactional bool) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.async = true
+		handler.transactional = transactional
+	}
+}
+
+// WithTolerantArity lets the handler take fewer parameters than published arguments, receiving
+// a prefix of them, or more, the missing trailing ones being zero values. Publishers can then add
+// arguments without breaking every subscriber at once.
+func WithTolerantArity() SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.tolerant = true
+	}
+}
+
+// WithPriority calls the handler before those of lower priority, handlers of the same priority
+// being called in subscription order. Handlers subscribed without a priority have priority 0.
+func WithPriority(priority int) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.priority = priority
+	}
+}
+
+// SubscribeWithPriority subscribes to a topic with the given priority, so e.g. a validation
+// handler runs before a persistence handler whatever the subscription order. See WithPriority.
+// Returns error if `fn` is not a function.
+func (bus *EventBus) SubscribeWithPriority(topic string, fn interface{}, priority int) error {
+	return bus.SubscribeWith(topic, fn, WithPriority(priority))
+}
+
+// insertByPriority returns a copy of handlers with handler added after every handler of the same
+// or a higher priority
+func insertByPriority(handlers []*eventHandler, handler *eventHandler) []*eventHandler {
+	i := sort.Search(len(handlers), func(i int) bool { return handlers[i].priority < handler.priority })
+	inserted := make([]*eventHandler, 0, len(handlers)+1)
+	inserted = append(append(inserted, handlers[:i]...), handler)
+	return append(inserted, handlers[i:]...)
+}
+
+// SubscribeWith subscribes to a topic with the given options.
+// Returns error if `fn` is not a function.
+func (bus *EventBus) SubscribeWith(topic string, fn interface{}, opts ...SubscribeOption) error {
+	handler := newEventHandler(fn, false, false, false)
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return bus.doSubscribe(topic, fn, handler)
+}
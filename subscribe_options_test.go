@@ -0,0 +1,63 @@
+package EventBus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubscribeWith(t *testing.T) {
+	bus := New().(*EventBus)
+	count := 0
+	if bus.SubscribeWith("topic", func() { count++ }, WithOnce(), WithAsync(true)) != nil {
+		t.Fail()
+	}
+	if bus.SubscribeWith("topic", "String") == nil {
+		t.Fail()
+	}
+	bus.Publish("topic")
+	bus.Publish("topic")
+	bus.WaitAsync()
+	if count != 1 || bus.HasCallback("topic") {
+		t.Fail()
+	}
+}
+
+func TestTolerantArity(t *testing.T) {
+	bus := NewStrict().(*EventBus)
+	var got []interface{}
+	bus.SubscribeWith("topic", func(a int) { got = append(got, a) }, WithTolerantArity())
+	bus.SubscribeWith("topic", func(a int, s string, extra *int) {
+		got = append(got, s, extra == nil)
+	}, WithTolerantArity())
+	bus.SubscribeWith("topic", func(a int, rest ...string) { got = append(got, len(rest)) }, WithTolerantArity())
+	bus.Publish("topic", 1, "x")
+	bus.Publish("topic", 2)
+	if len(got) != 8 || got[0] != 1 || got[1] != "x" || got[2] != true || got[3] != 1 ||
+		got[4] != 2 || got[5] != "" || got[6] != true || got[7] != 0 {
+		t.Fatal(got)
+	}
+}
+
+func TestSubscribeWithPriority(t *testing.T) {
+	bus := New().(*EventBus)
+	var calls []string
+	record := func(name string) func() { return func() { calls = append(calls, name) } }
+	bus.Subscribe("order", record("default"))
+	bus.SubscribeWithPriority("order", record("persist"), 10)
+	bus.SubscribeWithPriority("order", record("audit"), -1)
+	bus.SubscribeWithPriority("order", record("validate"), 100)
+	bus.SubscribeWithPriority("order", record("persist again"), 10)
+	bus.Publish("order")
+	if strings.Join(calls, ",") != "validate,persist,persist again,default,audit" {
+		t.Fatal(calls)
+	}
+
+	calls = nil
+	bus.SubscribeEntities("user", record("any user"), WithPriority(5))
+	bus.SubscribeEntity("user", 1, record("user 1"))
+	bus.SubscribeEntity("user", 1, record("user 1 first"), WithPriority(5))
+	bus.Publish(EntityTopic("user", 1))
+	if strings.Join(calls, ",") != "user 1 first,any user,user 1" {
+		t.Fatal(calls)
+	}
+}
@@ -0,0 +1,117 @@
+package EventBus
+
+import (
+	"sync/atomic"
+)
+
+// Subscription - handle of a handler, returned by the Handle variant of every Subscribe method
+// below. It identifies the handler itself rather than its function, so anonymous closures can
+// be unsubscribed. The methods without the suffix keep returning an error only, as the Bus
+// interface declares them; SubscribeMerged returns a stop function instead, its handlers
+// belonging to the merged stream.
+type Subscription struct {
+	bus     *EventBus
+	topic   string
+	handler *eventHandler
+}
+
+// subscribe subscribes the handler of fn and returns its handle
+func (bus *EventBus) subscribe(topic string, fn interface{}, handler *eventHandler) (*Subscription, error) {
+	if err := bus.doSubscribe(topic, fn, handler); err != nil {
+		return nil, err
+	}
+	return &Subscription{bus: bus, topic: topic, handler: handler}, nil
+}
+
+// SubscribeHandle is Subscribe, or SubscribeWith when given options, returning the handle of
+// the subscription.
+func (bus *EventBus) SubscribeHandle(topic string, fn interface{}, opts ...SubscribeOption) (*Subscription, error) {
+	handler := newEventHandler(fn, false, false, false)
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return bus.subscribe(topic, fn, handler)
+}
+
+// SubscribeAsyncHandle is SubscribeAsync returning the handle of the subscription.
+func (bus *EventBus) SubscribeAsyncHandle(topic string, fn interface{}, transactional bool) (*Subscription, error) {
+	return bus.subscribe(topic, fn, newEventHandler(fn, false, true, transactional))
+}
+
+// SubscribeOnceHandle is SubscribeOnce returning the handle of the subscription.
+func (bus *EventBus) SubscribeOnceHandle(topic string, fn interface{}) (*Subscription, error) {
+	return bus.subscribe(topic, fn, newEventHandler(fn, true, false, false))
+}
+
+// SubscribeOnceAsyncHandle is SubscribeOnceAsync returning the handle of the subscription.
+func (bus *EventBus) SubscribeOnceAsyncHandle(topic string, fn interface{}) (*Subscription, error) {
+	return bus.subscribe(topic, fn, newEventHandler(fn, true, true, false))
+}
+
+// SubscribeWithPriorityHandle is SubscribeWithPriority returning the handle of the subscription.
+func (bus *EventBus) SubscribeWithPriorityHandle(topic string, fn interface{}, priority int) (*Subscription, error) {
+	return bus.SubscribeHandle(topic, fn, WithPriority(priority))
+}
+
+// SubscribeEntityHandle is SubscribeEntity returning the handle of the subscription.
+func (bus *EventBus) SubscribeEntityHandle(kind string, id interface{}, fn interface{}, opts ...SubscribeOption) (*Subscription, error) {
+	return bus.subscribeEntity(kind, id, fn, opts)
+}
+
+// SubscribeEntitiesHandle is SubscribeEntities returning the handle of the subscription.
+func (bus *EventBus) SubscribeEntitiesHandle(kind string, fn interface{}, opts ...SubscribeOption) (*Subscription, error) {
+	return bus.subscribeEntities(kind, fn, opts)
+}
+
+// SubscribeTreeHandle is SubscribeTree returning the handle of the subscription.
+func (bus *EventBus) SubscribeTreeHandle(topic string, fn interface{}, opts ...SubscribeOption) (*Subscription, error) {
+	return bus.subscribeTree(topic, fn, opts)
+}
+
+// SubscribeMethodHandle is SubscribeMethod returning the handle of the subscription.
+func (bus *EventBus) SubscribeMethodHandle(topic string, receiver interface{}, method string, opts ...SubscribeOption) (*Subscription, error) {
+	return bus.subscribeMethod(topic, receiver, method, opts)
+}
+
+// SubscribeStateHandle is SubscribeState returning the handle of the subscription.
+func (bus *EventBus) SubscribeStateHandle(topic string, fn interface{}) (*Subscription, error) {
+	return bus.subscribeState(topic, fn)
+}
+
+// SubscribeShadowHandle is SubscribeShadow returning the handle of the subscription, its
+// ShadowStats are kept once unsubscribed.
+func (bus *EventBus) SubscribeShadowHandle(topic string, fn interface{}) (*Subscription, error) {
+	return bus.subscribeShadow(topic, fn)
+}
+
+// SubscribeSplitHandle is SubscribeSplit returning the handle of the subscription, which
+// unsubscribes every variant.
+func (bus *EventBus) SubscribeSplitHandle(topic string, variants map[string]SplitVariant, key func(args []interface{}) string) (*Subscription, error) {
+	return bus.subscribeSplit(topic, variants, key)
+}
+
+// Topic returns the topic subscribed to
+func (sub *Subscription) Topic() string {
+	return sub.topic
+}
+
+// IsActive reports whether the handler is still subscribed: it is not once unsubscribed, nor
+// once called when subscribed WithOnce.
+func (sub *Subscription) IsActive() bool {
+	sub.bus.lock.Lock()
+	defer sub.bus.lock.Unlock()
+	return sub.bus.findHandlerPtrIdx(sub.topic, sub.handler) >= 0 &&
+		(!sub.handler.flagOnce || atomic.LoadInt32(&sub.handler.claimed) == 0)
+}
+
+// Unsubscribe removes the handler. Returns ErrSealed on a sealed bus, *ErrHandlerNotFound when
+// the handler is no longer subscribed.
+func (sub *Subscription) Unsubscribe() error {
+	if sub.bus.sealedTable() != nil {
+		return ErrSealed
+	}
+	if !sub.bus.removeHandlerPtr(sub.topic, sub.handler) {
+		return &ErrHandlerNotFound{Topic: sub.topic, Handler: sub.handler.name()}
+	}
+	return nil
+}
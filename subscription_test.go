@@ -0,0 +1,100 @@
+package EventBus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSubscriptionHandle(t *testing.T) {
+	bus := New().(*EventBus)
+	var calls []int
+	subs := make([]*Subscription, 3)
+	for i := range subs {
+		i := i
+		sub, err := bus.SubscribeHandle("topic", func() { calls = append(calls, i) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs[i] = sub
+	}
+	if err := subs[1].Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic")
+	if len(calls) != 2 || calls[0] != 0 || calls[1] != 2 {
+		t.Fatal(calls)
+	}
+	if subs[1].IsActive() || !subs[0].IsActive() || subs[0].Topic() != "topic" {
+		t.Fatal("wrong state")
+	}
+	var notFound *ErrHandlerNotFound
+	if err := subs[1].Unsubscribe(); !errors.As(err, &notFound) {
+		t.Fatal(err)
+	}
+}
+
+func TestSubscriptionHandleOnce(t *testing.T) {
+	bus := New().(*EventBus)
+	sub, err := bus.SubscribeHandle("topic", func() {}, WithOnce(), WithAsync(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish("topic")
+	bus.WaitAsync()
+	if sub.IsActive() {
+		t.Fatal("once handler still active")
+	}
+	if _, err := bus.SubscribeHandle("topic", 42); !errors.Is(err, ErrNotAFunction) {
+		t.Fatal(err)
+	}
+}
+
+type subscriptionReceiver struct{ calls int }
+
+func (receiver *subscriptionReceiver) Handle() { receiver.calls++ }
+
+func TestSubscriptionHandleVariants(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.SetState("state", 1)
+	var calls int
+	fn := func() { calls++ }
+	receiver := &subscriptionReceiver{}
+	subscribe := []func() (*Subscription, error){
+		func() (*Subscription, error) { return bus.SubscribeAsyncHandle("async", fn, true) },
+		func() (*Subscription, error) { return bus.SubscribeOnceHandle("once", fn) },
+		func() (*Subscription, error) { return bus.SubscribeOnceAsyncHandle("once:async", fn) },
+		func() (*Subscription, error) { return bus.SubscribeWithPriorityHandle("priority", fn, 1) },
+		func() (*Subscription, error) { return bus.SubscribeEntityHandle("order", 1, fn) },
+		func() (*Subscription, error) { return bus.SubscribeEntitiesHandle("user", fn) },
+		func() (*Subscription, error) { return bus.SubscribeTreeHandle("a.b", fn) },
+		func() (*Subscription, error) { return bus.SubscribeMethodHandle("method", receiver, "Handle") },
+		func() (*Subscription, error) { return bus.SubscribeStateHandle("state", func(int) { calls++ }) },
+		func() (*Subscription, error) { return bus.SubscribeShadowHandle("shadow", fn) },
+		func() (*Subscription, error) {
+			return bus.SubscribeSplitHandle("split", map[string]SplitVariant{"a": {Fn: fn, Weight: 1}}, nil)
+		},
+	}
+	topics := []string{"async", "once", "once:async", "priority", "order:1", "user:*", "a.b", "method", "state", "shadow", "split"}
+	for i, subscribeHandle := range subscribe {
+		sub, err := subscribeHandle()
+		if err != nil || sub.Topic() != topics[i] || !sub.IsActive() {
+			t.Fatal(topics[i], err)
+		}
+		if err := sub.Unsubscribe(); err != nil || sub.IsActive() || bus.HasCallback(topics[i]) {
+			t.Fatal(topics[i], err)
+		}
+	}
+	// only SubscribeStateHandle delivered the current state
+	for _, topic := range topics {
+		bus.Publish(topic)
+	}
+	bus.Publish("order:1")
+	bus.Publish("a.b.c")
+	bus.WaitAsync()
+	if calls != 1 || receiver.calls != 0 {
+		t.Fatal(calls, receiver.calls)
+	}
+	if _, err := bus.SubscribeMethodHandle("method", receiver, "Missing"); !errors.Is(err, ErrNotAFunction) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,53 @@
+package EventBus
+
+import (
+	"time"
+)
+
+const (
+	// HandlerRestartedTopic - topic published when a supervised worker is restarted after a panic,
+	// the event carries the worker name, the recovered value and the number of restarts so far
+	HandlerRestartedTopic = "bus:handler_restarted"
+)
+
+// SupervisorPolicy - how a supervised worker is restarted after panicking
+type SupervisorPolicy struct {
+	MaxRestarts int                                      // restarts allowed before giving up, negative for no limit
+	Backoff     time.Duration                            // delay before the first restart, doubled for every further restart
+	MaxBackoff  time.Duration                            // upper bound of the delay, zero for no bound
+	OnFatal     func(name string, recovered interface{}) // called when the worker is given up
+}
+
+// supervise runs work until it returns, restarting it according to policy when it panics.
+// It returns false if the worker was given up after too many restarts.
+func (bus *EventBus) supervise(name string, policy SupervisorPolicy, work func()) bool {
+	backoff := policy.Backoff
+	for restarts := 0; ; restarts++ {
+		recovered, panicked := runRecovered(work)
+		if !panicked {
+			return true
+		}
+		if policy.MaxRestarts >= 0 && restarts >= policy.MaxRestarts {
+			if policy.OnFatal != nil {
+				policy.OnFatal(name, recovered)
+			}
+			return false
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+		bus.Publish(HandlerRestartedTopic, name, recovered, restarts+1)
+	}
+}
+
+func runRecovered(work func()) (recovered interface{}, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			recovered, panicked = r, true
+		}
+	}()
+	work()
+	return nil, false
+}
@@ -0,0 +1,51 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSupervisedActorRestarts(t *testing.T) {
+	bus := New().(*EventBus)
+	var restarts []int
+	bus.Subscribe(HandlerRestartedTopic, func(name string, recovered interface{}, n int) {
+		if name != "worker" || recovered != "boom" {
+			t.Fail()
+		}
+		restarts = append(restarts, n)
+	})
+	var processed []int
+	actor := NewActor(bus, 4, func(event Event) {
+		if event.Args[0].(int)%2 == 0 {
+			panic("boom")
+		}
+		processed = append(processed, event.Args[0].(int))
+	}).Supervise("worker", SupervisorPolicy{MaxRestarts: -1, Backoff: time.Millisecond})
+	actor.Start("topic")
+	for i := 1; i <= 5; i++ {
+		bus.Publish("topic", i)
+	}
+	actor.Stop()
+	if len(processed) != 3 || len(restarts) != 2 || restarts[1] != 2 {
+		t.Fatal(processed, restarts)
+	}
+}
+
+func TestSupervisedActorFatal(t *testing.T) {
+	bus := New().(*EventBus)
+	fatal := make(chan string, 1)
+	actor := NewActor(bus, 1, func(event Event) {
+		panic("boom")
+	}).Supervise("worker", SupervisorPolicy{MaxRestarts: 1, OnFatal: func(name string, recovered interface{}) {
+		fatal <- name
+	}})
+	actor.Start("topic")
+	for i := 0; i < 5; i++ {
+		bus.Publish("topic")
+	}
+	bus.WaitAsync()
+	if <-fatal != "worker" {
+		t.Fail()
+	}
+	actor.Stop()
+}
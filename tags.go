@@ -0,0 +1,175 @@
+package EventBus
+
+import (
+	"sync"
+	"time"
+)
+
+// TagStats - deliveries to the handlers carrying a tag since it was first used
+type TagStats struct {
+	Tag       string
+	Handlers  int    // handlers currently subscribed with the tag
+	Delivered uint64 // deliveries to those handlers
+	Dropped   uint64 // deliveries skipped while the tag was paused or over its rate limit
+	Paused    bool
+}
+
+// tagControl - state shared by the handlers carrying a tag
+type tagControl struct {
+	paused    bool
+	rate      float64 // deliveries allowed per second, unlimited when zero
+	burst     float64
+	tokens    float64
+	refilled  time.Time
+	delivered uint64
+	dropped   uint64
+}
+
+// tagSet - controls of the tags used by handlers
+type tagSet struct {
+	lock sync.Mutex
+	tags map[string]*tagControl
+}
+
+// WithTags tags the subscription, so it can be managed along with the others carrying the same
+// tags, see UnsubscribeTag, PauseTag, LimitTag and TagStats.
+func WithTags(tags ...string) SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.tags = append(handler.tags, tags...)
+	}
+}
+
+// control returns the control of the tag, set.lock must be held
+func (set *tagSet) control(tag string) *tagControl {
+	if set.tags == nil {
+		set.tags = make(map[string]*tagControl)
+	}
+	control, ok := set.tags[tag]
+	if !ok {
+		control = &tagControl{}
+		set.tags[tag] = control
+	}
+	return control
+}
+
+// allow reports whether the event may be delivered to the handler: none of its tags is paused
+// and each has a token left
+func (set *tagSet) allow(handler *eventHandler) bool {
+	if len(handler.tags) == 0 {
+		return true
+	}
+	now := time.Now()
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	controls := make([]*tagControl, len(handler.tags))
+	allowed := true
+	for i, tag := range handler.tags {
+		controls[i] = set.control(tag)
+		allowed = allowed && !controls[i].paused && controls[i].hasToken(now)
+	}
+	for _, control := range controls {
+		if !allowed {
+			control.dropped++
+			continue
+		}
+		control.delivered++
+		if control.rate > 0 {
+			control.tokens--
+		}
+	}
+	return allowed
+}
+
+// hasToken refills the bucket of a rate limited tag and reports whether a delivery is left
+func (control *tagControl) hasToken(now time.Time) bool {
+	if control.rate <= 0 {
+		return true
+	}
+	control.tokens += now.Sub(control.refilled).Seconds() * control.rate
+	if control.tokens > control.burst {
+		control.tokens = control.burst
+	}
+	control.refilled = now
+	return control.tokens >= 1
+}
+
+// PauseTag stops delivering events to the handlers carrying the tag until ResumeTag, events
+// published meanwhile are dropped for them.
+func (bus *EventBus) PauseTag(tag string) {
+	bus.tags.lock.Lock()
+	defer bus.tags.lock.Unlock()
+	bus.tags.control(tag).paused = true
+}
+
+// ResumeTag delivers events to the handlers carrying the tag again.
+func (bus *EventBus) ResumeTag(tag string) {
+	bus.tags.lock.Lock()
+	defer bus.tags.lock.Unlock()
+	bus.tags.control(tag).paused = false
+}
+
+// LimitTag allows the handlers carrying the tag perSecond deliveries per second together, in bursts
+// of up to burst deliveries, the others are dropped. A perSecond of zero removes the limit.
+func (bus *EventBus) LimitTag(tag string, perSecond float64, burst int) {
+	bus.tags.lock.Lock()
+	defer bus.tags.lock.Unlock()
+	control := bus.tags.control(tag)
+	control.rate, control.burst = perSecond, float64(burst)
+	control.tokens, control.refilled = control.burst, time.Now()
+}
+
+// UnsubscribeTag removes every handler carrying the tag and returns how many were removed.
+// Returns ErrSealed on a sealed bus.
+func (bus *EventBus) UnsubscribeTag(tag string) (int, error) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.sealedTable() != nil {
+		return 0, ErrSealed
+	}
+	removed := 0
+	for topic, handlers := range bus.handlers {
+		kept := make([]*eventHandler, 0, len(handlers))
+		for _, handler := range handlers {
+			if handler.hasTag(tag) {
+				removed++
+			} else {
+				kept = append(kept, handler)
+			}
+		}
+		if len(kept) > 0 {
+			bus.setHandlers(topic, kept)
+		} else {
+			bus.dropTopic(topic)
+		}
+	}
+	return removed, nil
+}
+
+// TagStats returns the deliveries to the handlers carrying the tag.
+func (bus *EventBus) TagStats(tag string) TagStats {
+	stats := TagStats{Tag: tag}
+	bus.lock.Lock()
+	for _, handlers := range bus.handlers {
+		for _, handler := range handlers {
+			if handler.hasTag(tag) {
+				stats.Handlers++
+			}
+		}
+	}
+	bus.lock.Unlock()
+	bus.tags.lock.Lock()
+	defer bus.tags.lock.Unlock()
+	if control, ok := bus.tags.tags[tag]; ok {
+		stats.Delivered, stats.Dropped, stats.Paused = control.delivered, control.dropped, control.paused
+	}
+	return stats
+}
+
+func (handler *eventHandler) hasTag(tag string) bool {
+	for _, t := range handler.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
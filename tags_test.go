@@ -0,0 +1,53 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestTagsPauseAndUnsubscribe(t *testing.T) {
+	bus := New().(*EventBus)
+	counts := map[string]int{}
+	bus.SubscribeWith("order", func() { counts["analytics"]++ }, WithTags("analytics", "low-priority"))
+	bus.SubscribeWith("user", func() { counts["audit"]++ }, WithTags("analytics"))
+	bus.Subscribe("order", func() { counts["core"]++ })
+
+	bus.PauseTag("low-priority")
+	if delivered := bus.PublishEx("order"); delivered != 1 {
+		t.Fatal(delivered)
+	}
+	bus.ResumeTag("low-priority")
+	bus.Publish("order")
+	bus.Publish("user")
+	if counts["analytics"] != 1 || counts["audit"] != 1 || counts["core"] != 2 {
+		t.Fatal(counts)
+	}
+	if stats := bus.TagStats("analytics"); stats.Handlers != 2 || stats.Delivered != 2 || stats.Dropped != 1 || stats.Paused {
+		t.Fatal(stats)
+	}
+
+	if removed, err := bus.UnsubscribeTag("analytics"); removed != 2 || err != nil {
+		t.Fatal(removed, err)
+	}
+	bus.Publish("order")
+	if counts["analytics"] != 1 || counts["core"] != 3 || bus.HasCallback("user") {
+		t.Fatal(counts)
+	}
+}
+
+func TestLimitTag(t *testing.T) {
+	bus := New().(*EventBus)
+	count := 0
+	bus.SubscribeWith("metrics", func() { count++ }, WithTags("sampled"))
+	bus.LimitTag("sampled", 0.001, 3)
+	for i := 0; i < 10; i++ {
+		bus.Publish("metrics")
+	}
+	if count != 3 {
+		t.Fatal(count)
+	}
+	bus.LimitTag("sampled", 0, 0)
+	bus.Publish("metrics")
+	if count != 4 || bus.TagStats("sampled").Dropped != 7 {
+		t.Fatal(count, bus.TagStats("sampled"))
+	}
+}
@@ -0,0 +1,134 @@
+//go:build go1.18
+// +build go1.18
+
+// Package tinybus is a minimal event bus with the topic semantics of EventBus, typed with
+// generics and free of reflection and networking, so it builds with TinyGo for embedded
+// targets. Handlers take an event of type T; a program needing several event types uses a
+// bus per type.
+package tinybus
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNilHandler - a nil function was subscribed
+var ErrNilHandler = errors.New("nil handler")
+
+// Bus - bus of events of type T
+type Bus[T any] struct {
+	lock     sync.Mutex
+	handlers map[string][]*handler[T]
+	wg       sync.WaitGroup
+}
+
+type handler[T any] struct {
+	fn            func(event T)
+	flagOnce      bool
+	async         bool
+	transactional bool
+	serial        sync.Mutex // runs transactional callbacks serially
+}
+
+// New returns a bus of events of type T
+func New[T any]() *Bus[T] {
+	return &Bus[T]{handlers: make(map[string][]*handler[T])}
+}
+
+// Subscribe subscribes to a topic. stop unsubscribes the handler.
+func (b *Bus[T]) Subscribe(topic string, fn func(event T)) (stop func(), err error) {
+	return b.subscribe(topic, &handler[T]{fn: fn})
+}
+
+// SubscribeAsync subscribes to a topic with an asynchronous callback, run serially when
+// transactional.
+func (b *Bus[T]) SubscribeAsync(topic string, fn func(event T), transactional bool) (stop func(), err error) {
+	return b.subscribe(topic, &handler[T]{fn: fn, async: true, transactional: transactional})
+}
+
+// SubscribeOnce subscribes to a topic once. Handler will be removed after executing.
+func (b *Bus[T]) SubscribeOnce(topic string, fn func(event T)) (stop func(), err error) {
+	return b.subscribe(topic, &handler[T]{fn: fn, flagOnce: true})
+}
+
+// SubscribeOnceAsync subscribes to a topic once with an asynchronous callback
+func (b *Bus[T]) SubscribeOnceAsync(topic string, fn func(event T)) (stop func(), err error) {
+	return b.subscribe(topic, &handler[T]{fn: fn, flagOnce: true, async: true})
+}
+
+func (b *Bus[T]) subscribe(topic string, h *handler[T]) (stop func(), err error) {
+	if h.fn == nil {
+		return nil, ErrNilHandler
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], h)
+	return func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		b.remove(topic, h)
+	}, nil
+}
+
+// HasCallback returns true if exists any callback subscribed to the topic.
+func (b *Bus[T]) HasCallback(topic string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.handlers[topic]) > 0
+}
+
+// remove removes the handler, reporting false when it is gone already. The lock must be held.
+func (b *Bus[T]) remove(topic string, h *handler[T]) bool {
+	handlers := b.handlers[topic]
+	for i, subscribed := range handlers {
+		if subscribed == h {
+			copied := make([]*handler[T], 0, len(handlers)-1)
+			copied = append(append(copied, handlers[:i]...), handlers[i+1:]...)
+			if len(copied) == 0 {
+				delete(b.handlers, topic)
+			} else {
+				b.handlers[topic] = copied
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Publish delivers event to the handlers of the topic. The handlers are called without the lock
+// held, so they may publish and subscribe.
+func (b *Bus[T]) Publish(topic string, event T) {
+	b.lock.Lock()
+	handlers := b.handlers[topic] // never modified in place, see remove
+	b.lock.Unlock()
+	for _, h := range handlers {
+		if h.flagOnce {
+			b.lock.Lock()
+			removed := b.remove(topic, h)
+			b.lock.Unlock()
+			if !removed {
+				continue // claimed by a concurrent Publish
+			}
+		}
+		if !h.async {
+			h.fn(event)
+			continue
+		}
+		b.wg.Add(1)
+		if h.transactional {
+			h.serial.Lock()
+		}
+		go func(h *handler[T]) {
+			defer b.wg.Done()
+			if h.transactional {
+				defer h.serial.Unlock()
+			}
+			h.fn(event)
+		}(h)
+	}
+}
+
+// WaitAsync waits for all async callbacks to complete
+func (b *Bus[T]) WaitAsync() {
+	b.wg.Wait()
+}
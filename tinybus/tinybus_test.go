@@ -0,0 +1,56 @@
+//go:build go1.18
+// +build go1.18
+
+package tinybus
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+type reading struct {
+	Sensor string
+	Value  int
+}
+
+func TestBus(t *testing.T) {
+	bus := New[reading]()
+	var sum int
+	stop, err := bus.Subscribe("sensor", func(r reading) { sum += r.Value })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var once, async int32
+	bus.SubscribeOnce("sensor", func(reading) { once++ })
+	bus.SubscribeAsync("sensor", func(r reading) { atomic.AddInt32(&async, int32(r.Value)) }, true)
+	bus.Publish("sensor", reading{"t1", 2})
+	bus.Publish("sensor", reading{"t1", 3})
+	bus.WaitAsync()
+	if sum != 5 || once != 1 || atomic.LoadInt32(&async) != 5 {
+		t.Fatal(sum, once, async)
+	}
+	stop()
+	bus.Publish("sensor", reading{"t1", 10})
+	bus.WaitAsync()
+	if sum != 5 || !bus.HasCallback("sensor") {
+		t.Fatal(sum)
+	}
+	if _, err := bus.Subscribe("sensor", nil); err != ErrNilHandler {
+		t.Fatal(err)
+	}
+}
+
+func TestPublishFromHandler(t *testing.T) {
+	bus := New[int]()
+	var got []int
+	bus.Subscribe("count", func(n int) {
+		got = append(got, n)
+		if n < 3 {
+			bus.Publish("count", n+1)
+		}
+	})
+	bus.Publish("count", 1)
+	if len(got) != 3 {
+		t.Fatal(got)
+	}
+}
@@ -0,0 +1,124 @@
+package EventBus
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TopicDeclaration - a topic, as declared by DeclareTopic, with the handlers subscribed to it
+type TopicDeclaration struct {
+	Topic       string
+	Description string
+	Args        []reflect.Type // types of the published arguments
+	Subscribers []string       // names of the handlers subscribed, with their flags
+	Declared    bool           // false for a topic having handlers but no declaration
+	Schema      *JSONSchema    // payload schema imported by ImportAsyncAPI, nil for Go types
+}
+
+// DeclareTopic documents a topic: what its events mean and the arguments they carry, given as
+// sample values such as Order{} or as reflect.Type values, e.g. for interfaces. Declaring a
+// topic again replaces its declaration.
+func (bus *EventBus) DeclareTopic(topic, description string, args ...interface{}) {
+	types := make([]reflect.Type, len(args))
+	for i, arg := range args {
+		if t, ok := arg.(reflect.Type); ok {
+			types[i] = t
+		} else {
+			types[i] = reflect.TypeOf(arg)
+		}
+	}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.declared == nil {
+		bus.declared = make(map[string]TopicDeclaration)
+	}
+	bus.declared[topic] = TopicDeclaration{Topic: topic, Description: description, Args: types, Declared: true}
+	bus.schemas.Delete(topic)
+}
+
+// Topics returns the declared topics and those having handlers, sorted, each with the
+// handlers currently subscribed to it.
+func (bus *EventBus) Topics() []TopicDeclaration {
+	topics, edges := bus.topology()
+	bus.lock.Lock()
+	byTopic := make(map[string]*TopicDeclaration, len(bus.declared)+len(topics))
+	for topic, declaration := range bus.declared {
+		declaration := declaration
+		byTopic[topic] = &declaration
+	}
+	bus.lock.Unlock()
+	for _, topic := range topics {
+		if byTopic[topic] == nil {
+			byTopic[topic] = &TopicDeclaration{Topic: topic}
+		}
+	}
+	for _, edge := range edges {
+		subscriber := edge.handler
+		if edge.flags != "" {
+			subscriber += " (" + edge.flags + ")"
+		}
+		declaration := byTopic[edge.topic]
+		declaration.Subscribers = append(declaration.Subscribers, subscriber)
+	}
+	declarations := make([]TopicDeclaration, 0, len(byTopic))
+	for _, declaration := range byTopic {
+		declarations = append(declarations, *declaration)
+	}
+	sort.Slice(declarations, func(i, j int) bool { return declarations[i].Topic < declarations[j].Topic })
+	return declarations
+}
+
+// WriteTopicDocs writes a Markdown catalog of the Topics: description, argument types with the
+// fields of struct arguments, and subscribers. Generating it from a test or go generate keeps
+// the catalog of events in sync with the code.
+func (bus *EventBus) WriteTopicDocs(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Event topics\n")
+	for _, topic := range bus.Topics() {
+		fmt.Fprintf(&b, "\n## `%s`\n\n", topic.Topic)
+		if !topic.Declared {
+			b.WriteString("Not declared.\n")
+		} else if topic.Description != "" {
+			b.WriteString(topic.Description + "\n")
+		}
+		if len(topic.Args) > 0 {
+			b.WriteString("\n| Argument | Type |\n|---|---|\n")
+			for i, t := range topic.Args {
+				fmt.Fprintf(&b, "| %d | `%v` |\n", i, t)
+			}
+			for _, t := range topic.Args {
+				writeFieldDocs(&b, t)
+			}
+		}
+		if len(topic.Subscribers) > 0 {
+			b.WriteString("\nSubscribers:\n")
+			for _, subscriber := range topic.Subscribers {
+				fmt.Fprintf(&b, "- `%s`\n", subscriber)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFieldDocs writes a table of the exported fields of a struct type, or pointer to one
+func writeFieldDocs(b *strings.Builder, t reflect.Type) {
+	if t == nil {
+		return
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	fmt.Fprintf(b, "\nFields of `%v`:\n\n| Field | Type |\n|---|---|\n", t)
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.PkgPath == "" {
+			fmt.Fprintf(b, "| %s | `%v` |\n", field.Name, field.Type)
+		}
+	}
+}
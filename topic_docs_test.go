@@ -0,0 +1,51 @@
+package EventBus
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type docOrder struct {
+	ID     string
+	Amount int
+	secret string
+}
+
+func docHandler(order docOrder, by string) {}
+
+func TestWriteTopicDocs(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.DeclareTopic("order:placed", "An order was placed by a customer.", docOrder{}, "")
+	bus.DeclareTopic("order:audit", "Audit trail.", reflect.TypeOf((*error)(nil)).Elem())
+	bus.Subscribe("order:placed", docHandler)
+	bus.SubscribeAsync("user:created", func() {}, false)
+
+	topics := bus.Topics()
+	if len(topics) != 3 || topics[1].Topic != "order:placed" || !topics[1].Declared || len(topics[1].Subscribers) != 1 ||
+		topics[2].Declared || topics[0].Args[0].String() != "error" {
+		t.Fatal(topics)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := bus.WriteTopicDocs(buf); err != nil {
+		t.Fatal(err)
+	}
+	docs := buf.String()
+	for _, want := range []string{
+		"## `order:placed`\n\nAn order was placed by a customer.\n",
+		"| 0 | `EventBus.docOrder` |\n| 1 | `string` |\n",
+		"| Amount | `int` |\n",
+		"- `github.com/asaskevich/EventBus.docHandler`\n",
+		"## `user:created`\n\nNot declared.\n",
+		"(async)",
+	} {
+		if !strings.Contains(docs, want) {
+			t.Fatalf("%q missing from\n%s", want, docs)
+		}
+	}
+	if strings.Contains(docs, "secret") {
+		t.Fatal(docs)
+	}
+}
@@ -0,0 +1,108 @@
+package EventBus
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TopologyFormat - diagram language written by ExportTopology
+type TopologyFormat int
+
+const (
+	// TopologyDOT - Graphviz DOT graph
+	TopologyDOT TopologyFormat = iota
+	// TopologyMermaid - Mermaid flowchart
+	TopologyMermaid
+)
+
+// topologyEdge - subscription of a handler to a topic
+type topologyEdge struct {
+	topic   string
+	handler string
+	flags   string
+}
+
+// ExportTopology writes a diagram of the topics and the handlers currently subscribed to them.
+func (bus *EventBus) ExportTopology(w io.Writer, format TopologyFormat) error {
+	topics, edges := bus.topology()
+	switch format {
+	case TopologyDOT:
+		return writeTopologyDOT(w, topics, edges)
+	case TopologyMermaid:
+		return writeTopologyMermaid(w, topics, edges)
+	}
+	return fmt.Errorf("unknown topology format %d", format)
+}
+
+func (bus *EventBus) topology() ([]string, []topologyEdge) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	topics := make([]string, 0, len(bus.handlers))
+	for topic, handlers := range bus.handlers {
+		if len(handlers) > 0 {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+	var edges []topologyEdge
+	for _, topic := range topics {
+		for _, handler := range bus.handlers[topic] {
+			var flags []string
+			if handler.async {
+				flags = append(flags, "async")
+			}
+			if handler.transactional {
+				flags = append(flags, "transactional")
+			}
+			if handler.flagOnce {
+				flags = append(flags, "once")
+			}
+			edges = append(edges, topologyEdge{topic, handler.name(), strings.Join(flags, ",")})
+		}
+	}
+	return topics, edges
+}
+
+func writeTopologyDOT(w io.Writer, topics []string, edges []topologyEdge) error {
+	var b strings.Builder
+	b.WriteString("digraph eventbus {\n\trankdir=LR;\n")
+	for _, topic := range topics {
+		fmt.Fprintf(&b, "\t%q [shape=box];\n", "topic:"+topic)
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "\t%q [shape=ellipse,label=%q];\n", "handler:"+edge.handler, edge.handler)
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", "topic:"+edge.topic, "handler:"+edge.handler, edge.flags)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTopologyMermaid(w io.Writer, topics []string, edges []topologyEdge) error {
+	label := strings.NewReplacer(`"`, "#quot;").Replace
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	topicIDs := make(map[string]string, len(topics))
+	for i, topic := range topics {
+		topicIDs[topic] = fmt.Sprintf("t%d", i)
+		fmt.Fprintf(&b, "\tt%d[\"%s\"]\n", i, label(topic))
+	}
+	handlerIDs := make(map[string]string)
+	for _, edge := range edges {
+		id, ok := handlerIDs[edge.handler]
+		if !ok {
+			id = fmt.Sprintf("h%d", len(handlerIDs))
+			handlerIDs[edge.handler] = id
+			fmt.Fprintf(&b, "\t%s([\"%s\"])\n", id, label(edge.handler))
+		}
+		if edge.flags != "" {
+			fmt.Fprintf(&b, "\t%s -->|%s| %s\n", topicIDs[edge.topic], edge.flags, id)
+		} else {
+			fmt.Fprintf(&b, "\t%s --> %s\n", topicIDs[edge.topic], id)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,40 @@
+package EventBus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func topologyHandler(a int) {}
+
+func TestExportTopology(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.Subscribe("orders", topologyHandler)
+	bus.SubscribeOnceAsync("orders", func() {})
+	bus.Subscribe("users", topologyHandler)
+
+	buf := new(bytes.Buffer)
+	if err := bus.ExportTopology(buf, TopologyDOT); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	if !strings.Contains(dot, `"topic:orders" -> "handler:github.com/asaskevich/EventBus.topologyHandler"`) ||
+		!strings.Contains(dot, `"topic:users" [shape=box]`) || !strings.Contains(dot, `label="async,once"`) {
+		t.Fatal(dot)
+	}
+
+	buf.Reset()
+	if err := bus.ExportTopology(buf, TopologyMermaid); err != nil {
+		t.Fatal(err)
+	}
+	mermaid := buf.String()
+	if !strings.HasPrefix(mermaid, "flowchart LR\n") || !strings.Contains(mermaid, "t1 --> h0") ||
+		!strings.Contains(mermaid, "t0 -->|async,once| h1") {
+		t.Fatal(mermaid)
+	}
+
+	if bus.ExportTopology(buf, TopologyFormat(42)) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,236 @@
+package EventBus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TraceOutcome - what happened to a traced event for a single handler
+type TraceOutcome string
+
+const (
+	// TraceDispatched - async handler was started but has not returned yet
+	TraceDispatched TraceOutcome = "dispatched"
+	// TraceDelivered - handler returned normally
+	TraceDelivered TraceOutcome = "delivered"
+	// TracePanicked - handler panicked
+	TracePanicked TraceOutcome = "panicked"
+	// TraceCancelled - handler was skipped, the context of the publish was done before it started
+	TraceCancelled TraceOutcome = "cancelled"
+)
+
+// TraceDelivery - delivery of a traced event to one handler
+type TraceDelivery struct {
+	Handler  string
+	Async    bool
+	Once     bool
+	Outcome  TraceOutcome
+	Duration time.Duration
+}
+
+// TraceRecord - a published event kept in the trace ring
+type TraceRecord struct {
+	Topic      string
+	Args       string
+	Published  time.Time
+	Duration   time.Duration
+	Deliveries []TraceDelivery
+	dropped    bool // left the ring, its memory is no longer counted
+}
+
+// traceRing - fixed size ring of the most recently published events
+type traceRing struct {
+	lock    sync.Mutex
+	records []*TraceRecord // nil once evicted, see WithMemoryLimit
+	next    int
+	memory  *memoryAccount // counts the bytes of the records, nil when not counted
+}
+
+// traceTicket - handle used to report the outcome of a single delivery
+type traceTicket struct {
+	ring   *traceRing
+	record *TraceRecord
+	idx    int
+}
+
+func newTraceRing(size int, memory *memoryAccount) *traceRing {
+	return &traceRing{records: make([]*TraceRecord, 0, size), memory: memory}
+}
+
+// begin stores a new record for the published event, a nil ring traces nothing
+func (ring *traceRing) begin(topic string, args []interface{}) *TraceRecord {
+	if ring == nil {
+		return nil
+	}
+	record := &TraceRecord{Topic: topic, Args: fmt.Sprint(args...), Published: time.Now()}
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	if len(ring.records) < cap(ring.records) {
+		ring.records = append(ring.records, record)
+	} else {
+		ring.drop(ring.next)
+		ring.records[ring.next] = record
+	}
+	ring.next = (ring.next + 1) % cap(ring.records)
+	ring.memory.add(memoryTrace, recordSize(record))
+	return record
+}
+
+// evictOldest drops the oldest record, it reports false when there is none
+func (ring *traceRing) evictOldest() bool {
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	for i := 0; i < len(ring.records); i++ {
+		idx := (ring.start() + i) % len(ring.records)
+		if ring.records[idx] != nil {
+			ring.drop(idx)
+			return true
+		}
+	}
+	return false
+}
+
+// release drops every record, for a ring replaced by EnableTrace
+func (ring *traceRing) release() {
+	if ring == nil {
+		return
+	}
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	for idx := range ring.records {
+		ring.drop(idx)
+	}
+}
+
+// drop removes a record from the ring, the lock must be held
+func (ring *traceRing) drop(idx int) {
+	if record := ring.records[idx]; record != nil {
+		record.dropped = true
+		ring.memory.add(memoryTrace, -recordSize(record))
+		ring.records[idx] = nil
+	}
+}
+
+// start returns the index of the oldest record, the lock must be held
+func (ring *traceRing) start() int {
+	if len(ring.records) == cap(ring.records) {
+		return ring.next
+	}
+	return 0
+}
+
+func (ring *traceRing) end(record *TraceRecord) {
+	if record == nil {
+		return
+	}
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	record.Duration = time.Since(record.Published)
+}
+
+func (ring *traceRing) deliver(record *TraceRecord, handler *eventHandler) *traceTicket {
+	if record == nil {
+		return nil
+	}
+	delivery := TraceDelivery{
+		Handler: handler.name(),
+		Async:   handler.async,
+		Once:    handler.flagOnce,
+		Outcome: TraceDispatched,
+	}
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	record.Deliveries = append(record.Deliveries, delivery)
+	if !record.dropped {
+		ring.memory.add(memoryTrace, traceDeliverySize+int64(len(delivery.Handler)))
+	}
+	return &traceTicket{ring, record, len(record.Deliveries) - 1}
+}
+
+func (ticket *traceTicket) done(outcome TraceOutcome, started time.Time) {
+	if ticket == nil {
+		return
+	}
+	ticket.ring.lock.Lock()
+	defer ticket.ring.lock.Unlock()
+	ticket.record.Deliveries[ticket.idx].Outcome = outcome
+	ticket.record.Deliveries[ticket.idx].Duration = time.Since(started)
+}
+
+// snapshot copies the ring contents, oldest record first
+func (ring *traceRing) snapshot() []TraceRecord {
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+	records := make([]TraceRecord, 0, len(ring.records))
+	start := ring.start()
+	for i := 0; i < len(ring.records); i++ {
+		kept := ring.records[(start+i)%len(ring.records)]
+		if kept == nil {
+			continue
+		}
+		record := *kept
+		record.Deliveries = append([]TraceDelivery(nil), record.Deliveries...)
+		records = append(records, record)
+	}
+	return records
+}
+
+// EnableTrace keeps the last `size` published events of all topics together with
+// timing and delivery outcomes. A size of zero or less disables tracing.
+func (bus *EventBus) EnableTrace(size int) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	bus.trace.release()
+	if size <= 0 {
+		bus.trace = nil
+	} else {
+		bus.trace = newTraceRing(size, bus.memory)
+	}
+	bus.live.trace.Store(bus.trace)
+	if table := bus.sealedTable(); table != nil {
+		resealed := *table
+		resealed.trace = bus.trace
+		bus.sealed.Store(&resealed)
+	}
+}
+
+// Trace returns the traced events, oldest first.
+func (bus *EventBus) Trace() []TraceRecord {
+	bus.lock.Lock()
+	ring := bus.trace
+	bus.lock.Unlock()
+	if ring == nil {
+		return nil
+	}
+	return ring.snapshot()
+}
+
+// DumpTrace writes the traced events to w in a human readable form.
+func (bus *EventBus) DumpTrace(w io.Writer) error {
+	for _, record := range bus.Trace() {
+		_, err := fmt.Fprintf(w, "%s %s [%s] took %s, %d handler(s)\n",
+			record.Published.Format(time.RFC3339Nano), record.Topic, record.Args, record.Duration, len(record.Deliveries))
+		if err != nil {
+			return err
+		}
+		for _, delivery := range record.Deliveries {
+			_, err = fmt.Fprintf(w, "\t%s async=%t once=%t %s in %s\n",
+				delivery.Handler, delivery.Async, delivery.Once, delivery.Outcome, delivery.Duration)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TraceHandler returns an http.Handler serving DumpTrace output.
+func (bus *EventBus) TraceHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		bus.DumpTrace(w)
+	})
+}
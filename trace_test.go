@@ -0,0 +1,77 @@
+package EventBus
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTraceDisabled(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.Publish("topic")
+	if bus.Trace() != nil {
+		t.Fail()
+	}
+}
+
+func TestTraceRing(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.EnableTrace(2)
+	bus.Subscribe("topic", func(a int) {})
+	bus.SubscribeAsync("topic", func(a int) {}, false)
+	bus.Publish("topic", 1)
+	bus.Publish("topic", 2)
+	bus.Publish("nobody", 3)
+	bus.WaitAsync()
+
+	records := bus.Trace()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Topic != "topic" || records[0].Args != "2" || records[1].Topic != "nobody" {
+		t.Fail()
+	}
+	if len(records[0].Deliveries) != 2 || len(records[1].Deliveries) != 0 {
+		t.Fail()
+	}
+	for _, delivery := range records[0].Deliveries {
+		if delivery.Outcome != TraceDelivered {
+			t.Fail()
+		}
+	}
+	if !records[0].Deliveries[1].Async {
+		t.Fail()
+	}
+}
+
+func TestTracePanic(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.EnableTrace(4)
+	bus.Subscribe("topic", func() { panic("boom") })
+	func() {
+		defer func() { recover() }()
+		bus.Publish("topic")
+	}()
+	if bus.Trace()[0].Deliveries[0].Outcome != TracePanicked {
+		t.Fail()
+	}
+}
+
+func TestDumpTrace(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.EnableTrace(4)
+	bus.Subscribe("topic", func(s string) {})
+	bus.Publish("topic", "hello")
+
+	buf := new(bytes.Buffer)
+	if bus.DumpTrace(buf) != nil || !strings.Contains(buf.String(), "topic [hello]") {
+		t.Fail()
+	}
+
+	rec := httptest.NewRecorder()
+	bus.TraceHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/trace", nil))
+	if rec.Body.String() != buf.String() {
+		t.Fail()
+	}
+}
@@ -0,0 +1,81 @@
+package EventBus
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// TopicSeparator - separates the levels of a hierarchical topic, e.g. "audit.order.placed"
+const TopicSeparator = "."
+
+// WithTree makes the handler receive the events of the descendants of its topic too, see
+// SubscribeTree.
+func WithTree() SubscribeOption {
+	return func(handler *eventHandler) {
+		handler.tree = true
+	}
+}
+
+// SubscribeTree subscribes to a topic and to its descendants, with the given options: publishing
+// to "a.b.c" notifies the tree handlers of "a.b" and "a" as well, e.g. an audit logger of
+// everything under "a". They run after the handlers of the topic itself, the handlers of nearer
+// ancestors first, and read the topic published to from EventMeta. Unsubscribe from the topic.
+func (bus *EventBus) SubscribeTree(topic string, fn interface{}, opts ...SubscribeOption) error {
+	_, err := bus.subscribeTree(topic, fn, opts)
+	return err
+}
+
+func (bus *EventBus) subscribeTree(topic string, fn interface{}, opts []SubscribeOption) (*Subscription, error) {
+	bus.lock.Lock()
+	if bus.sealedTable() != nil {
+		bus.lock.Unlock()
+		return nil, ErrSealed
+	}
+	atomic.StoreInt32(&bus.trees, 1)
+	bus.lock.Unlock()
+	return bus.SubscribeHandle(topic, fn, append(opts, WithTree())...)
+}
+
+// parentOf returns the topic one level above topic in the hierarchy
+func parentOf(topic string) (string, bool) {
+	i := strings.LastIndex(topic, TopicSeparator)
+	if i <= 0 {
+		return "", false
+	}
+	return topic[:i], true
+}
+
+// withAncestors returns subscribed followed by the tree handlers of the ancestors of the topic
+func (bus *EventBus) withAncestors(handlers handlerSource, topic string, subscribed []*eventHandler) []*eventHandler {
+	if atomic.LoadInt32(&bus.trees) == 0 {
+		return subscribed
+	}
+	copied := false
+	for parent, ok := parentOf(topic); ok; parent, ok = parentOf(parent) {
+		for _, handler := range handlers.lookup(parent) {
+			if !handler.tree {
+				continue
+			}
+			if !copied {
+				subscribed, copied = append([]*eventHandler(nil), subscribed...), true
+			}
+			subscribed = append(subscribed, handler)
+		}
+	}
+	return subscribed
+}
+
+// removeTreeOnce removes a once tree handler of an ancestor of the topic about to be called,
+// reporting false when it is gone already
+func (bus *EventBus) removeTreeOnce(topic string, handler *eventHandler) bool {
+	if !handler.tree {
+		return false
+	}
+	for parent, ok := parentOf(topic); ok; parent, ok = parentOf(parent) {
+		if idx := bus.findHandlerPtrIdx(parent, handler); idx >= 0 {
+			bus.removeHandler(parent, idx)
+			return true
+		}
+	}
+	return false
+}
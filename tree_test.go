@@ -0,0 +1,39 @@
+package EventBus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubscribeTree(t *testing.T) {
+	bus := New().(*EventBus)
+	var got []string
+	record := func(name string) func(meta EventMeta) {
+		return func(meta EventMeta) { got = append(got, name+"<"+meta.Topic) }
+	}
+	bus.SubscribeTree("audit", record("audit"))
+	bus.SubscribeTree("audit.order", record("order"))
+	bus.Subscribe("audit.order", record("exact"))
+	bus.Subscribe("audit.order.placed", record("placed"))
+	bus.SubscribeWith("audit", record("once"), WithOnce(), WithTree())
+
+	bus.Publish("audit.order.placed")
+	bus.Publish("audit.order")
+	bus.Publish("audit.user")
+	bus.Publish("auditing")
+	want := "placed<audit.order.placed order<audit.order.placed audit<audit.order.placed once<audit.order.placed " +
+		"order<audit.order exact<audit.order audit<audit.order audit<audit.user"
+	if strings.Join(got, " ") != want {
+		t.Fatal(got)
+	}
+	if len(bus.handlers["audit"]) != 1 {
+		t.Fatal("once tree handler not removed")
+	}
+
+	got = nil
+	bus.Unsubscribe("audit", record("audit"))
+	bus.Publish("audit.order.placed")
+	if strings.Join(got, " ") != "placed<audit.order.placed order<audit.order.placed" {
+		t.Fatal(got)
+	}
+}
@@ -0,0 +1,87 @@
+package EventBus
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// TuningProfile - workload Tune sizes the async workers of a bus for
+type TuningProfile int
+
+const (
+	// TuneBalanced - a worker per processor, 64 deliveries waiting per worker
+	TuneBalanced TuningProfile = iota
+	// TuneThroughput - 4 workers per processor and long queues, for handlers waiting on I/O
+	TuneThroughput
+	// TuneLatency - a worker per processor and no queue: a delivery finding every worker busy
+	// starts a goroutine rather than wait
+	TuneLatency
+	// TuneLowMemory - a worker per two processors and short queues
+	TuneLowMemory
+)
+
+// Tuning - sizes chosen by Tune
+type Tuning struct {
+	Profile    TuningProfile
+	Procs      int // runtime.GOMAXPROCS when tuned
+	Workers    int
+	Queue      int    // deliveries waiting for a worker before the pool is saturated
+	Overflowed uint64 // deliveries which found the previous pool saturated
+}
+
+// base returns the workers and queue length of the profile on procs processors
+func (profile TuningProfile) base(procs int) (workers, queue int) {
+	switch profile {
+	case TuneThroughput:
+		return 4 * procs, 256
+	case TuneLatency:
+		return procs, 0
+	case TuneLowMemory:
+		return (procs + 1) / 2, 16
+	default:
+		return procs, 64
+	}
+}
+
+// pool returns the pool running async handlers, nil when they run on a goroutine each
+func (bus *EventBus) pool() *workerPool {
+	pool, _ := bus.workers.Load().(*workerPool)
+	return pool
+}
+
+// Tune sizes the pool running async handlers, see WithAsyncWorkers, for the profile and
+// runtime.GOMAXPROCS. Tune may be called again at runtime, e.g. periodically or after changing
+// GOMAXPROCS: it then accounts for the observed load, doubling the workers, up to 4 times the
+// profile's, when deliveries found the pool saturated since the previous call, and halving
+// them back otherwise. Deliveries queued on the replaced pool still run.
+func (bus *EventBus) Tune(profile TuningProfile) Tuning {
+	procs := runtime.GOMAXPROCS(0)
+	workers, queue := profile.base(procs)
+	tuning := Tuning{Profile: profile, Procs: procs, Workers: workers}
+
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	previous := bus.pool()
+	if previous != nil {
+		tuning.Overflowed = atomic.LoadUint64(&previous.overflowed)
+		switch {
+		case tuning.Overflowed > 0:
+			tuning.Workers = previous.size * 2
+		case previous.size/2 > workers:
+			tuning.Workers = previous.size / 2
+		}
+		if tuning.Workers < workers {
+			tuning.Workers = workers
+		}
+		if tuning.Workers > 4*workers {
+			tuning.Workers = 4 * workers
+		}
+	}
+	tuning.Queue = queue * tuning.Workers
+	if atomic.LoadInt32(&bus.closed) != 0 {
+		return tuning
+	}
+	bus.workers.Store(newWorkerPool(tuning.Workers, tuning.Queue))
+	previous.stop()
+	return tuning
+}
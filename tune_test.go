@@ -0,0 +1,56 @@
+package EventBus
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTune(t *testing.T) {
+	bus := New().(*EventBus)
+	procs := runtime.GOMAXPROCS(0)
+	tuning := bus.Tune(TuneThroughput)
+	if tuning.Procs != procs || tuning.Workers != 4*procs || tuning.Queue != 256*4*procs {
+		t.Fatal(tuning)
+	}
+	if tuning = bus.Tune(TuneLatency); tuning.Workers != 2*procs || tuning.Queue != 0 {
+		t.Fatal("idle pool not halved", tuning)
+	}
+	bus.Close()
+
+	bus = New().(*EventBus)
+	if tuning = bus.Tune(TuneLatency); tuning.Workers != procs {
+		t.Fatal(tuning)
+	}
+
+	var calls int32
+	var wg sync.WaitGroup
+	release := make(chan struct{})
+	bus.SubscribeAsync("job", func() {
+		defer wg.Done()
+		<-release
+		atomic.AddInt32(&calls, 1)
+	}, false)
+	n := 4*procs + 1
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		bus.Publish("job")
+	}
+	close(release)
+	wg.Wait()
+	if tuning = bus.Tune(TuneLatency); tuning.Overflowed == 0 || tuning.Workers != 2*procs {
+		t.Fatal("saturation not accounted for", tuning)
+	}
+	if tuning = bus.Tune(TuneLatency); tuning.Overflowed != 0 || tuning.Workers != procs {
+		t.Fatal("idle pool not shrunk", tuning)
+	}
+
+	wg.Add(1)
+	bus.Publish("job")
+	bus.WaitAsync()
+	if atomic.LoadInt32(&calls) != int32(n+1) {
+		t.Fatal(calls)
+	}
+	bus.Close()
+}
@@ -0,0 +1,148 @@
+//go:build go1.18
+// +build go1.18
+
+package EventBus
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// TypedBus - bus of events of type T with the subscription modes of EventBus, dispatching
+// without reflection. It shares the EventBus it was created on for WaitAsync and Close, its
+// topics are separate from those of the EventBus.
+type TypedBus[T any] struct {
+	bus      *EventBus
+	lock     sync.Mutex
+	handlers map[string][]*typedHandler[T]
+}
+
+type typedHandler[T any] struct {
+	fn            func(event T)
+	flagOnce      bool
+	async         bool
+	transactional bool
+	sync.Mutex    // runs transactional callbacks serially
+}
+
+// NewTypedBus returns a bus of events of type T whose async handlers count for bus.WaitAsync
+func NewTypedBus[T any](bus *EventBus) *TypedBus[T] {
+	return &TypedBus[T]{bus: bus, handlers: make(map[string][]*typedHandler[T])}
+}
+
+// Subscribe subscribes to a topic.
+func (b *TypedBus[T]) Subscribe(topic string, fn func(event T)) error {
+	return b.doSubscribe(topic, &typedHandler[T]{fn: fn})
+}
+
+// SubscribeAsync subscribes to a topic with an asynchronous callback, run serially when
+// transactional, see EventBus.SubscribeAsync.
+func (b *TypedBus[T]) SubscribeAsync(topic string, fn func(event T), transactional bool) error {
+	return b.doSubscribe(topic, &typedHandler[T]{fn: fn, async: true, transactional: transactional})
+}
+
+// SubscribeOnce subscribes to a topic once. Handler will be removed after executing.
+func (b *TypedBus[T]) SubscribeOnce(topic string, fn func(event T)) error {
+	return b.doSubscribe(topic, &typedHandler[T]{fn: fn, flagOnce: true})
+}
+
+// SubscribeOnceAsync subscribes to a topic once with an asynchronous callback
+func (b *TypedBus[T]) SubscribeOnceAsync(topic string, fn func(event T)) error {
+	return b.doSubscribe(topic, &typedHandler[T]{fn: fn, flagOnce: true, async: true})
+}
+
+func (b *TypedBus[T]) doSubscribe(topic string, handler *typedHandler[T]) error {
+	if handler.fn == nil {
+		return fmt.Errorf("nil handler: %w", ErrNotAFunction)
+	}
+	if atomic.LoadInt32(&b.bus.closed) != 0 {
+		return ErrBusClosed
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.handlers[topic] = append(b.handlers[topic], handler)
+	return nil
+}
+
+// HasCallback returns true if exists any callback subscribed to the topic.
+func (b *TypedBus[T]) HasCallback(topic string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.handlers[topic]) > 0
+}
+
+// Unsubscribe removes callback defined for a topic, errors are those of EventBus.Unsubscribe.
+// Functions are told apart by their code pointer, the only use of reflection by the typed bus.
+func (b *TypedBus[T]) Unsubscribe(topic string, fn func(event T)) error {
+	pointer := reflect.ValueOf(fn).Pointer()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(b.handlers[topic]) == 0 {
+		return fmt.Errorf("topic %s: %w", topic, ErrTopicNotFound)
+	}
+	for _, handler := range b.handlers[topic] {
+		if reflect.ValueOf(handler.fn).Pointer() == pointer {
+			b.removeLocked(topic, handler)
+			return nil
+		}
+	}
+	return handlerNotFound(topic, fn)
+}
+
+// removeLocked removes the handler, reporting false when it is gone already
+func (b *TypedBus[T]) removeLocked(topic string, handler *typedHandler[T]) bool {
+	handlers := b.handlers[topic]
+	for i, h := range handlers {
+		if h == handler {
+			copied := make([]*typedHandler[T], 0, len(handlers)-1)
+			copied = append(append(copied, handlers[:i]...), handlers[i+1:]...)
+			if len(copied) == 0 {
+				delete(b.handlers, topic)
+			} else {
+				b.handlers[topic] = copied
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Publish delivers event to the handlers of the topic. Unlike EventBus, the handlers are called
+// without the lock held, so they may publish and subscribe.
+func (b *TypedBus[T]) Publish(topic string, event T) {
+	b.lock.Lock()
+	handlers := b.handlers[topic] // never modified in place, see removeLocked
+	b.lock.Unlock()
+	for _, handler := range handlers {
+		if handler.flagOnce {
+			b.lock.Lock()
+			removed := b.removeLocked(topic, handler)
+			b.lock.Unlock()
+			if !removed {
+				continue // claimed by a concurrent Publish
+			}
+		}
+		if !handler.async {
+			handler.fn(event)
+			continue
+		}
+		b.bus.wg.Add(1)
+		if handler.transactional {
+			handler.Lock()
+		}
+		go func(handler *typedHandler[T]) {
+			defer b.bus.wg.Done()
+			if handler.transactional {
+				defer handler.Unlock()
+			}
+			handler.fn(event)
+		}(handler)
+	}
+}
+
+// WaitAsync waits for the async callbacks of the typed bus and of its EventBus to complete
+func (b *TypedBus[T]) WaitAsync() {
+	b.bus.WaitAsync()
+}
@@ -0,0 +1,84 @@
+//go:build go1.18
+// +build go1.18
+
+package EventBus
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type typedOrder struct {
+	ID     string
+	Amount int
+}
+
+func TestTypedBus(t *testing.T) {
+	bus := New().(*EventBus)
+	orders := NewTypedBus[typedOrder](bus)
+	var total, once int
+	var async, asyncOnce int32
+	record := func(order typedOrder) { total += order.Amount }
+	orders.Subscribe("order", record)
+	orders.SubscribeOnce("order", func(order typedOrder) { once++ })
+	orders.SubscribeAsync("order", func(order typedOrder) { atomic.AddInt32(&async, 1) }, true)
+	orders.SubscribeOnceAsync("order", func(order typedOrder) { atomic.AddInt32(&asyncOnce, 1) })
+
+	orders.Publish("order", typedOrder{"o-1", 5})
+	orders.Publish("order", typedOrder{"o-2", 7})
+	bus.WaitAsync()
+	if total != 12 || once != 1 || async != 2 || asyncOnce != 1 {
+		t.Fatal(total, once, async, asyncOnce)
+	}
+
+	if err := orders.Unsubscribe("order", record); err != nil {
+		t.Fatal(err)
+	}
+	var notFound *ErrHandlerNotFound
+	if err := orders.Unsubscribe("order", record); !errors.As(err, &notFound) {
+		t.Fatal(err)
+	}
+	if err := orders.Unsubscribe("none", record); !errors.Is(err, ErrTopicNotFound) {
+		t.Fatal(err)
+	}
+	orders.Publish("order", typedOrder{"o-3", 1})
+	orders.WaitAsync()
+	if total != 12 || async != 3 {
+		t.Fatal(total, async)
+	}
+}
+
+func TestTypedBusNestedPublish(t *testing.T) {
+	bus := New().(*EventBus)
+	events := NewTypedBus[string](bus)
+	var got []string
+	events.Subscribe("ping", func(s string) { events.Publish("pong", s+"!") })
+	events.Subscribe("pong", func(s string) { got = append(got, s) })
+	events.Publish("ping", "hi")
+	if len(got) != 1 || got[0] != "hi!" {
+		t.Fatal(got)
+	}
+}
+
+func TestTypedBusClosed(t *testing.T) {
+	bus := New().(*EventBus)
+	events := NewTypedBus[int](bus)
+	bus.Close()
+	if err := events.Subscribe("n", func(int) {}); !errors.Is(err, ErrBusClosed) {
+		t.Fatal(err)
+	}
+	if err := events.Subscribe("n", nil); !errors.Is(err, ErrNotAFunction) {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkTypedBusPublish(b *testing.B) {
+	events := NewTypedBus[int](New().(*EventBus))
+	var sum int
+	events.Subscribe("n", func(n int) { sum += n })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		events.Publish("n", i)
+	}
+}
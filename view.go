@@ -0,0 +1,56 @@
+package EventBus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPublishOnly - returned when subscribing or unsubscribing through a PublishOnly view
+var ErrPublishOnly = errors.New("bus view is publish only")
+
+// readOnlyBus - view of a bus forbidding Publish
+type readOnlyBus struct {
+	Bus
+}
+
+// Publish panics, the view is read only.
+func (view readOnlyBus) Publish(topic string, args ...interface{}) {
+	panic(fmt.Errorf("topic %s: bus view is read only", topic))
+}
+
+// publishOnlyBus - view of a bus forbidding Subscribe and Unsubscribe
+type publishOnlyBus struct {
+	Bus
+}
+
+func (view publishOnlyBus) Subscribe(topic string, fn interface{}) error {
+	return ErrPublishOnly
+}
+
+func (view publishOnlyBus) SubscribeAsync(topic string, fn interface{}, transactional bool) error {
+	return ErrPublishOnly
+}
+
+func (view publishOnlyBus) SubscribeOnce(topic string, fn interface{}) error {
+	return ErrPublishOnly
+}
+
+func (view publishOnlyBus) SubscribeOnceAsync(topic string, fn interface{}) error {
+	return ErrPublishOnly
+}
+
+func (view publishOnlyBus) Unsubscribe(topic string, handler interface{}) error {
+	return ErrPublishOnly
+}
+
+// ReadOnly returns a view of the bus which can subscribe and unsubscribe but panics on Publish,
+// to hand to plugins which must only consume events.
+func (bus *EventBus) ReadOnly() Bus {
+	return readOnlyBus{bus}
+}
+
+// PublishOnly returns a view of the bus which can publish but returns ErrPublishOnly from
+// Subscribe and Unsubscribe, to hand to plugins which must only produce events.
+func (bus *EventBus) PublishOnly() Bus {
+	return publishOnlyBus{bus}
+}
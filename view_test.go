@@ -0,0 +1,41 @@
+package EventBus
+
+import (
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	bus := New().(*EventBus)
+	view := bus.ReadOnly()
+	count := 0
+	if view.Subscribe("topic", func() { count++ }) != nil || !view.HasCallback("topic") {
+		t.Fail()
+	}
+	bus.Publish("topic")
+	if count != 1 {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	view.Publish("topic")
+}
+
+func TestPublishOnly(t *testing.T) {
+	bus := New().(*EventBus)
+	view := bus.PublishOnly()
+	count := 0
+	handler := func() { count++ }
+	bus.Subscribe("topic", handler)
+	view.Publish("topic")
+	if count != 1 {
+		t.Fail()
+	}
+	if view.Subscribe("topic", handler) != ErrPublishOnly || view.SubscribeAsync("topic", handler, false) != ErrPublishOnly ||
+		view.SubscribeOnce("topic", handler) != ErrPublishOnly || view.SubscribeOnceAsync("topic", handler) != ErrPublishOnly ||
+		view.Unsubscribe("topic", handler) != ErrPublishOnly {
+		t.Fail()
+	}
+}
@@ -0,0 +1,81 @@
+package EventBus
+
+import (
+	"context"
+)
+
+// Once returns a channel receiving the arguments of the next event published to the topic.
+// The channel is closed without a value if ctx is done first, in which case
+// the underlying subscription is removed so nothing is leaked.
+// Returns ErrSealed on a sealed bus.
+func (bus *EventBus) Once(ctx context.Context, topic string) (<-chan []interface{}, error) {
+	ch := make(chan []interface{}, 1)
+	fired := make(chan struct{})
+	fn := func(args ...interface{}) {
+		ch <- args
+		close(ch)
+		close(fired)
+	}
+	handler, err := bus.subscribeHandler(topic, fn, true, false, false)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-fired:
+		case <-ctx.Done():
+			// the handler may have been claimed by a Publish already, it will then deliver the event
+			if bus.removeHandlerPtr(topic, handler) {
+				close(ch)
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// WaitForEvent blocks until the next event is published to the topic or ctx is done.
+// The event comes with its ID, sequence number, publish time and headers.
+// Returns ctx.Err() if no event arrived in time, ErrSealed on a sealed bus.
+func (bus *EventBus) WaitForEvent(ctx context.Context, topic string) (Event, error) {
+	received := make(chan *Event, 1)
+	handler, err := bus.subscribeHandler(topic, func(event *Event) { received <- event }, true, false, false)
+	if err != nil {
+		return Event{}, err
+	}
+	select {
+	case event := <-received:
+		return *event, nil
+	case <-ctx.Done():
+		if bus.removeHandlerPtr(topic, handler) {
+			return Event{}, ctx.Err()
+		}
+		// claimed by a Publish already, which delivers the event
+		return *<-received, nil
+	}
+}
+
+// WaitUntil blocks until an event satisfying predicate is published to the topic or ctx is done.
+// The predicate runs as a synchronous handler, so it must not publish or subscribe itself.
+// Returns ctx.Err() if no matching event arrived in time, ErrSealed on a sealed bus.
+func (bus *EventBus) WaitUntil(ctx context.Context, topic string, predicate func(args ...interface{}) bool) error {
+	matched := make(chan struct{}, 1)
+	fn := func(args ...interface{}) {
+		if predicate(args...) {
+			select {
+			case matched <- struct{}{}:
+			default:
+			}
+		}
+	}
+	handler, err := bus.subscribeHandler(topic, fn, false, false, false)
+	if err != nil {
+		return err
+	}
+	defer bus.removeHandlerPtr(topic, handler)
+	select {
+	case <-matched:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
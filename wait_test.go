@@ -0,0 +1,124 @@
+package EventBus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWaitForEvent(t *testing.T) {
+	bus := NewWithOptions(WithIDGenerator(func() string { return "id-1" })).(*EventBus)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bus.PublishWithHeaders("topic", Headers{"Tenant": "acme"}, 10, nil)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := bus.WaitForEvent(ctx, "topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Topic != "topic" || len(event.Args) != 2 || event.Args[0] != 10 || event.Args[1] != nil {
+		t.Fail()
+	}
+	if event.ID != "id-1" || event.Seq != 1 || event.Published.IsZero() || event.Headers["Tenant"] != "acme" {
+		t.Fatal(event)
+	}
+	if bus.HasCallback("topic") {
+		t.Fail()
+	}
+}
+
+func TestWaitForEventTimeout(t *testing.T) {
+	bus := New().(*EventBus)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := bus.WaitForEvent(ctx, "topic"); err != context.DeadlineExceeded {
+		t.Fail()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if bus.HasCallback("topic") {
+		t.Fail()
+	}
+}
+
+func TestOnceDoesNotRemoveOtherWaiters(t *testing.T) {
+	bus := New().(*EventBus)
+	ctx, cancel := context.WithCancel(context.Background())
+	first, _ := bus.Once(ctx, "topic")
+	second, _ := bus.Once(context.Background(), "topic")
+	cancel()
+	if _, ok := <-first; ok {
+		t.Fail()
+	}
+	bus.Publish("topic", "value")
+	if args := <-second; len(args) != 1 || args[0] != "value" {
+		t.Fail()
+	}
+}
+
+func TestOnceCancelledWhileClaimed(t *testing.T) {
+	bus := New().(*EventBus)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, _ := bus.Once(ctx, "topic")
+	// a Publish claimed the handler and is about to call it when ctx is cancelled
+	handler := bus.handlers["topic"][0]
+	if !handler.claim() {
+		t.Fatal("handler claimed already")
+	}
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-ch:
+		t.Fatal("channel closed under a claimed handler")
+	default:
+	}
+	handler.callBack.Call([]reflect.Value{reflect.ValueOf("value")})
+	if args := <-ch; len(args) != 1 || args[0] != "value" {
+		t.Fatal(args)
+	}
+
+	for i := 0; i < 200; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, _ := bus.Once(ctx, "race")
+		go cancel()
+		bus.Publish("race", i)
+		for range ch {
+		}
+	}
+}
+
+func TestWaitUntil(t *testing.T) {
+	bus := New().(*EventBus)
+	go func() {
+		for i := 0; i < 5; i++ {
+			time.Sleep(time.Millisecond)
+			bus.Publish("status", i)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := bus.WaitUntil(ctx, "status", func(args ...interface{}) bool {
+		return args[0].(int) == 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bus.HasCallback("status") {
+		t.Fail()
+	}
+}
+
+func TestWaitUntilTimeout(t *testing.T) {
+	bus := New().(*EventBus)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	bus.Publish("status", "starting")
+	if bus.WaitUntil(ctx, "status", func(args ...interface{}) bool { return false }) != context.DeadlineExceeded {
+		t.Fail()
+	}
+	if bus.HasCallback("status") {
+		t.Fail()
+	}
+}
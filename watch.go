@@ -0,0 +1,124 @@
+package EventBus
+
+import (
+	"strings"
+	"sync"
+)
+
+// KV - state of a topic delivered by Watch
+type KV struct {
+	Key   string
+	Value interface{}
+}
+
+// watcherSet - watchers of the state topics
+type watcherSet struct {
+	lock     sync.Mutex
+	watchers []*watcher
+}
+
+// watcher - state changes queued for a Watch channel, so SetState never waits for a slow reader
+type watcher struct {
+	prefix  string
+	ch      chan KV
+	lock    sync.Mutex
+	cond    *sync.Cond
+	pending []KV
+	seen    map[string]bool // topics whose current state was queued already
+	stopped bool
+}
+
+// Watch returns a channel receiving the current state of every state topic starting with prefix,
+// then every change of those topics, see SetState. The changes of a topic arrive in order. The
+// returned function stops watching and closes the channel.
+func (bus *EventBus) Watch(prefix string) (<-chan KV, func()) {
+	w := &watcher{prefix: prefix, ch: make(chan KV), seen: make(map[string]bool)}
+	w.cond = sync.NewCond(&w.lock)
+	bus.watchers.lock.Lock()
+	bus.watchers.watchers = append(bus.watchers.watchers, w)
+	bus.watchers.lock.Unlock()
+
+	bus.states.Range(func(key, value interface{}) bool {
+		topic, state := key.(string), value.(*topicState)
+		if strings.HasPrefix(topic, prefix) {
+			// changes made since the watcher was added are queued already, they are newer
+			state.lock.Lock()
+			if state.set {
+				w.queue(topic, state.value, false)
+			}
+			state.lock.Unlock()
+		}
+		return true
+	})
+	go w.pump()
+
+	var once sync.Once
+	return w.ch, func() {
+		once.Do(func() {
+			bus.watchers.remove(w)
+			w.stop()
+		})
+	}
+}
+
+// notify queues a change of a state topic for its watchers, the state lock of the topic must be held
+func (set *watcherSet) notify(topic string, value interface{}) {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	for _, w := range set.watchers {
+		if strings.HasPrefix(topic, w.prefix) {
+			w.queue(topic, value, true)
+		}
+	}
+}
+
+func (set *watcherSet) remove(w *watcher) {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	for i, candidate := range set.watchers {
+		if candidate == w {
+			set.watchers = append(set.watchers[:i], set.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+// queue adds a state to deliver, the current state of a topic only when no change of it was queued
+func (w *watcher) queue(topic string, value interface{}, change bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !change && w.seen[topic] {
+		return
+	}
+	w.seen[topic] = true
+	w.pending = append(w.pending, KV{topic, value})
+	w.cond.Signal()
+}
+
+func (w *watcher) pump() {
+	defer close(w.ch)
+	for {
+		w.lock.Lock()
+		for len(w.pending) == 0 && !w.stopped {
+			w.cond.Wait()
+		}
+		if w.stopped {
+			w.lock.Unlock()
+			return
+		}
+		kv := w.pending[0]
+		w.pending = w.pending[1:]
+		w.lock.Unlock()
+		w.ch <- kv
+	}
+}
+
+func (w *watcher) stop() {
+	w.lock.Lock()
+	w.stopped = true
+	w.cond.Signal()
+	w.lock.Unlock()
+	// unblock the pump if it is handing over a value nobody reads anymore
+	for range w.ch {
+	}
+}
@@ -0,0 +1,51 @@
+package EventBus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	bus := New().(*EventBus)
+	bus.SetState("config/workers", 4)
+	bus.SetState("other/workers", 1)
+	ch, stop := bus.Watch("config/")
+	defer stop()
+	bus.SetState("config/workers", 8)
+	bus.SetState("config/workers", 8)
+	bus.SetState("other/workers", 2)
+	bus.SetState("config/timeout", "5s")
+
+	expected := []KV{{"config/workers", 4}, {"config/workers", 8}, {"config/timeout", "5s"}}
+	for _, want := range expected {
+		select {
+		case kv := <-ch:
+			if kv != want {
+				t.Fatal(kv)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("missing", want)
+		}
+	}
+}
+
+func TestWatchStop(t *testing.T) {
+	bus := New().(*EventBus)
+	ch, stop := bus.Watch("")
+	bus.SetState("a", 1)
+	bus.SetState("b", 2)
+	stop()
+	stop()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("channel not closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed")
+	}
+	bus.SetState("a", 3)
+	if len(bus.watchers.watchers) != 0 {
+		t.Fail()
+	}
+}
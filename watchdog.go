@@ -0,0 +1,77 @@
+package EventBus
+
+import (
+	"sync"
+	"time"
+)
+
+// MissedTopic - topic a Missed is published to when a topic watched by ExpectWithin stays silent
+const MissedTopic = "bus:missed"
+
+// Missed - a topic received no event within the expected duration
+type Missed struct {
+	Topic    string
+	Last     time.Time // publish time of the last event, when the watch started if none arrived
+	Expected time.Duration
+}
+
+// watchdog - timer of a topic expected to receive events, restarted by every event
+type watchdog struct {
+	bus       *EventBus
+	topic     string
+	within    time.Duration
+	onMissing func()
+	lock      sync.Mutex
+	last      time.Time
+	timer     *time.Timer
+	stopped   bool
+}
+
+// ExpectWithin calls onMissing, when not nil, and publishes a Missed to MissedTopic whenever
+// the topic receives no event for d, detecting dead producers without timer code in every
+// consumer. The watch re-arms itself: every event restarts the countdown, and so does a miss,
+// so a topic staying silent is reported every d. stop ends the watch.
+func (bus *EventBus) ExpectWithin(topic string, d time.Duration, onMissing func()) (stop func(), err error) {
+	dog := &watchdog{bus: bus, topic: topic, within: d, onMissing: onMissing, last: time.Now()}
+	handler, err := bus.subscribeHandler(topic, dog.seen, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	dog.lock.Lock()
+	dog.timer = time.AfterFunc(d, dog.missed)
+	dog.lock.Unlock()
+	return func() {
+		bus.removeHandlerPtr(topic, handler)
+		dog.lock.Lock()
+		defer dog.lock.Unlock()
+		dog.stopped = true
+		dog.timer.Stop()
+	}, nil
+}
+
+func (dog *watchdog) seen(meta EventMeta, _ ...interface{}) {
+	dog.lock.Lock()
+	defer dog.lock.Unlock()
+	if dog.stopped {
+		return
+	}
+	dog.last = meta.Published
+	dog.timer.Reset(dog.within)
+}
+
+func (dog *watchdog) missed() {
+	dog.lock.Lock()
+	if dog.stopped || time.Since(dog.last) < dog.within {
+		// an event arrived while the timer fired
+		dog.lock.Unlock()
+		return
+	}
+	missed := Missed{Topic: dog.topic, Last: dog.last, Expected: dog.within}
+	dog.timer.Reset(dog.within)
+	dog.lock.Unlock()
+
+	if dog.onMissing != nil {
+		dog.onMissing()
+	}
+	dog.bus.Publish(MissedTopic, missed)
+}
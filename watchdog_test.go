@@ -0,0 +1,39 @@
+package EventBus
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExpectWithin(t *testing.T) {
+	bus := New().(*EventBus)
+	var calls int32
+	missed := make(chan Missed, 10)
+	bus.Subscribe(MissedTopic, func(m Missed) { missed <- m })
+	stop, err := bus.ExpectWithin("heartbeat", 50*time.Millisecond, func() { atomic.AddInt32(&calls, 1) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		bus.Publish("heartbeat", i)
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatal("missed while events arrived", n)
+	}
+
+	first := <-missed
+	second := <-missed
+	if first.Topic != "heartbeat" || first.Expected != 50*time.Millisecond || second.Last != first.Last {
+		t.Fatal(first, second)
+	}
+	stop()
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n < 2 || n != int32(2+len(missed)) {
+		t.Fatal(n, len(missed))
+	}
+	if bus.HasCallback("heartbeat") {
+		t.Fatal("still subscribed after stop")
+	}
+}